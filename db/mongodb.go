@@ -98,8 +98,10 @@ func (m *MongoDB) Collections() []string {
 	return []string{
 		"companies",
 		"questionnaires",
+		"questionnaire_versions",
 		"company_questionnaires",
 		"user_questionnaire_assignments",
 		"users_metadata",
+		"anonymous_responses",
 	}
 }
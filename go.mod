@@ -11,6 +11,7 @@ require (
 	github.com/lib/pq v1.10.9
 	github.com/pressly/goose/v3 v3.17.0
 	go.mongodb.org/mongo-driver v1.13.1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
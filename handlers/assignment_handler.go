@@ -125,9 +125,65 @@ func (h *AssignmentHandler) GetAssignmentByID(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	// Only the respondent may see their answers and timing on an anonymous questionnaire
+	if assignment.UserID != claims.Sub {
+		if err := h.service.RedactAnonymousAssignments(r.Context(), []*models.UserQuestionnaireAssignment{assignment}); err != nil {
+			utils.HandleRepositoryError(w, err)
+			return
+		}
+	}
+
 	utils.RespondWithSuccess(w, http.StatusOK, assignment, "")
 }
 
+// GetAssignmentProgress handles GET /api/v1/assignments/:id/progress
+func (h *AssignmentHandler) GetAssignmentProgress(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := utils.ValidateObjectID(idStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	claims, _ := middleware.GetUserFromContext(r.Context())
+	isSuperAdmin := middleware.IsSuperAdmin(r.Context())
+
+	progress, err := h.service.GetAssignmentProgress(r.Context(), id, claims.Sub, isSuperAdmin)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, progress, "")
+}
+
+// GetAssignmentPage handles GET /api/v1/assignments/:id/sections/:section_id
+func (h *AssignmentHandler) GetAssignmentPage(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := utils.ValidateObjectID(idStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	sectionID := chi.URLParam(r, "section_id")
+	if sectionID == "" {
+		utils.BadRequest(w, "section_id is required")
+		return
+	}
+
+	claims, _ := middleware.GetUserFromContext(r.Context())
+	isSuperAdmin := middleware.IsSuperAdmin(r.Context())
+
+	page, err := h.service.GetAssignmentPage(r.Context(), id, sectionID, claims.Sub, isSuperAdmin)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, page, "")
+}
+
 // GetMyCompanyQuestionnaires handles GET /api/v1/my-company/questionnaires
 func (h *AssignmentHandler) GetMyCompanyQuestionnaires(w http.ResponseWriter, r *http.Request) {
 	claims, _ := middleware.GetUserFromContext(r.Context())
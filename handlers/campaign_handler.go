@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"questionarie-service/middleware"
+	"questionarie-service/models"
+	"questionarie-service/services"
+	"questionarie-service/utils"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CampaignHandler handles recurring campaign HTTP requests
+type CampaignHandler struct {
+	service *services.CampaignService
+}
+
+// NewCampaignHandler creates a new CampaignHandler
+func NewCampaignHandler(service *services.CampaignService) *CampaignHandler {
+	return &CampaignHandler{
+		service: service,
+	}
+}
+
+// CreateCampaign handles POST /api/v1/companies/:company_id/campaigns
+func (h *CampaignHandler) CreateCampaign(w http.ResponseWriter, r *http.Request) {
+	companyIDStr := chi.URLParam(r, "company_id")
+	companyID, err := utils.ValidateObjectID(companyIDStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	var req struct {
+		QuestionnaireID              string                   `json:"questionnaire_id"`
+		SourceCompanyQuestionnaireID string                   `json:"source_company_questionnaire_id"`
+		Name                         string                   `json:"name"`
+		Schedule                     models.CampaignSchedule  `json:"schedule"`
+		Audience                     *models.CampaignAudience `json:"audience"`
+		StartsAt                     string                   `json:"starts_at"`
+		EndsAt                       string                   `json:"ends_at"`
+		IsAnonymous                  bool                     `json:"is_anonymous"`
+		MinGroupSize                 int                      `json:"min_group_size"`
+	}
+
+	if err := utils.ParseRequestBody(r, &req); err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	input := services.CampaignInput{
+		Name:         req.Name,
+		Schedule:     req.Schedule,
+		Audience:     req.Audience,
+		IsAnonymous:  req.IsAnonymous,
+		MinGroupSize: req.MinGroupSize,
+	}
+
+	if req.SourceCompanyQuestionnaireID != "" {
+		if input.SourceCompanyQuestionnaireID, err = utils.ValidateObjectID(req.SourceCompanyQuestionnaireID); err != nil {
+			utils.BadRequest(w, "invalid source_company_questionnaire_id: "+err.Error())
+			return
+		}
+	} else if input.QuestionnaireID, err = utils.ValidateObjectID(req.QuestionnaireID); err != nil {
+		utils.BadRequest(w, "invalid questionnaire_id: "+err.Error())
+		return
+	}
+
+	if input.StartsAt, err = parseCampaignTime(req.StartsAt, req.Schedule.Timezone); err != nil {
+		utils.BadRequest(w, "invalid starts_at format (use YYYY-MM-DD or RFC 3339)")
+		return
+	}
+
+	if req.EndsAt != "" {
+		endsAt, err := parseCampaignTime(req.EndsAt, req.Schedule.Timezone)
+		if err != nil {
+			utils.BadRequest(w, "invalid ends_at format (use YYYY-MM-DD or RFC 3339)")
+			return
+		}
+		input.EndsAt = &endsAt
+	}
+
+	claims, _ := middleware.GetUserFromContext(r.Context())
+	campaign, err := h.service.CreateCampaign(r.Context(), companyID, input, claims.Sub)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusCreated, campaign, "Campaign created successfully")
+}
+
+// GetCompanyCampaigns handles GET /api/v1/companies/:company_id/campaigns
+func (h *CampaignHandler) GetCompanyCampaigns(w http.ResponseWriter, r *http.Request) {
+	companyIDStr := chi.URLParam(r, "company_id")
+	companyID, err := utils.ValidateObjectID(companyIDStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	campaigns, err := h.service.GetCompanyCampaigns(r.Context(), companyID)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, campaigns, "")
+}
+
+// GetCampaign handles GET /api/v1/campaigns/:id
+func (h *CampaignHandler) GetCampaign(w http.ResponseWriter, r *http.Request) {
+	id, ok := campaignIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	campaign, err := h.service.GetCampaign(r.Context(), id)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, campaign, "")
+}
+
+// UpdateCampaign handles PUT /api/v1/campaigns/:id
+func (h *CampaignHandler) UpdateCampaign(w http.ResponseWriter, r *http.Request) {
+	id, ok := campaignIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Name         *string                  `json:"name"`
+		Schedule     *models.CampaignSchedule `json:"schedule"`
+		Audience     *models.CampaignAudience `json:"audience"`
+		EndsAt       string                   `json:"ends_at"`
+		IsAnonymous  *bool                    `json:"is_anonymous"`
+		MinGroupSize *int                     `json:"min_group_size"`
+		IsActive     *bool                    `json:"is_active"`
+	}
+
+	if err := utils.ParseRequestBody(r, &req); err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	update := services.CampaignUpdate{
+		Name:         req.Name,
+		Schedule:     req.Schedule,
+		Audience:     req.Audience,
+		IsAnonymous:  req.IsAnonymous,
+		MinGroupSize: req.MinGroupSize,
+		IsActive:     req.IsActive,
+	}
+
+	if req.EndsAt != "" {
+		timezone := ""
+		if req.Schedule != nil {
+			timezone = req.Schedule.Timezone
+		}
+		endsAt, err := parseCampaignTime(req.EndsAt, timezone)
+		if err != nil {
+			utils.BadRequest(w, "invalid ends_at format (use YYYY-MM-DD or RFC 3339)")
+			return
+		}
+		update.EndsAt = &endsAt
+	}
+
+	campaign, err := h.service.UpdateCampaign(r.Context(), id, update)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, campaign, "Campaign updated successfully")
+}
+
+// GetCampaignPeriods handles GET /api/v1/campaigns/:id/periods
+func (h *CampaignHandler) GetCampaignPeriods(w http.ResponseWriter, r *http.Request) {
+	id, ok := campaignIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	periods, err := h.service.GetCampaignPeriods(r.Context(), id)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, periods, "")
+}
+
+// RunDueCampaigns handles POST /api/v1/campaigns/run
+func (h *CampaignHandler) RunDueCampaigns(w http.ResponseWriter, r *http.Request) {
+	created, err := h.service.RunDueCampaigns(r.Context(), time.Now())
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, created, fmt.Sprintf("%d campaign periods created", len(created)))
+}
+
+// campaignIDParam reads the campaign ID from the URL, answering 400 when it is not valid
+func campaignIDParam(w http.ResponseWriter, r *http.Request) (primitive.ObjectID, bool) {
+	id, err := utils.ValidateObjectID(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return primitive.NilObjectID, false
+	}
+	return id, true
+}
+
+// parseCampaignTime parses an RFC 3339 time, or a date taken as midnight in the schedule's time zone
+func parseCampaignTime(value, timezone string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return time.ParseInLocation("2006-01-02", value, loc)
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"net/http"
 	"questionarie-service/middleware"
+	"questionarie-service/models"
 	"questionarie-service/services"
 	"questionarie-service/utils"
 	"strconv"
@@ -85,7 +86,9 @@ func (h *CompanyHandler) UpdateCompany(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		Name string `json:"name"`
+		Name           string                 `json:"name"`
+		MinGroupSize   *int                   `json:"min_group_size"`
+		ReminderPolicy *models.ReminderPolicy `json:"reminder_policy"`
 	}
 
 	if err := utils.ParseRequestBody(r, &req); err != nil {
@@ -93,7 +96,7 @@ func (h *CompanyHandler) UpdateCompany(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.UpdateCompany(r.Context(), id, req.Name); err != nil {
+	if err := h.service.UpdateCompany(r.Context(), id, req.Name, req.MinGroupSize, req.ReminderPolicy); err != nil {
 		utils.HandleRepositoryError(w, err)
 		return
 	}
@@ -114,6 +117,8 @@ func (h *CompanyHandler) AssignQuestionnaireToCompany(w http.ResponseWriter, r *
 		QuestionnaireID string `json:"questionnaire_id"`
 		PeriodStart     string `json:"period_start"`
 		PeriodEnd       string `json:"period_end"`
+		IsAnonymous     bool   `json:"is_anonymous"`
+		MinGroupSize    int    `json:"min_group_size"`
 	}
 
 	if err := utils.ParseRequestBody(r, &req); err != nil {
@@ -140,7 +145,7 @@ func (h *CompanyHandler) AssignQuestionnaireToCompany(w http.ResponseWriter, r *
 	}
 
 	claims, _ := middleware.GetUserFromContext(r.Context())
-	cq, err := h.service.AssignQuestionnaireToCompany(r.Context(), companyID, questionnaireID, claims.Sub, periodStart, periodEnd)
+	cq, err := h.service.AssignQuestionnaireToCompany(r.Context(), companyID, questionnaireID, claims.Sub, periodStart, periodEnd, req.IsAnonymous, req.MinGroupSize)
 	if err != nil {
 		utils.HandleRepositoryError(w, err)
 		return
@@ -179,9 +184,11 @@ func (h *CompanyHandler) UpdateCompanyQuestionnaire(w http.ResponseWriter, r *ht
 	}
 
 	var req struct {
-		PeriodStart string `json:"period_start"`
-		PeriodEnd   string `json:"period_end"`
-		IsActive    *bool  `json:"is_active"`
+		PeriodStart  string `json:"period_start"`
+		PeriodEnd    string `json:"period_end"`
+		IsActive     *bool  `json:"is_active"`
+		IsAnonymous  *bool  `json:"is_anonymous"`
+		MinGroupSize *int   `json:"min_group_size"`
 	}
 
 	if err := utils.ParseRequestBody(r, &req); err != nil {
@@ -207,12 +214,7 @@ func (h *CompanyHandler) UpdateCompanyQuestionnaire(w http.ResponseWriter, r *ht
 		}
 	}
 
-	isActive := true
-	if req.IsActive != nil {
-		isActive = *req.IsActive
-	}
-
-	if err := h.service.UpdateCompanyQuestionnaire(r.Context(), id, periodStart, periodEnd, isActive); err != nil {
+	if err := h.service.UpdateCompanyQuestionnaire(r.Context(), id, periodStart, periodEnd, req.IsActive, req.IsAnonymous, req.MinGroupSize); err != nil {
 		utils.HandleRepositoryError(w, err)
 		return
 	}
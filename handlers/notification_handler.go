@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+	"questionarie-service/models"
+	"questionarie-service/services"
+	"questionarie-service/utils"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// NotificationHandler handles notification outbox HTTP requests
+type NotificationHandler struct {
+	service *services.NotificationService
+}
+
+// NewNotificationHandler creates a new NotificationHandler
+func NewNotificationHandler(service *services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		service: service,
+	}
+}
+
+// GetCompanyNotifications handles GET /api/v1/companies/:company_id/notifications?status=&user_id=
+func (h *NotificationHandler) GetCompanyNotifications(w http.ResponseWriter, r *http.Request) {
+	companyIDStr := chi.URLParam(r, "company_id")
+	companyID, err := utils.ValidateObjectID(companyIDStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	statusStr := r.URL.Query().Get("status")
+	var status *models.NotificationStatus
+	if statusStr != "" {
+		if err := utils.ValidateNotificationStatus(statusStr); err != nil {
+			utils.BadRequest(w, err.Error())
+			return
+		}
+		s := models.NotificationStatus(statusStr)
+		status = &s
+	}
+
+	notifications, err := h.service.GetCompanyNotifications(r.Context(), companyID, status, r.URL.Query().Get("user_id"))
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, notifications, "")
+}
+
+// RetryNotification handles POST /api/v1/notifications/:id/retry
+func (h *NotificationHandler) RetryNotification(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := utils.ValidateObjectID(idStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	notification, err := h.service.RetryNotification(r.Context(), id)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, notification, "Notification queued for delivery")
+}
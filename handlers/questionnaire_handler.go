@@ -1,14 +1,20 @@
 package handlers
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"questionarie-service/middleware"
 	"questionarie-service/models"
 	"questionarie-service/services"
 	"questionarie-service/utils"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
+	"gopkg.in/yaml.v3"
 )
 
 // QuestionnaireHandler handles questionnaire-related HTTP requests
@@ -45,13 +51,76 @@ func (h *QuestionnaireHandler) CreateQuestionnaire(w http.ResponseWriter, r *htt
 	utils.RespondWithSuccess(w, http.StatusCreated, questionnaire, "Questionnaire created successfully")
 }
 
+// CloneQuestionnaire handles POST /api/v1/questionnaires/:id/clone
+func (h *QuestionnaireHandler) CloneQuestionnaire(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := utils.ValidateObjectID(idStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	// Body is optional
+	var req struct {
+		Title   string `json:"title"`
+		Version int    `json:"version"`
+	}
+
+	if r.ContentLength != 0 {
+		if err := utils.ParseRequestBody(r, &req); err != nil {
+			utils.BadRequest(w, err.Error())
+			return
+		}
+	}
+
+	if req.Version < 0 {
+		utils.BadRequest(w, "version must be a positive integer")
+		return
+	}
+
+	claims, _ := middleware.GetUserFromContext(r.Context())
+	questionnaire, err := h.service.CloneQuestionnaire(r.Context(), id, req.Title, req.Version, claims.Sub)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusCreated, questionnaire, "Questionnaire cloned successfully")
+}
+
+// GetQuestionnaireClones handles GET /api/v1/questionnaires/:id/clones
+func (h *QuestionnaireHandler) GetQuestionnaireClones(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := utils.ValidateObjectID(idStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	clones, err := h.service.GetQuestionnaireClones(r.Context(), id)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, clones, "")
+}
+
 // GetQuestionnaires handles GET /api/v1/questionnaires
 func (h *QuestionnaireHandler) GetQuestionnaires(w http.ResponseWriter, r *http.Request) {
 	page, _ := strconv.ParseInt(r.URL.Query().Get("page"), 10, 64)
 	pageSize, _ := strconv.ParseInt(r.URL.Query().Get("page_size"), 10, 64)
 	activeOnly := r.URL.Query().Get("active") == "true"
 
-	questionnaires, err := h.service.GetAllQuestionnaires(r.Context(), page, pageSize, activeOnly)
+	status := r.URL.Query().Get("status")
+	if status != "" {
+		if err := utils.ValidateQuestionnaireStatus(status); err != nil {
+			utils.BadRequest(w, err.Error())
+			return
+		}
+	}
+
+	questionnaires, err := h.service.GetAllQuestionnaires(r.Context(), page, pageSize, activeOnly, models.QuestionnaireStatus(status))
 	if err != nil {
 		utils.HandleRepositoryError(w, err)
 		return
@@ -98,12 +167,12 @@ func (h *QuestionnaireHandler) UpdateQuestionnaire(w http.ResponseWriter, r *htt
 		return
 	}
 
-	isActive := true
 	if req.IsActive != nil {
-		isActive = *req.IsActive
+		utils.BadRequest(w, "is_active cannot be changed directly; use the publish, revise, archive and restore endpoints")
+		return
 	}
 
-	if err := h.service.UpdateQuestionnaire(r.Context(), id, req.Title, req.Description, isActive); err != nil {
+	if err := h.service.UpdateQuestionnaire(r.Context(), id, req.Title, req.Description); err != nil {
 		utils.HandleRepositoryError(w, err)
 		return
 	}
@@ -120,7 +189,8 @@ func (h *QuestionnaireHandler) DeactivateQuestionnaire(w http.ResponseWriter, r
 		return
 	}
 
-	if err := h.service.DeactivateQuestionnaire(r.Context(), id); err != nil {
+	claims, _ := middleware.GetUserFromContext(r.Context())
+	if err := h.service.DeactivateQuestionnaire(r.Context(), id, claims.Sub); err != nil {
 		utils.HandleRepositoryError(w, err)
 		return
 	}
@@ -128,6 +198,65 @@ func (h *QuestionnaireHandler) DeactivateQuestionnaire(w http.ResponseWriter, r
 	utils.RespondWithSuccess(w, http.StatusOK, nil, "Questionnaire deactivated successfully")
 }
 
+// ReviseQuestionnaire handles POST /api/v1/questionnaires/:id/revise
+func (h *QuestionnaireHandler) ReviseQuestionnaire(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := utils.ValidateObjectID(idStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	claims, _ := middleware.GetUserFromContext(r.Context())
+	questionnaire, err := h.service.ReviseQuestionnaire(r.Context(), id, claims.Sub)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, questionnaire, "Questionnaire moved to draft")
+}
+
+// ArchiveQuestionnaire handles POST /api/v1/questionnaires/:id/archive
+func (h *QuestionnaireHandler) ArchiveQuestionnaire(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := utils.ValidateObjectID(idStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	cascade := r.URL.Query().Get("cascade") == "true"
+
+	claims, _ := middleware.GetUserFromContext(r.Context())
+	questionnaire, err := h.service.ArchiveQuestionnaire(r.Context(), id, claims.Sub, cascade)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, questionnaire, "Questionnaire archived successfully")
+}
+
+// RestoreQuestionnaire handles POST /api/v1/questionnaires/:id/restore
+func (h *QuestionnaireHandler) RestoreQuestionnaire(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := utils.ValidateObjectID(idStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	claims, _ := middleware.GetUserFromContext(r.Context())
+	questionnaire, err := h.service.RestoreQuestionnaire(r.Context(), id, claims.Sub)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, questionnaire, "Questionnaire restored to draft")
+}
+
 // AddQuestion handles POST /api/v1/questionnaires/:id/questions
 func (h *QuestionnaireHandler) AddQuestion(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
@@ -138,11 +267,17 @@ func (h *QuestionnaireHandler) AddQuestion(w http.ResponseWriter, r *http.Reques
 	}
 
 	var req struct {
-		QuestionText string                 `json:"question_text"`
-		QuestionType string                 `json:"question_type"`
-		Options      map[string]interface{} `json:"options"`
-		OrderIndex   int                    `json:"order_index"`
-		IsRequired   bool                   `json:"is_required"`
+		QuestionText string                  `json:"question_text"`
+		QuestionType string                  `json:"question_type"`
+		Options      map[string]interface{}  `json:"options"`
+		OrderIndex   int                     `json:"order_index"`
+		IsRequired   bool                    `json:"is_required"`
+		SectionID    string                  `json:"section_id"`
+		Scoring      *models.QuestionScoring `json:"scoring"`
+		Dimensions   []models.DimensionTag   `json:"dimensions"`
+
+		DisplayConditions []models.QuestionCondition `json:"display_conditions"`
+		SkipConditions    []models.QuestionCondition `json:"skip_conditions"`
 	}
 
 	if err := utils.ParseRequestBody(r, &req); err != nil {
@@ -165,8 +300,13 @@ func (h *QuestionnaireHandler) AddQuestion(w http.ResponseWriter, r *http.Reques
 	if req.Options != nil {
 		question.Options = req.Options
 	}
+	question.SectionID = req.SectionID
+	question.Scoring = req.Scoring
+	question.Dimensions = req.Dimensions
+	question.DisplayConditions = req.DisplayConditions
+	question.SkipConditions = req.SkipConditions
 
-	if err := h.service.AddQuestion(r.Context(), id, *question); err != nil {
+	if err := h.service.AddQuestion(r.Context(), id, question); err != nil {
 		utils.HandleRepositoryError(w, err)
 		return
 	}
@@ -190,11 +330,17 @@ func (h *QuestionnaireHandler) UpdateQuestion(w http.ResponseWriter, r *http.Req
 	}
 
 	var req struct {
-		QuestionText string                 `json:"question_text"`
-		QuestionType string                 `json:"question_type"`
-		Options      map[string]interface{} `json:"options"`
-		OrderIndex   int                    `json:"order_index"`
-		IsRequired   bool                   `json:"is_required"`
+		QuestionText string                  `json:"question_text"`
+		QuestionType string                  `json:"question_type"`
+		Options      map[string]interface{}  `json:"options"`
+		OrderIndex   int                     `json:"order_index"`
+		IsRequired   bool                    `json:"is_required"`
+		SectionID    string                  `json:"section_id"`
+		Scoring      *models.QuestionScoring `json:"scoring"`
+		Dimensions   []models.DimensionTag   `json:"dimensions"`
+
+		DisplayConditions []models.QuestionCondition `json:"display_conditions"`
+		SkipConditions    []models.QuestionCondition `json:"skip_conditions"`
 	}
 
 	if err := utils.ParseRequestBody(r, &req); err != nil {
@@ -209,9 +355,15 @@ func (h *QuestionnaireHandler) UpdateQuestion(w http.ResponseWriter, r *http.Req
 		Options:      req.Options,
 		OrderIndex:   req.OrderIndex,
 		IsRequired:   req.IsRequired,
+		SectionID:    req.SectionID,
+		Scoring:      req.Scoring,
+		Dimensions:   req.Dimensions,
+
+		DisplayConditions: req.DisplayConditions,
+		SkipConditions:    req.SkipConditions,
 	}
 
-	if err := h.service.UpdateQuestion(r.Context(), id, questionID, question); err != nil {
+	if err := h.service.UpdateQuestion(r.Context(), id, questionID, &question); err != nil {
 		utils.HandleRepositoryError(w, err)
 		return
 	}
@@ -219,6 +371,38 @@ func (h *QuestionnaireHandler) UpdateQuestion(w http.ResponseWriter, r *http.Req
 	utils.RespondWithSuccess(w, http.StatusOK, nil, "Question updated successfully")
 }
 
+// ReorderQuestions handles PUT /api/v1/questionnaires/:id/questions/order
+func (h *QuestionnaireHandler) ReorderQuestions(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := utils.ValidateObjectID(idStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	var req struct {
+		QuestionIDs []string `json:"question_ids"`
+	}
+
+	if err := utils.ParseRequestBody(r, &req); err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	if len(req.QuestionIDs) == 0 {
+		utils.BadRequest(w, "question_ids cannot be empty")
+		return
+	}
+
+	questionnaire, err := h.service.ReorderQuestions(r.Context(), id, req.QuestionIDs)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, questionnaire, "Questions reordered successfully")
+}
+
 // RemoveQuestion handles DELETE /api/v1/questionnaires/:id/questions/:question_id
 func (h *QuestionnaireHandler) RemoveQuestion(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
@@ -241,3 +425,355 @@ func (h *QuestionnaireHandler) RemoveQuestion(w http.ResponseWriter, r *http.Req
 
 	utils.RespondWithSuccess(w, http.StatusOK, nil, "Question removed successfully")
 }
+
+// UpdateScoreBands handles PUT /api/v1/questionnaires/:id/score-bands
+func (h *QuestionnaireHandler) UpdateScoreBands(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := utils.ValidateObjectID(idStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	var req struct {
+		ScoreBands []models.ScoreBand `json:"score_bands"`
+	}
+
+	if err := utils.ParseRequestBody(r, &req); err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	bands, err := h.service.UpdateScoreBands(r.Context(), id, req.ScoreBands)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, bands, "Score bands updated successfully")
+}
+
+// GetSections handles GET /api/v1/questionnaires/:id/sections
+func (h *QuestionnaireHandler) GetSections(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := utils.ValidateObjectID(idStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	sections, err := h.service.GetSections(r.Context(), id)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, sections, "")
+}
+
+// AddSection handles POST /api/v1/questionnaires/:id/sections
+func (h *QuestionnaireHandler) AddSection(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := utils.ValidateObjectID(idStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	var req struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		OrderIndex  int    `json:"order_index"`
+	}
+
+	if err := utils.ParseRequestBody(r, &req); err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	section := models.NewSection(req.Title, req.Description, req.OrderIndex)
+
+	if err := h.service.AddSection(r.Context(), id, section); err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusCreated, section, "Section added successfully")
+}
+
+// UpdateSection handles PUT /api/v1/questionnaires/:id/sections/:section_id
+func (h *QuestionnaireHandler) UpdateSection(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := utils.ValidateObjectID(idStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	sectionID := chi.URLParam(r, "section_id")
+	if sectionID == "" {
+		utils.BadRequest(w, "section_id is required")
+		return
+	}
+
+	var req struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		OrderIndex  int    `json:"order_index"`
+	}
+
+	if err := utils.ParseRequestBody(r, &req); err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	section := &models.Section{
+		Title:       req.Title,
+		Description: req.Description,
+		OrderIndex:  req.OrderIndex,
+	}
+
+	if err := h.service.UpdateSection(r.Context(), id, sectionID, section); err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, section, "Section updated successfully")
+}
+
+// RemoveSection handles DELETE /api/v1/questionnaires/:id/sections/:section_id
+func (h *QuestionnaireHandler) RemoveSection(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := utils.ValidateObjectID(idStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	sectionID := chi.URLParam(r, "section_id")
+	if sectionID == "" {
+		utils.BadRequest(w, "section_id is required")
+		return
+	}
+
+	if err := h.service.RemoveSection(r.Context(), id, sectionID); err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, nil, "Section removed successfully")
+}
+
+// PublishQuestionnaire handles POST /api/v1/questionnaires/:id/publish
+func (h *QuestionnaireHandler) PublishQuestionnaire(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := utils.ValidateObjectID(idStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	claims, _ := middleware.GetUserFromContext(r.Context())
+	version, err := h.service.PublishQuestionnaire(r.Context(), id, claims.Sub)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusCreated, version, "Questionnaire published successfully")
+}
+
+// GetQuestionnaireVersions handles GET /api/v1/questionnaires/:id/versions
+func (h *QuestionnaireHandler) GetQuestionnaireVersions(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := utils.ValidateObjectID(idStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	versions, err := h.service.GetQuestionnaireVersions(r.Context(), id)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, versions, "")
+}
+
+// GetQuestionnaireVersion handles GET /api/v1/questionnaires/:id/versions/:version
+func (h *QuestionnaireHandler) GetQuestionnaireVersion(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := utils.ValidateObjectID(idStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	version, err := strconv.Atoi(chi.URLParam(r, "version"))
+	if err != nil || version <= 0 {
+		utils.BadRequest(w, "version must be a positive integer")
+		return
+	}
+
+	questionnaireVersion, err := h.service.GetQuestionnaireVersion(r.Context(), id, version)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, questionnaireVersion, "")
+}
+
+// ExportQuestionnaire handles GET /api/v1/questionnaires/:id/export?format=json|yaml&version=N
+func (h *QuestionnaireHandler) ExportQuestionnaire(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := utils.ValidateObjectID(idStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	format, ok := documentFormat(r)
+	if !ok {
+		utils.BadRequest(w, "format must be json or yaml")
+		return
+	}
+
+	version := 0
+	if v := r.URL.Query().Get("version"); v != "" {
+		version, err = strconv.Atoi(v)
+		if err != nil || version <= 0 {
+			utils.BadRequest(w, "version must be a positive integer")
+			return
+		}
+	}
+
+	doc, err := h.service.ExportQuestionnaire(r.Context(), id, version)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	var body []byte
+	if format == "yaml" {
+		body, err = yaml.Marshal(doc)
+	} else {
+		body, err = json.MarshalIndent(doc, "", "  ")
+	}
+	if err != nil {
+		utils.InternalServerError(w, "failed to encode questionnaire")
+		return
+	}
+
+	w.Header().Set("Content-Type", documentContentTypes[format])
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="questionnaire-%s.%s"`, id.Hex(), format))
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// ImportQuestionnaire handles POST /api/v1/questionnaires/import?format=json|yaml&dry_run=true
+func (h *QuestionnaireHandler) ImportQuestionnaire(w http.ResponseWriter, r *http.Request) {
+	doc, ok := parseQuestionnaireDocument(w, r)
+	if !ok {
+		return
+	}
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+
+	claims, _ := middleware.GetUserFromContext(r.Context())
+	questionnaire, err := h.service.ImportQuestionnaire(r.Context(), doc, claims.Sub, dryRun)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	if dryRun {
+		utils.RespondWithSuccess(w, http.StatusOK, questionnaire, "Questionnaire is valid, nothing was imported")
+		return
+	}
+	utils.RespondWithSuccess(w, http.StatusCreated, questionnaire, "Questionnaire imported successfully")
+}
+
+// ReplaceQuestionnaire handles PUT /api/v1/questionnaires/:id/import?format=json|yaml&dry_run=true
+func (h *QuestionnaireHandler) ReplaceQuestionnaire(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := utils.ValidateObjectID(idStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	doc, ok := parseQuestionnaireDocument(w, r)
+	if !ok {
+		return
+	}
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+
+	questionnaire, err := h.service.ReplaceQuestionnaire(r.Context(), id, doc, dryRun)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	if dryRun {
+		utils.RespondWithSuccess(w, http.StatusOK, questionnaire, "Questionnaire is valid, nothing was replaced")
+		return
+	}
+	utils.RespondWithSuccess(w, http.StatusOK, questionnaire, "Questionnaire replaced successfully")
+}
+
+// documentContentTypes are the content types of the questionnaire document formats
+var documentContentTypes = map[string]string{
+	"json": "application/json",
+	"yaml": "application/yaml",
+}
+
+// documentFormat reads the document format from ?format, falling back to the request content type
+func documentFormat(r *http.Request) (string, bool) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		if strings.Contains(r.Header.Get("Content-Type"), "yaml") {
+			return "yaml", true
+		}
+		return "json", true
+	}
+	if format == "yml" {
+		format = "yaml"
+	}
+	_, ok := documentContentTypes[format]
+	return format, ok
+}
+
+// parseQuestionnaireDocument decodes a questionnaire document strictly, so misspelled fields are
+// reported instead of silently dropped. It writes the error response and returns false on failure.
+func parseQuestionnaireDocument(w http.ResponseWriter, r *http.Request) (*models.QuestionnaireDocument, bool) {
+	format, ok := documentFormat(r)
+	if !ok {
+		utils.BadRequest(w, "format must be json or yaml")
+		return nil, false
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		utils.BadRequest(w, "failed to read request body")
+		return nil, false
+	}
+	defer r.Body.Close()
+
+	var doc models.QuestionnaireDocument
+	if format == "yaml" {
+		decoder := yaml.NewDecoder(bytes.NewReader(body))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&doc)
+	} else {
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&doc)
+	}
+	if err != nil {
+		utils.BadRequest(w, fmt.Sprintf("invalid %s document: %v", strings.ToUpper(format), err))
+		return nil, false
+	}
+
+	return &doc, true
+}
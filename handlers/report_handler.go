@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"fmt"
+	"log"
 	"net/http"
 	"questionarie-service/middleware"
 	"questionarie-service/services"
 	"questionarie-service/utils"
+	"strconv"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -42,6 +45,169 @@ func (h *ReportHandler) GetCompletionMetrics(w http.ResponseWriter, r *http.Requ
 	utils.RespondWithSuccess(w, http.StatusOK, metrics, "")
 }
 
+// GetAnswerReport handles GET /api/v1/reports/company-questionnaire/:cq_id/answers
+func (h *ReportHandler) GetAnswerReport(w http.ResponseWriter, r *http.Request) {
+	cqIDStr := chi.URLParam(r, "cq_id")
+	cqID, err := utils.ValidateObjectID(cqIDStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	includeIncomplete := false
+	if v := r.URL.Query().Get("include_incomplete"); v != "" {
+		includeIncomplete, err = strconv.ParseBool(v)
+		if err != nil {
+			utils.BadRequest(w, "include_incomplete must be a boolean")
+			return
+		}
+	}
+
+	claims, _ := middleware.GetUserFromContext(r.Context())
+	isSuperAdmin := middleware.IsSuperAdmin(r.Context())
+
+	report, err := h.service.GetAnswerReport(r.Context(), cqID, claims.Sub, isSuperAdmin, includeIncomplete)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, report, "")
+}
+
+// GetNPSReport handles GET /api/v1/reports/company-questionnaire/:cq_id/nps
+func (h *ReportHandler) GetNPSReport(w http.ResponseWriter, r *http.Request) {
+	cqIDStr := chi.URLParam(r, "cq_id")
+	cqID, err := utils.ValidateObjectID(cqIDStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	claims, _ := middleware.GetUserFromContext(r.Context())
+	isSuperAdmin := middleware.IsSuperAdmin(r.Context())
+
+	report, err := h.service.GetNPSReport(r.Context(), cqID, claims.Sub, isSuperAdmin)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, report, "")
+}
+
+// GetScoreReport handles GET /api/v1/reports/company-questionnaire/:cq_id/scores
+func (h *ReportHandler) GetScoreReport(w http.ResponseWriter, r *http.Request) {
+	cqIDStr := chi.URLParam(r, "cq_id")
+	cqID, err := utils.ValidateObjectID(cqIDStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	claims, _ := middleware.GetUserFromContext(r.Context())
+	isSuperAdmin := middleware.IsSuperAdmin(r.Context())
+
+	report, err := h.service.GetScoreReport(r.Context(), cqID, claims.Sub, isSuperAdmin)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, report, "")
+}
+
+// GetCompanyScoreReports handles GET /api/v1/reports/company/:company_id/scores
+func (h *ReportHandler) GetCompanyScoreReports(w http.ResponseWriter, r *http.Request) {
+	companyIDStr := chi.URLParam(r, "company_id")
+	companyID, err := utils.ValidateObjectID(companyIDStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	claims, _ := middleware.GetUserFromContext(r.Context())
+	isSuperAdmin := middleware.IsSuperAdmin(r.Context())
+
+	reports, err := h.service.GetCompanyScoreReports(r.Context(), companyID, claims.Sub, isSuperAdmin)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, reports, "")
+}
+
+// GetDimensionReport handles GET /api/v1/reports/company-questionnaire/:cq_id/dimensions
+func (h *ReportHandler) GetDimensionReport(w http.ResponseWriter, r *http.Request) {
+	cqIDStr := chi.URLParam(r, "cq_id")
+	cqID, err := utils.ValidateObjectID(cqIDStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	claims, _ := middleware.GetUserFromContext(r.Context())
+	isSuperAdmin := middleware.IsSuperAdmin(r.Context())
+
+	report, err := h.service.GetDimensionReport(r.Context(), cqID, claims.Sub, isSuperAdmin)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, report, "")
+}
+
+// GetDimensionTrend handles GET /api/v1/reports/company/:company_id/questionnaires/:questionnaire_id/dimension-trends
+func (h *ReportHandler) GetDimensionTrend(w http.ResponseWriter, r *http.Request) {
+	companyIDStr := chi.URLParam(r, "company_id")
+	companyID, err := utils.ValidateObjectID(companyIDStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	questionnaireIDStr := chi.URLParam(r, "questionnaire_id")
+	questionnaireID, err := utils.ValidateObjectID(questionnaireIDStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	claims, _ := middleware.GetUserFromContext(r.Context())
+	isSuperAdmin := middleware.IsSuperAdmin(r.Context())
+
+	trend, err := h.service.GetDimensionTrend(r.Context(), companyID, questionnaireID, claims.Sub, isSuperAdmin)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, trend, "")
+}
+
+// GetCampaignTrend handles GET /api/v1/reports/campaigns/:id/trend
+func (h *ReportHandler) GetCampaignTrend(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := utils.ValidateObjectID(idStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	claims, _ := middleware.GetUserFromContext(r.Context())
+	isSuperAdmin := middleware.IsSuperAdmin(r.Context())
+
+	trend, err := h.service.GetCampaignTrend(r.Context(), id, claims.Sub, isSuperAdmin)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, trend, "")
+}
+
 // GetCompanyOverview handles GET /api/v1/reports/company/:company_id/overview
 func (h *ReportHandler) GetCompanyOverview(w http.ResponseWriter, r *http.Request) {
 	companyIDStr := chi.URLParam(r, "company_id")
@@ -83,3 +249,94 @@ func (h *ReportHandler) GetEmployeeProgress(w http.ResponseWriter, r *http.Reque
 
 	utils.RespondWithSuccess(w, http.StatusOK, progress, "")
 }
+
+// ExportResponses handles GET /api/v1/company-questionnaires/:id/export?format=csv|xlsx
+func (h *ReportHandler) ExportResponses(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := utils.ValidateObjectID(idStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = utils.TableFormatCSV
+	}
+	if format != utils.TableFormatCSV && format != utils.TableFormatXLSX {
+		utils.BadRequest(w, "format must be csv or xlsx")
+		return
+	}
+
+	claims, _ := middleware.GetUserFromContext(r.Context())
+	isSuperAdmin := middleware.IsSuperAdmin(r.Context())
+
+	export, err := h.service.NewResponseExport(r.Context(), id, claims.Sub, isSuperAdmin)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", utils.TableContentType(format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, export.FileName(format)))
+
+	// The status is sent with the first row, so later failures can only cut the file short
+	tw, err := utils.NewTableWriter(format, w)
+	if err == nil {
+		err = export.WriteTo(r.Context(), tw)
+	}
+	if err != nil {
+		log.Printf("Failed to export responses of company questionnaire %s: %v", id.Hex(), err)
+	}
+}
+
+// GetCompletionReportPDF handles GET /api/v1/reports/company-questionnaire/:cq_id/completion/pdf
+func (h *ReportHandler) GetCompletionReportPDF(w http.ResponseWriter, r *http.Request) {
+	cqIDStr := chi.URLParam(r, "cq_id")
+	cqID, err := utils.ValidateObjectID(cqIDStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	claims, _ := middleware.GetUserFromContext(r.Context())
+	isSuperAdmin := middleware.IsSuperAdmin(r.Context())
+
+	report, err := h.service.GetCompletionReportPDF(r.Context(), cqID, claims.Sub, isSuperAdmin)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	respondWithPDF(w, report)
+}
+
+// GetCompanyOverviewPDF handles GET /api/v1/reports/company/:company_id/overview/pdf
+func (h *ReportHandler) GetCompanyOverviewPDF(w http.ResponseWriter, r *http.Request) {
+	companyIDStr := chi.URLParam(r, "company_id")
+	companyID, err := utils.ValidateObjectID(companyIDStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	claims, _ := middleware.GetUserFromContext(r.Context())
+	isSuperAdmin := middleware.IsSuperAdmin(r.Context())
+
+	report, err := h.service.GetCompanyOverviewPDF(r.Context(), companyID, claims.Sub, isSuperAdmin)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	respondWithPDF(w, report)
+}
+
+// respondWithPDF sends a rendered PDF report as a download
+func respondWithPDF(w http.ResponseWriter, report *services.PDFReport) {
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, report.FileName))
+	w.Header().Set("Content-Length", strconv.Itoa(len(report.Content)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(report.Content)
+}
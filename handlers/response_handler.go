@@ -70,10 +70,7 @@ func (h *ResponseHandler) UpdateResponses(w http.ResponseWriter, r *http.Request
 	}
 
 	var req struct {
-		Responses []struct {
-			QuestionID    string      `json:"question_id"`
-			ResponseValue interface{} `json:"response_value"`
-		} `json:"responses"`
+		Responses []services.ResponseInput `json:"responses"`
 	}
 
 	if err := utils.ParseRequestBody(r, &req); err != nil {
@@ -88,17 +85,53 @@ func (h *ResponseHandler) UpdateResponses(w http.ResponseWriter, r *http.Request
 
 	claims, _ := middleware.GetUserFromContext(r.Context())
 
-	// Save each response
-	for _, response := range req.Responses {
-		if err := h.service.SaveResponse(r.Context(), id, claims.Sub, response.QuestionID, response.ResponseValue); err != nil {
-			utils.HandleRepositoryError(w, err)
-			return
-		}
+	if err := h.service.UpdateResponses(r.Context(), id, claims.Sub, req.Responses); err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
 	}
 
 	utils.RespondWithSuccess(w, http.StatusOK, nil, "Responses updated successfully")
 }
 
+// SaveSectionResponses handles PUT /api/v1/assignments/:id/sections/:section_id/responses
+func (h *ResponseHandler) SaveSectionResponses(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := utils.ValidateObjectID(idStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	sectionID := chi.URLParam(r, "section_id")
+	if sectionID == "" {
+		utils.BadRequest(w, "section_id is required")
+		return
+	}
+
+	var req struct {
+		Responses []services.ResponseInput `json:"responses"`
+	}
+
+	if err := utils.ParseRequestBody(r, &req); err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	if len(req.Responses) == 0 {
+		utils.BadRequest(w, "responses cannot be empty")
+		return
+	}
+
+	claims, _ := middleware.GetUserFromContext(r.Context())
+
+	if err := h.service.SaveSectionResponses(r.Context(), id, claims.Sub, sectionID, req.Responses); err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, nil, "Section responses saved successfully")
+}
+
 // SubmitAssignment handles POST /api/v1/assignments/:id/submit
 func (h *ResponseHandler) SubmitAssignment(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"net/http"
+	"questionarie-service/services"
+	"questionarie-service/utils"
+)
+
+// SchedulerHandler handles background scheduler HTTP requests
+type SchedulerHandler struct {
+	scheduler *services.Scheduler
+}
+
+// NewSchedulerHandler creates a new SchedulerHandler
+func NewSchedulerHandler(scheduler *services.Scheduler) *SchedulerHandler {
+	return &SchedulerHandler{
+		scheduler: scheduler,
+	}
+}
+
+// GetStatus handles GET /api/v1/scheduler/status
+func (h *SchedulerHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	utils.RespondWithSuccess(w, http.StatusOK, h.scheduler.GetStatus(r.Context()), "")
+}
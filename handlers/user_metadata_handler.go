@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"questionarie-service/middleware"
 	"questionarie-service/services"
 	"questionarie-service/utils"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -29,6 +32,7 @@ func (h *UserMetadataHandler) CreateUserMetadata(w http.ResponseWriter, r *http.
 		CompanyID    string `json:"company_id"`
 		SupervisorID string `json:"supervisor_id"`
 		Department   string `json:"department"`
+		Email        string `json:"email"`
 	}
 
 	if err := utils.ParseRequestBody(r, &req); err != nil {
@@ -52,7 +56,7 @@ func (h *UserMetadataHandler) CreateUserMetadata(w http.ResponseWriter, r *http.
 		return
 	}
 
-	metadata, err := h.service.CreateUserMetadata(r.Context(), req.UserID, companyID, req.SupervisorID, req.Department)
+	metadata, err := h.service.CreateUserMetadata(r.Context(), req.UserID, companyID, req.SupervisorID, req.Department, req.Email)
 	if err != nil {
 		utils.HandleRepositoryError(w, err)
 		return
@@ -90,6 +94,7 @@ func (h *UserMetadataHandler) UpdateUserMetadata(w http.ResponseWriter, r *http.
 		CompanyID    string `json:"company_id"`
 		SupervisorID string `json:"supervisor_id"`
 		Department   string `json:"department"`
+		Email        string `json:"email"`
 	}
 
 	if err := utils.ParseRequestBody(r, &req); err != nil {
@@ -103,7 +108,7 @@ func (h *UserMetadataHandler) UpdateUserMetadata(w http.ResponseWriter, r *http.
 		return
 	}
 
-	if err := h.service.UpdateUserMetadata(r.Context(), userID, companyID, req.SupervisorID, req.Department); err != nil {
+	if err := h.service.UpdateUserMetadata(r.Context(), userID, companyID, req.SupervisorID, req.Department, req.Email); err != nil {
 		utils.HandleRepositoryError(w, err)
 		return
 	}
@@ -111,6 +116,31 @@ func (h *UserMetadataHandler) UpdateUserMetadata(w http.ResponseWriter, r *http.
 	utils.RespondWithSuccess(w, http.StatusOK, nil, "User metadata updated successfully")
 }
 
+// AssignSupervisor handles PUT /api/v1/users/metadata/:user_id/supervisor
+func (h *UserMetadataHandler) AssignSupervisor(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "user_id")
+	if userID == "" {
+		utils.BadRequest(w, "user_id is required")
+		return
+	}
+
+	var req struct {
+		SupervisorID string `json:"supervisor_id"`
+	}
+
+	if err := utils.ParseRequestBody(r, &req); err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	if err := h.service.AssignSupervisor(r.Context(), userID, req.SupervisorID); err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, nil, "Supervisor updated successfully")
+}
+
 // DeleteUserMetadata handles DELETE /api/v1/users/metadata/:user_id
 func (h *UserMetadataHandler) DeleteUserMetadata(w http.ResponseWriter, r *http.Request) {
 	userID := chi.URLParam(r, "user_id")
@@ -148,6 +178,24 @@ func (h *UserMetadataHandler) GetUsersByCompany(w http.ResponseWriter, r *http.R
 	utils.RespondWithSuccess(w, http.StatusOK, users, "")
 }
 
+// GetOrgChart handles GET /api/v1/companies/:company_id/org-chart
+func (h *UserMetadataHandler) GetOrgChart(w http.ResponseWriter, r *http.Request) {
+	companyIDStr := chi.URLParam(r, "company_id")
+	companyID, err := utils.ValidateObjectID(companyIDStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	chart, err := h.service.GetOrgChart(r.Context(), companyID)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, chart, "")
+}
+
 // GetMyMetadata handles GET /api/v1/users/me/metadata
 func (h *UserMetadataHandler) GetMyMetadata(w http.ResponseWriter, r *http.Request) {
 	claims, _ := middleware.GetUserFromContext(r.Context())
@@ -177,6 +225,47 @@ func (h *UserMetadataHandler) GetMyMetadata(w http.ResponseWriter, r *http.Reque
 	if metadata.Department != "" {
 		response["department"] = metadata.Department
 	}
+	if metadata.Email != "" {
+		response["email"] = metadata.Email
+	}
 
 	utils.RespondWithSuccess(w, http.StatusOK, response, "")
 }
+
+// maxUserMetadataImportSize is the maximum size of an uploaded user metadata CSV file
+const maxUserMetadataImportSize = 10 << 20
+
+// ImportUserMetadata handles POST /api/v1/users/metadata/import?dry_run=true
+// The CSV file is sent as the request body or as the "file" field of a multipart form.
+func (h *UserMetadataHandler) ImportUserMetadata(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxUserMetadataImportSize)
+
+	var file io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		part, _, err := r.FormFile("file")
+		if err != nil {
+			utils.BadRequest(w, "file is required")
+			return
+		}
+		defer part.Close()
+		file = part
+	}
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+
+	result, err := h.service.ImportUserMetadata(r.Context(), file, dryRun)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			utils.BadRequest(w, "file is too large")
+			return
+		}
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	if dryRun {
+		utils.RespondWithSuccess(w, http.StatusOK, result, "Dry run completed, nothing was imported")
+		return
+	}
+	utils.RespondWithSuccess(w, http.StatusOK, result, "User metadata imported successfully")
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"net/http"
+	"questionarie-service/middleware"
+	"questionarie-service/models"
+	"questionarie-service/services"
+	"questionarie-service/utils"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// WebhookHandler handles webhook subscription HTTP requests
+type WebhookHandler struct {
+	service *services.WebhookService
+}
+
+// NewWebhookHandler creates a new WebhookHandler
+func NewWebhookHandler(service *services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		service: service,
+	}
+}
+
+// CreateSubscription handles POST /api/v1/companies/:company_id/webhooks
+func (h *WebhookHandler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	companyIDStr := chi.URLParam(r, "company_id")
+	companyID, err := utils.ValidateObjectID(companyIDStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	var req struct {
+		URL         string                    `json:"url"`
+		Secret      string                    `json:"secret"`
+		EventTypes  []models.WebhookEventType `json:"event_types"`
+		Description string                    `json:"description"`
+	}
+
+	if err := utils.ParseRequestBody(r, &req); err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	input := services.WebhookSubscriptionInput{
+		URL:         req.URL,
+		Secret:      req.Secret,
+		EventTypes:  req.EventTypes,
+		Description: req.Description,
+	}
+
+	claims, _ := middleware.GetUserFromContext(r.Context())
+	subscription, err := h.service.CreateSubscription(r.Context(), companyID, input, claims.Sub)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusCreated, subscription, "Webhook subscription created successfully")
+}
+
+// GetCompanySubscriptions handles GET /api/v1/companies/:company_id/webhooks
+func (h *WebhookHandler) GetCompanySubscriptions(w http.ResponseWriter, r *http.Request) {
+	companyIDStr := chi.URLParam(r, "company_id")
+	companyID, err := utils.ValidateObjectID(companyIDStr)
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	subscriptions, err := h.service.GetCompanySubscriptions(r.Context(), companyID)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, subscriptions, "")
+}
+
+// GetSubscription handles GET /api/v1/webhooks/:id
+func (h *WebhookHandler) GetSubscription(w http.ResponseWriter, r *http.Request) {
+	id, ok := webhookIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	subscription, err := h.service.GetSubscription(r.Context(), id)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, subscription, "")
+}
+
+// UpdateSubscription handles PUT /api/v1/webhooks/:id
+func (h *WebhookHandler) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
+	id, ok := webhookIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		URL         *string                   `json:"url"`
+		Secret      *string                   `json:"secret"`
+		EventTypes  []models.WebhookEventType `json:"event_types"`
+		Description *string                   `json:"description"`
+		IsActive    *bool                     `json:"is_active"`
+	}
+
+	if err := utils.ParseRequestBody(r, &req); err != nil {
+		utils.BadRequest(w, err.Error())
+		return
+	}
+
+	update := services.WebhookSubscriptionUpdate{
+		URL:         req.URL,
+		Secret:      req.Secret,
+		EventTypes:  req.EventTypes,
+		Description: req.Description,
+		IsActive:    req.IsActive,
+	}
+
+	subscription, err := h.service.UpdateSubscription(r.Context(), id, update)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, subscription, "Webhook subscription updated successfully")
+}
+
+// DeleteSubscription handles DELETE /api/v1/webhooks/:id
+func (h *WebhookHandler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	id, ok := webhookIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteSubscription(r.Context(), id); err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, nil, "Webhook subscription deleted successfully")
+}
+
+// GetDeliveries handles GET /api/v1/webhooks/:id/deliveries?status=
+func (h *WebhookHandler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, ok := webhookIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	statusStr := r.URL.Query().Get("status")
+	var status *models.WebhookDeliveryStatus
+	if statusStr != "" {
+		if err := utils.ValidateWebhookDeliveryStatus(statusStr); err != nil {
+			utils.BadRequest(w, err.Error())
+			return
+		}
+		s := models.WebhookDeliveryStatus(statusStr)
+		status = &s
+	}
+
+	deliveries, err := h.service.GetDeliveries(r.Context(), id, status)
+	if err != nil {
+		utils.HandleRepositoryError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, deliveries, "")
+}
+
+// webhookIDParam reads the subscription ID from the URL, answering 400 when it is not valid
+func webhookIDParam(w http.ResponseWriter, r *http.Request) (primitive.ObjectID, bool) {
+	id, err := utils.ValidateObjectID(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.BadRequest(w, err.Error())
+		return primitive.NilObjectID, false
+	}
+	return id, true
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
@@ -17,6 +18,8 @@ import (
 	"questionarie-service/db"
 	"questionarie-service/handlers"
 	authMiddleware "questionarie-service/middleware"
+	"questionarie-service/notifications"
+	"questionarie-service/outbox"
 	"questionarie-service/repository"
 	"questionarie-service/services"
 )
@@ -35,16 +38,50 @@ func main() {
 	// Initialize repositories
 	companyRepo := repository.NewCompanyRepository(mongodb.Database)
 	questionnaireRepo := repository.NewQuestionnaireRepository(mongodb.Database)
+	questionnaireVersionRepo := repository.NewQuestionnaireVersionRepository(mongodb.Database)
 	companyQuestionnaireRepo := repository.NewCompanyQuestionnaireRepository(mongodb.Database)
 	assignmentRepo := repository.NewAssignmentRepository(mongodb.Database)
 	userMetadataRepo := repository.NewUserMetadataRepository(mongodb.Database)
+	anonymousResponseRepo := repository.NewAnonymousResponseRepository(mongodb.Database)
+	campaignRepo := repository.NewCampaignRepository(mongodb.Database)
+	leaseRepo := repository.NewLeaseRepository(mongodb.Database)
+	notificationRepo := repository.NewNotificationRepository(mongodb.Database)
+	webhookSubscriptionRepo := repository.NewWebhookSubscriptionRepository(mongodb.Database)
+	webhookDeliveryRepo := repository.NewWebhookDeliveryRepository(mongodb.Database)
+	outboxRepo := repository.NewOutboxRepository(mongodb.Database)
+
+	// Changes and their domain events are written in one transaction; standalone servers need MONGODB_TRANSACTIONS=false
+	transactionsEnabled := os.Getenv("MONGODB_TRANSACTIONS") != "false"
+	if !transactionsEnabled {
+		log.Println("WARNING: MONGODB_TRANSACTIONS=false, changes and their outbox events are written separately; " +
+			"a failure between the two writes loses the event and consumers miss the change")
+	}
+	transactor := repository.NewTransactor(mongodb.Database, transactionsEnabled)
+
+	// Initialize the notification transport
+	notificationTransport, err := notifications.NewTransportFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure notifications: %v", err)
+	}
 
 	// Initialize services
-	questionnaireService := services.NewQuestionnaireService(questionnaireRepo)
-	companyService := services.NewCompanyService(companyRepo, companyQuestionnaireRepo, questionnaireRepo)
-	userMetadataService := services.NewUserMetadataService(userMetadataRepo, companyRepo)
-	assignmentService := services.NewAssignmentService(assignmentRepo, companyQuestionnaireRepo, userMetadataRepo, questionnaireRepo)
-	reportService := services.NewReportService(assignmentRepo, companyQuestionnaireRepo, userMetadataRepo, questionnaireRepo, companyRepo)
+	webhookService := services.NewWebhookService(webhookSubscriptionRepo, webhookDeliveryRepo, companyRepo)
+
+	// Domain events are relayed to the sinks in OUTBOX_SINKS; a broker Publisher is passed here to enable "broker"
+	outboxSink, err := outbox.NewSinkFromEnv(webhookService, nil)
+	if err != nil {
+		log.Fatalf("Failed to configure the outbox: %v", err)
+	}
+	outboxService := services.NewOutboxService(outboxRepo, transactor, outboxSink)
+
+	questionnaireService := services.NewQuestionnaireService(questionnaireRepo, questionnaireVersionRepo, companyQuestionnaireRepo, campaignRepo, transactor)
+	companyService := services.NewCompanyService(companyRepo, companyQuestionnaireRepo, questionnaireRepo, assignmentRepo, outboxService)
+	userMetadataService := services.NewUserMetadataService(userMetadataRepo, companyRepo, transactor)
+	notificationService := services.NewNotificationService(notificationRepo, companyRepo, companyQuestionnaireRepo, assignmentRepo, userMetadataRepo, questionnaireRepo, questionnaireVersionRepo, notificationTransport, os.Getenv("NOTIFICATION_APP_URL"))
+	assignmentService := services.NewAssignmentService(assignmentRepo, companyQuestionnaireRepo, userMetadataRepo, questionnaireRepo, questionnaireVersionRepo, anonymousResponseRepo, notificationService, outboxService)
+	reportService := services.NewReportService(assignmentRepo, companyQuestionnaireRepo, userMetadataRepo, questionnaireRepo, questionnaireVersionRepo, companyRepo, anonymousResponseRepo, campaignRepo)
+	campaignService := services.NewCampaignService(campaignRepo, companyRepo, questionnaireRepo, companyQuestionnaireRepo, assignmentRepo, userMetadataRepo, companyService, assignmentService, transactor)
+	scheduler := services.NewScheduler(leaseRepo, companyService, campaignService, notificationService, outboxService, webhookService, schedulerConfig())
 
 	// Initialize handlers
 	questionnaireHandler := handlers.NewQuestionnaireHandler(questionnaireService)
@@ -53,6 +90,10 @@ func main() {
 	assignmentHandler := handlers.NewAssignmentHandler(assignmentService)
 	responseHandler := handlers.NewResponseHandler(assignmentService)
 	reportHandler := handlers.NewReportHandler(reportService)
+	campaignHandler := handlers.NewCampaignHandler(campaignService)
+	schedulerHandler := handlers.NewSchedulerHandler(scheduler)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
 
 	// Create router
 	r := chi.NewRouter()
@@ -116,11 +157,36 @@ func main() {
 				r.Get("/api/v1/questionnaires/{id}", questionnaireHandler.GetQuestionnaireByID)
 				r.Put("/api/v1/questionnaires/{id}", questionnaireHandler.UpdateQuestionnaire)
 				r.Delete("/api/v1/questionnaires/{id}", questionnaireHandler.DeactivateQuestionnaire)
+				r.Post("/api/v1/questionnaires/{id}/clone", questionnaireHandler.CloneQuestionnaire)
+				r.Get("/api/v1/questionnaires/{id}/clones", questionnaireHandler.GetQuestionnaireClones)
+
+				// Import and export
+				r.Post("/api/v1/questionnaires/import", questionnaireHandler.ImportQuestionnaire)
+				r.Get("/api/v1/questionnaires/{id}/export", questionnaireHandler.ExportQuestionnaire)
+				r.Put("/api/v1/questionnaires/{id}/import", questionnaireHandler.ReplaceQuestionnaire)
 
 				// Questions management
 				r.Post("/api/v1/questionnaires/{id}/questions", questionnaireHandler.AddQuestion)
+				r.Put("/api/v1/questionnaires/{id}/questions/order", questionnaireHandler.ReorderQuestions)
 				r.Put("/api/v1/questionnaires/{id}/questions/{question_id}", questionnaireHandler.UpdateQuestion)
 				r.Delete("/api/v1/questionnaires/{id}/questions/{question_id}", questionnaireHandler.RemoveQuestion)
+
+				// Scoring
+				r.Put("/api/v1/questionnaires/{id}/score-bands", questionnaireHandler.UpdateScoreBands)
+
+				// Sections management
+				r.Get("/api/v1/questionnaires/{id}/sections", questionnaireHandler.GetSections)
+				r.Post("/api/v1/questionnaires/{id}/sections", questionnaireHandler.AddSection)
+				r.Put("/api/v1/questionnaires/{id}/sections/{section_id}", questionnaireHandler.UpdateSection)
+				r.Delete("/api/v1/questionnaires/{id}/sections/{section_id}", questionnaireHandler.RemoveSection)
+
+				// Lifecycle transitions and versioning
+				r.Post("/api/v1/questionnaires/{id}/publish", questionnaireHandler.PublishQuestionnaire)
+				r.Post("/api/v1/questionnaires/{id}/revise", questionnaireHandler.ReviseQuestionnaire)
+				r.Post("/api/v1/questionnaires/{id}/archive", questionnaireHandler.ArchiveQuestionnaire)
+				r.Post("/api/v1/questionnaires/{id}/restore", questionnaireHandler.RestoreQuestionnaire)
+				r.Get("/api/v1/questionnaires/{id}/versions", questionnaireHandler.GetQuestionnaireVersions)
+				r.Get("/api/v1/questionnaires/{id}/versions/{version}", questionnaireHandler.GetQuestionnaireVersion)
 			})
 
 			// === Companies (Super Admin only) ===
@@ -134,6 +200,29 @@ func main() {
 
 				// Assign questionnaire to company
 				r.Post("/api/v1/companies/{company_id}/questionnaires", companyHandler.AssignQuestionnaireToCompany)
+
+				// Recurring campaigns
+				r.Post("/api/v1/companies/{company_id}/campaigns", campaignHandler.CreateCampaign)
+				r.Get("/api/v1/companies/{company_id}/campaigns", campaignHandler.GetCompanyCampaigns)
+				r.Post("/api/v1/campaigns/run", campaignHandler.RunDueCampaigns)
+				r.Get("/api/v1/campaigns/{id}", campaignHandler.GetCampaign)
+				r.Put("/api/v1/campaigns/{id}", campaignHandler.UpdateCampaign)
+				r.Get("/api/v1/campaigns/{id}/periods", campaignHandler.GetCampaignPeriods)
+
+				// Background scheduler
+				r.Get("/api/v1/scheduler/status", schedulerHandler.GetStatus)
+
+				// Notifications
+				r.Get("/api/v1/companies/{company_id}/notifications", notificationHandler.GetCompanyNotifications)
+				r.Post("/api/v1/notifications/{id}/retry", notificationHandler.RetryNotification)
+
+				// Outbound webhooks
+				r.Post("/api/v1/companies/{company_id}/webhooks", webhookHandler.CreateSubscription)
+				r.Get("/api/v1/companies/{company_id}/webhooks", webhookHandler.GetCompanySubscriptions)
+				r.Get("/api/v1/webhooks/{id}", webhookHandler.GetSubscription)
+				r.Put("/api/v1/webhooks/{id}", webhookHandler.UpdateSubscription)
+				r.Delete("/api/v1/webhooks/{id}", webhookHandler.DeleteSubscription)
+				r.Get("/api/v1/webhooks/{id}/deliveries", webhookHandler.GetDeliveries)
 			})
 
 			// === User Metadata - Get My Metadata (All authenticated users) ===
@@ -144,12 +233,15 @@ func main() {
 				r.Use(authMiddleware.RequireSuperAdmin())
 
 				r.Post("/api/v1/users/metadata", userMetadataHandler.CreateUserMetadata)
+				r.Post("/api/v1/users/metadata/import", userMetadataHandler.ImportUserMetadata)
 				r.Get("/api/v1/users/metadata/{user_id}", userMetadataHandler.GetUserMetadata)
 				r.Put("/api/v1/users/metadata/{user_id}", userMetadataHandler.UpdateUserMetadata)
 				r.Delete("/api/v1/users/metadata/{user_id}", userMetadataHandler.DeleteUserMetadata)
+				r.Put("/api/v1/users/metadata/{user_id}/supervisor", userMetadataHandler.AssignSupervisor)
 
 				// Get users by company
 				r.Get("/api/v1/companies/{company_id}/users", userMetadataHandler.GetUsersByCompany)
+				r.Get("/api/v1/companies/{company_id}/org-chart", userMetadataHandler.GetOrgChart)
 			})
 
 			// === Company Questionnaires (Super Admin, Company Admin) ===
@@ -158,6 +250,7 @@ func main() {
 
 				r.Get("/api/v1/companies/{company_id}/questionnaires", companyHandler.GetCompanyQuestionnaires)
 				r.Put("/api/v1/company-questionnaires/{id}", companyHandler.UpdateCompanyQuestionnaire)
+				r.Get("/api/v1/company-questionnaires/{id}/export", reportHandler.ExportResponses)
 			})
 
 			// === Assignments (Company Admin, Supervisor) ===
@@ -180,10 +273,13 @@ func main() {
 				// View my assignments
 				r.Get("/api/v1/my-assignments", assignmentHandler.GetMyAssignments)
 				r.Get("/api/v1/assignments/{id}", assignmentHandler.GetAssignmentByID)
+				r.Get("/api/v1/assignments/{id}/progress", assignmentHandler.GetAssignmentProgress)
+				r.Get("/api/v1/assignments/{id}/sections/{section_id}", assignmentHandler.GetAssignmentPage)
 
 				// Save responses
 				r.Post("/api/v1/assignments/{id}/responses", responseHandler.SaveResponse)
 				r.Put("/api/v1/assignments/{id}/responses", responseHandler.UpdateResponses)
+				r.Put("/api/v1/assignments/{id}/sections/{section_id}/responses", responseHandler.SaveSectionResponses)
 				r.Post("/api/v1/assignments/{id}/submit", responseHandler.SubmitAssignment)
 			})
 
@@ -192,8 +288,17 @@ func main() {
 				r.Use(authMiddleware.RequireSupervisor())
 
 				r.Get("/api/v1/reports/company-questionnaire/{cq_id}/completion", reportHandler.GetCompletionMetrics)
+				r.Get("/api/v1/reports/company-questionnaire/{cq_id}/completion/pdf", reportHandler.GetCompletionReportPDF)
+				r.Get("/api/v1/reports/company-questionnaire/{cq_id}/answers", reportHandler.GetAnswerReport)
+				r.Get("/api/v1/reports/company-questionnaire/{cq_id}/nps", reportHandler.GetNPSReport)
+				r.Get("/api/v1/reports/company-questionnaire/{cq_id}/scores", reportHandler.GetScoreReport)
+				r.Get("/api/v1/reports/company-questionnaire/{cq_id}/dimensions", reportHandler.GetDimensionReport)
 				r.Get("/api/v1/reports/company/{company_id}/overview", reportHandler.GetCompanyOverview)
+				r.Get("/api/v1/reports/company/{company_id}/overview/pdf", reportHandler.GetCompanyOverviewPDF)
 				r.Get("/api/v1/reports/company/{company_id}/employees-progress", reportHandler.GetEmployeeProgress)
+				r.Get("/api/v1/reports/company/{company_id}/scores", reportHandler.GetCompanyScoreReports)
+				r.Get("/api/v1/reports/company/{company_id}/questionnaires/{questionnaire_id}/dimension-trends", reportHandler.GetDimensionTrend)
+				r.Get("/api/v1/reports/campaigns/{id}/trend", reportHandler.GetCampaignTrend)
 			})
 		})
 	})
@@ -212,6 +317,19 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	// Background scheduler (period lifecycle, campaigns, notifications, outbox relay and webhooks); one instance runs the jobs at a time
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	schedulerDone := make(chan struct{})
+	if os.Getenv("SCHEDULER_ENABLED") != "false" {
+		go func() {
+			scheduler.Run(schedulerCtx)
+			close(schedulerDone)
+		}()
+	} else {
+		log.Println("Scheduler disabled")
+		close(schedulerDone)
+	}
+
 	// Graceful shutdown
 	done := make(chan os.Signal, 1)
 	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
@@ -227,6 +345,9 @@ func main() {
 	<-done
 	log.Println("Server shutting down...")
 
+	stopScheduler()
+	<-schedulerDone
+
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
@@ -236,3 +357,24 @@ func main() {
 
 	log.Println("Server exited properly")
 }
+
+// schedulerConfig reads the scheduler settings from the environment. The lease outlives a few
+// missed ticks by default, so a slow tick does not hand the jobs to another instance.
+func schedulerConfig() services.SchedulerConfig {
+	interval := time.Minute
+	if parsed, err := time.ParseDuration(os.Getenv("SCHEDULER_INTERVAL")); err == nil && parsed > 0 {
+		interval = parsed
+	}
+
+	leaseTTL := 3 * interval
+	if parsed, err := time.ParseDuration(os.Getenv("SCHEDULER_LEASE_TTL")); err == nil && parsed > interval {
+		leaseTTL = parsed
+	}
+
+	hostname, _ := os.Hostname()
+	return services.SchedulerConfig{
+		Interval:   interval,
+		LeaseTTL:   leaseTTL,
+		InstanceID: fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+	}
+}
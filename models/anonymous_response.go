@@ -0,0 +1,59 @@
+package models
+
+import (
+	"crypto/rand"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AnonymousResponseSet holds the answers of one submitted assignment of an anonymous
+// company questionnaire. It carries no reference to the respondent or the assignment.
+type AnonymousResponseSet struct {
+	ID                     primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	CompanyQuestionnaireID primitive.ObjectID `bson:"company_questionnaire_id" json:"company_questionnaire_id"`
+	Department             string             `bson:"department,omitempty" json:"department,omitempty"`       // Respondent's department at submit time
+	SupervisorID           string             `bson:"supervisor_id,omitempty" json:"supervisor_id,omitempty"` // Respondent's supervisor (team) at submit time
+	Responses              []Response         `bson:"responses" json:"responses"`
+	Score                  *AssignmentScore   `bson:"score,omitempty" json:"score,omitempty"`
+	SubmittedOn            time.Time          `bson:"submitted_on" json:"submitted_on"` // Day of submission only
+}
+
+// NewAnonymousResponseSet detaches responses from their assignment. Answer timestamps are
+// dropped and the submission time is truncated to the day so sets cannot be matched back
+// to assignments by time.
+func NewAnonymousResponseSet(companyQuestionnaireID primitive.ObjectID, department, supervisorID string, responses []Response, score *AssignmentScore) *AnonymousResponseSet {
+	detached := make([]Response, 0, len(responses))
+	for _, r := range responses {
+		detached = append(detached, Response{QuestionID: r.QuestionID, ResponseValue: r.ResponseValue})
+	}
+
+	now := time.Now().UTC()
+	return &AnonymousResponseSet{
+		ID:                     newRandomObjectID(),
+		CompanyQuestionnaireID: companyQuestionnaireID,
+		Department:             department,
+		SupervisorID:           supervisorID,
+		Responses:              detached,
+		Score:                  score,
+		SubmittedOn:            time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// GetResponse retrieves the response to a question
+func (s *AnonymousResponseSet) GetResponse(questionID string) *Response {
+	for i := range s.Responses {
+		if s.Responses[i].QuestionID == questionID {
+			return &s.Responses[i]
+		}
+	}
+	return nil
+}
+
+// newRandomObjectID returns an ObjectID without the embedded creation time and counter,
+// which would otherwise reveal when and in which order a set was stored
+func newRandomObjectID() primitive.ObjectID {
+	var id primitive.ObjectID
+	_, _ = rand.Read(id[:])
+	return id
+}
@@ -0,0 +1,310 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FreeTextSampleSize is the number of free text answers included in a summary
+const FreeTextSampleSize = 5
+
+// AnswerSummary aggregates the answers given to one question. Which fields are
+// set depends on the question type; values that do not fit the question are skipped.
+type AnswerSummary struct {
+	QuestionID   string                    `json:"question_id"`
+	QuestionText string                    `json:"question_text"`
+	QuestionType QuestionType              `json:"question_type"`
+	Count        int                       `json:"count"`                  // Number of answers counted
+	Distribution map[string]int            `json:"distribution,omitempty"` // Answers per choice (choices, yes/no, checkbox, month for dates)
+	Histogram    map[string]int            `json:"histogram,omitempty"`    // Answers per scale value (Likert, rating, NPS)
+	Stats        *NumericStats             `json:"stats,omitempty"`        // Likert, rating, NPS and numeric
+	NPS          *NPSResult                `json:"nps,omitempty"`          // NPS
+	AverageRank  map[string]float64        `json:"average_rank,omitempty"` // Ranking: mean position per choice (1 = first)
+	Rows         map[string]map[string]int `json:"rows,omitempty"`         // Matrix: answers per column for each row
+	Earliest     string                    `json:"earliest,omitempty"`     // Date
+	Latest       string                    `json:"latest,omitempty"`       // Date
+	Samples      []string                  `json:"samples,omitempty"`      // Free text
+	Suppressed   bool                      `json:"suppressed,omitempty"`   // Too few answers to report
+}
+
+// NumericStats are descriptive statistics over numeric answers
+type NumericStats struct {
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	StdDev float64 `json:"stdev"` // Population standard deviation
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+}
+
+// Summarize aggregates the given answer values using the question type's aggregator
+func (q *Question) Summarize(values []interface{}) *AnswerSummary {
+	var summary *AnswerSummary
+	if def, ok := GetQuestionTypeDefinition(q.QuestionType); ok {
+		summary = def.Aggregate(q, values)
+	} else {
+		summary = &AnswerSummary{}
+	}
+	summary.QuestionID = q.QuestionID
+	summary.QuestionText = q.QuestionText
+	summary.QuestionType = q.QuestionType
+	return summary
+}
+
+// SuppressBelow drops everything but the question when fewer than minGroupSize answers were
+// counted, too few to be reported
+func (s *AnswerSummary) SuppressBelow(minGroupSize int) {
+	if s.Count >= minGroupSize {
+		return
+	}
+	*s = AnswerSummary{
+		QuestionID:   s.QuestionID,
+		QuestionText: s.QuestionText,
+		QuestionType: s.QuestionType,
+		Suppressed:   true,
+	}
+}
+
+// NewNumericStats computes descriptive statistics, or returns nil when there are no values
+func NewNumericStats(values []float64) *NumericStats {
+	if len(values) == 0 {
+		return nil
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	sum := 0.0
+	for _, v := range sorted {
+		sum += v
+	}
+	mean := sum / float64(len(sorted))
+
+	variance := 0.0
+	for _, v := range sorted {
+		variance += (v - mean) * (v - mean)
+	}
+	variance /= float64(len(sorted))
+
+	n := len(sorted)
+	median := sorted[n/2]
+	if n%2 == 0 {
+		median = (sorted[n/2-1] + sorted[n/2]) / 2
+	}
+
+	return &NumericStats{
+		Mean:   mean,
+		Median: median,
+		StdDev: math.Sqrt(variance),
+		Min:    sorted[0],
+		Max:    sorted[n-1],
+	}
+}
+
+func aggregateChoices(q *Question, values []interface{}) *AnswerSummary {
+	summary := &AnswerSummary{Distribution: zeroCounts(q.GetChoices())}
+	for _, value := range values {
+		if s, ok := value.(string); ok {
+			if _, known := summary.Distribution[s]; known {
+				summary.Distribution[s]++
+				summary.Count++
+			}
+		}
+	}
+	return summary
+}
+
+func aggregateYesNo(q *Question, values []interface{}) *AnswerSummary {
+	summary := &AnswerSummary{Distribution: map[string]int{"yes": 0, "no": 0}}
+	for _, value := range values {
+		if b, ok := value.(bool); ok {
+			if b {
+				summary.Distribution["yes"]++
+			} else {
+				summary.Distribution["no"]++
+			}
+			summary.Count++
+		}
+	}
+	return summary
+}
+
+// aggregateScale summarises integer scales (Likert, rating) with a histogram over every scale value
+func aggregateScale(q *Question, values []interface{}) *AnswerSummary {
+	min, max := q.GetLikertRange()
+	if q.QuestionType == QuestionTypeRating {
+		min, max = 1, q.GetRatingOptions().Max
+	}
+	return aggregateIntScale(values, min, max)
+}
+
+// aggregateNPS summarises the 0-10 scale and classifies promoters, passives and detractors
+func aggregateNPS(q *Question, values []interface{}) *AnswerSummary {
+	summary := aggregateIntScale(values, NPSMin, NPSMax)
+	nps := &NPSResult{}
+	for _, value := range values {
+		if n, ok := ToInt(value); ok {
+			nps.Add(n)
+		}
+	}
+	summary.NPS = nps
+	return summary
+}
+
+func aggregateIntScale(values []interface{}, min, max int) *AnswerSummary {
+	summary := &AnswerSummary{Histogram: make(map[string]int, max-min+1)}
+	for v := min; v <= max; v++ {
+		summary.Histogram[strconv.Itoa(v)] = 0
+	}
+
+	numbers := []float64{}
+	for _, value := range values {
+		if n, ok := ToInt(value); ok && n >= min && n <= max {
+			summary.Histogram[strconv.Itoa(n)]++
+			numbers = append(numbers, float64(n))
+		}
+	}
+	summary.Count = len(numbers)
+	summary.Stats = NewNumericStats(numbers)
+	return summary
+}
+
+func aggregateNumeric(q *Question, values []interface{}) *AnswerSummary {
+	numbers := []float64{}
+	for _, value := range values {
+		if n, ok := ToFloat(value); ok && !math.IsNaN(n) && !math.IsInf(n, 0) {
+			numbers = append(numbers, n)
+		}
+	}
+	return &AnswerSummary{Count: len(numbers), Stats: NewNumericStats(numbers)}
+}
+
+func aggregateText(q *Question, values []interface{}) *AnswerSummary {
+	summary := &AnswerSummary{Samples: []string{}}
+	for _, value := range values {
+		text, ok := value.(string)
+		if !ok || strings.TrimSpace(text) == "" {
+			continue
+		}
+		summary.Count++
+		if len(summary.Samples) < FreeTextSampleSize {
+			summary.Samples = append(summary.Samples, text)
+		}
+	}
+	return summary
+}
+
+func aggregateCheckbox(q *Question, values []interface{}) *AnswerSummary {
+	summary := &AnswerSummary{Distribution: zeroCounts(q.GetChoices())}
+	for _, value := range values {
+		selected := ToStringSlice(value)
+		if len(selected) == 0 {
+			continue
+		}
+		summary.Count++
+		for _, s := range selected {
+			if _, known := summary.Distribution[s]; known {
+				summary.Distribution[s]++
+			}
+		}
+	}
+	return summary
+}
+
+// aggregateDate counts answers per month (YYYY-MM) and reports the earliest and latest date
+func aggregateDate(q *Question, values []interface{}) *AnswerSummary {
+	summary := &AnswerSummary{Distribution: map[string]int{}}
+	var earliest, latest time.Time
+	for _, value := range values {
+		text, _ := value.(string)
+		date, err := time.Parse(DateLayout, text)
+		if err != nil {
+			continue
+		}
+		if summary.Count == 0 || date.Before(earliest) {
+			earliest = date
+		}
+		if summary.Count == 0 || date.After(latest) {
+			latest = date
+		}
+		summary.Count++
+		summary.Distribution[date.Format("2006-01")]++
+	}
+	if summary.Count > 0 {
+		summary.Earliest = earliest.Format(DateLayout)
+		summary.Latest = latest.Format(DateLayout)
+	}
+	return summary
+}
+
+// aggregateRanking reports the mean position of each choice and how often it was ranked first
+func aggregateRanking(q *Question, values []interface{}) *AnswerSummary {
+	choices := q.GetChoices()
+	summary := &AnswerSummary{
+		Distribution: zeroCounts(choices), // First-place counts
+		AverageRank:  make(map[string]float64, len(choices)),
+	}
+
+	totals := make(map[string]int, len(choices))
+	counts := make(map[string]int, len(choices))
+	for _, value := range values {
+		ranked := ToStringSlice(value)
+		if len(ranked) == 0 {
+			continue
+		}
+		summary.Count++
+		for i, s := range ranked {
+			if _, known := summary.Distribution[s]; !known {
+				continue
+			}
+			if i == 0 {
+				summary.Distribution[s]++
+			}
+			totals[s] += i + 1
+			counts[s]++
+		}
+	}
+
+	for _, choice := range choices {
+		if counts[choice] > 0 {
+			summary.AverageRank[choice] = float64(totals[choice]) / float64(counts[choice])
+		}
+	}
+	return summary
+}
+
+func aggregateMatrix(q *Question, values []interface{}) *AnswerSummary {
+	opts := q.GetMatrixOptions()
+	summary := &AnswerSummary{Rows: make(map[string]map[string]int, len(opts.Rows))}
+	for _, row := range opts.Rows {
+		summary.Rows[row] = zeroCounts(opts.Columns)
+	}
+
+	for _, value := range values {
+		answers, ok := ToMap(value)
+		if !ok {
+			continue
+		}
+		summary.Count++
+		for row, raw := range answers {
+			column, _ := raw.(string)
+			if counts, known := summary.Rows[row]; known {
+				if _, known := counts[column]; known {
+					counts[column]++
+				}
+			}
+		}
+	}
+	return summary
+}
+
+func zeroCounts(keys []string) map[string]int {
+	counts := make(map[string]int, len(keys))
+	for _, k := range keys {
+		counts[k] = 0
+	}
+	return counts
+}
@@ -0,0 +1,43 @@
+package models
+
+import "testing"
+
+func TestAnswerSummarySuppressBelow(t *testing.T) {
+	tests := []struct {
+		name           string
+		count          int
+		minGroupSize   int
+		wantSuppressed bool
+	}{
+		{"no answers", 0, 5, true},
+		{"just below the threshold", 4, 5, true},
+		{"at the threshold", 5, 5, false},
+		{"above the threshold", 9, 5, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := &AnswerSummary{
+				QuestionID:   "q1",
+				QuestionText: "How are you?",
+				QuestionType: QuestionTypeMultipleChoice,
+				Count:        tt.count,
+				Distribution: map[string]int{"Good": tt.count},
+			}
+			summary.SuppressBelow(tt.minGroupSize)
+
+			if summary.Suppressed != tt.wantSuppressed {
+				t.Fatalf("Suppressed = %v, want %v", summary.Suppressed, tt.wantSuppressed)
+			}
+			if summary.QuestionID != "q1" || summary.QuestionText != "How are you?" || summary.QuestionType != QuestionTypeMultipleChoice {
+				t.Errorf("question fields were not kept: %+v", summary)
+			}
+			if tt.wantSuppressed && (summary.Count != 0 || summary.Distribution != nil) {
+				t.Errorf("suppressed summary still reports answers: %+v", summary)
+			}
+			if !tt.wantSuppressed && summary.Distribution["Good"] != tt.count {
+				t.Errorf("summary above the threshold was changed: %+v", summary)
+			}
+		})
+	}
+}
@@ -0,0 +1,286 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Default Likert bounds used when a question does not define min/max
+const (
+	DefaultLikertMin = 1
+	DefaultLikertMax = 5
+)
+
+// ValidateAnswer checks that a submitted value matches the question type and options
+func (q *Question) ValidateAnswer(value interface{}) error {
+	if value == nil {
+		return fmt.Errorf("response value is required")
+	}
+
+	def, ok := GetQuestionTypeDefinition(q.QuestionType)
+	if !ok {
+		return fmt.Errorf("unsupported question type: %s", q.QuestionType)
+	}
+	return def.ValidateAnswer(q, value)
+}
+
+func validateMultipleChoiceAnswer(q *Question, value interface{}) error {
+	selected, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("must be a string matching one of the choices")
+	}
+	choices := q.GetChoices()
+	if !containsString(choices, selected) {
+		return fmt.Errorf("must be one of: %s", strings.Join(choices, ", "))
+	}
+	return nil
+}
+
+func validateLikertScaleAnswer(q *Question, value interface{}) error {
+	n, ok := ToInt(value)
+	if !ok {
+		return fmt.Errorf("must be an integer")
+	}
+	min, max := q.GetLikertRange()
+	if n < min || n > max {
+		return fmt.Errorf("must be between %d and %d", min, max)
+	}
+	return nil
+}
+
+func validateYesNoAnswer(q *Question, value interface{}) error {
+	if _, ok := value.(bool); !ok {
+		return fmt.Errorf("must be a boolean")
+	}
+	return nil
+}
+
+func validateFreeTextAnswer(q *Question, value interface{}) error {
+	text, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("must be a string")
+	}
+	if strings.TrimSpace(text) == "" {
+		return fmt.Errorf("cannot be empty")
+	}
+	return nil
+}
+
+func validateCheckboxAnswer(q *Question, value interface{}) error {
+	selected := ToStringSlice(value)
+	if len(selected) != arrayLen(value) {
+		return fmt.Errorf("must be a list of strings matching the choices")
+	}
+
+	opts := q.GetCheckboxOptions()
+	if len(selected) < opts.MinSelections || len(selected) > opts.MaxSelections {
+		return fmt.Errorf("must select between %d and %d choices", opts.MinSelections, opts.MaxSelections)
+	}
+
+	seen := make(map[string]bool)
+	for _, s := range selected {
+		if !containsString(opts.Choices, s) {
+			return fmt.Errorf("%q is not one of: %s", s, strings.Join(opts.Choices, ", "))
+		}
+		if seen[s] {
+			return fmt.Errorf("%q is selected more than once", s)
+		}
+		seen[s] = true
+	}
+	return nil
+}
+
+func validateNumericAnswer(q *Question, value interface{}) error {
+	n, ok := ToFloat(value)
+	if !ok || math.IsNaN(n) || math.IsInf(n, 0) {
+		return fmt.Errorf("must be a number")
+	}
+
+	opts := q.GetNumericOptions()
+	if opts.Min != nil && n < *opts.Min {
+		return fmt.Errorf("must be at least %g", *opts.Min)
+	}
+	if opts.Max != nil && n > *opts.Max {
+		return fmt.Errorf("must be at most %g", *opts.Max)
+	}
+	if opts.Step != nil {
+		base := 0.0
+		if opts.Min != nil {
+			base = *opts.Min
+		}
+		steps := (n - base) / *opts.Step
+		if math.Abs(steps-math.Round(steps)) > 1e-9 {
+			return fmt.Errorf("must be a multiple of %g from %g", *opts.Step, base)
+		}
+	}
+	return nil
+}
+
+func validateDateAnswer(q *Question, value interface{}) error {
+	text, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("must be a date in YYYY-MM-DD format")
+	}
+	date, err := time.Parse(DateLayout, text)
+	if err != nil {
+		return fmt.Errorf("must be a date in YYYY-MM-DD format")
+	}
+
+	opts := q.GetDateOptions()
+	if opts.MinDate != nil && date.Before(*opts.MinDate) {
+		return fmt.Errorf("must not be before %s", opts.MinDate.Format(DateLayout))
+	}
+	if opts.MaxDate != nil && date.After(*opts.MaxDate) {
+		return fmt.Errorf("must not be after %s", opts.MaxDate.Format(DateLayout))
+	}
+	return nil
+}
+
+func validateRatingAnswer(q *Question, value interface{}) error {
+	n, ok := ToInt(value)
+	if !ok {
+		return fmt.Errorf("must be an integer")
+	}
+	max := q.GetRatingOptions().Max
+	if n < 1 || n > max {
+		return fmt.Errorf("must be between 1 and %d", max)
+	}
+	return nil
+}
+
+func validateNPSAnswer(q *Question, value interface{}) error {
+	n, ok := ToInt(value)
+	if !ok {
+		return fmt.Errorf("must be an integer")
+	}
+	if n < NPSMin || n > NPSMax {
+		return fmt.Errorf("must be between %d and %d", NPSMin, NPSMax)
+	}
+	return nil
+}
+
+func validateRankingAnswer(q *Question, value interface{}) error {
+	ranked := ToStringSlice(value)
+	if len(ranked) != arrayLen(value) {
+		return fmt.Errorf("must be a list of strings ordering the choices")
+	}
+
+	choices := q.GetChoices()
+	if len(ranked) != len(choices) {
+		return fmt.Errorf("must rank all %d choices", len(choices))
+	}
+
+	seen := make(map[string]bool)
+	for _, s := range ranked {
+		if !containsString(choices, s) {
+			return fmt.Errorf("%q is not one of: %s", s, strings.Join(choices, ", "))
+		}
+		if seen[s] {
+			return fmt.Errorf("%q is ranked more than once", s)
+		}
+		seen[s] = true
+	}
+	return nil
+}
+
+func validateMatrixAnswer(q *Question, value interface{}) error {
+	answers, ok := ToMap(value)
+	if !ok {
+		return fmt.Errorf("must be an object mapping each row to a column")
+	}
+
+	opts := q.GetMatrixOptions()
+	for row := range answers {
+		if !containsString(opts.Rows, row) {
+			return fmt.Errorf("%q is not one of the rows: %s", row, strings.Join(opts.Rows, ", "))
+		}
+	}
+	for _, row := range opts.Rows {
+		column, ok := answers[row].(string)
+		if !ok {
+			return fmt.Errorf("row %q must be answered with one of the columns", row)
+		}
+		if !containsString(opts.Columns, column) {
+			return fmt.Errorf("row %q must be one of: %s", row, strings.Join(opts.Columns, ", "))
+		}
+	}
+	return nil
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// GetChoices returns the configured choices for a multiple choice question
+func (q *Question) GetChoices() []string {
+	if q.Options == nil {
+		return []string{}
+	}
+	return ToStringSlice(q.Options["choices"])
+}
+
+// GetLikertRange returns the configured min/max for a Likert scale question
+func (q *Question) GetLikertRange() (min, max int) {
+	min, max = DefaultLikertMin, DefaultLikertMax
+	if q.Options == nil {
+		return min, max
+	}
+	if v, ok := ToInt(q.Options["min"]); ok {
+		min = v
+	}
+	if v, ok := ToInt(q.Options["max"]); ok {
+		max = v
+	}
+	return min, max
+}
+
+// ToInt converts JSON/BSON numeric values to int, rejecting non-integral numbers
+func ToInt(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case int32:
+		return int(v), true
+	case int64:
+		return int(v), true
+	case float64:
+		if v != math.Trunc(v) {
+			return 0, false
+		}
+		return int(v), true
+	case float32:
+		if float64(v) != math.Trunc(float64(v)) {
+			return 0, false
+		}
+		return int(v), true
+	}
+	return 0, false
+}
+
+// ToStringSlice converts JSON/BSON arrays to a string slice, skipping non-string items
+func ToStringSlice(value interface{}) []string {
+	switch v := value.(type) {
+	case []string:
+		return v
+	case primitive.A:
+		return ToStringSlice([]interface{}(v))
+	case []interface{}:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return []string{}
+}
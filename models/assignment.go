@@ -13,6 +13,7 @@ const (
 	AssignmentStatusPending    AssignmentStatus = "pending"
 	AssignmentStatusInProgress AssignmentStatus = "in_progress"
 	AssignmentStatusCompleted  AssignmentStatus = "completed"
+	AssignmentStatusExpired    AssignmentStatus = "expired" // Not completed when the period ended
 )
 
 // UserQuestionnaireAssignment represents a questionnaire assigned to a user with embedded responses
@@ -25,7 +26,10 @@ type UserQuestionnaireAssignment struct {
 	Status                 AssignmentStatus   `bson:"status" json:"status"`
 	StartedAt              *time.Time         `bson:"started_at,omitempty" json:"started_at,omitempty"`
 	CompletedAt            *time.Time         `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+	ExpiredAt              *time.Time         `bson:"expired_at,omitempty" json:"expired_at,omitempty"`
 	Responses              []Response         `bson:"responses" json:"responses"`
+	ResponsesDetached      bool               `bson:"responses_detached,omitempty" json:"responses_detached,omitempty"` // Responses moved to the anonymous store on submit
+	Score                  *AssignmentScore   `bson:"score,omitempty" json:"score,omitempty"`                           // Set on submit when the questionnaire is scored
 }
 
 // NewUserQuestionnaireAssignment creates a new assignment
@@ -59,6 +63,15 @@ func (a *UserQuestionnaireAssignment) Complete() {
 	}
 }
 
+// Redact withholds what an anonymous assignment tells about its respondent from anyone else: the
+// answers, the score, and when it was started and completed. Only the status is left.
+func (a *UserQuestionnaireAssignment) Redact() {
+	a.Responses = []Response{}
+	a.Score = nil
+	a.StartedAt = nil
+	a.CompletedAt = nil
+}
+
 // AddResponse adds or updates a response for a specific question
 func (a *UserQuestionnaireAssignment) AddResponse(response Response) {
 	// Check if response already exists for this question
@@ -88,19 +101,53 @@ func (a *UserQuestionnaireAssignment) GetResponse(questionID string) *Response {
 	return nil
 }
 
-// GetProgress calculates the progress of the assignment
-func (a *UserQuestionnaireAssignment) GetProgress(totalQuestions int) (answered int, total int, percentage float64) {
-	answered = len(a.Responses)
-	total = totalQuestions
+// GetProgress calculates the progress of the assignment over the questions
+// currently visible to the respondent (hidden questions are not counted)
+func (a *UserQuestionnaireAssignment) GetProgress(questionnaire *Questionnaire) (answered int, total int, percentage float64) {
+	visible := questionnaire.VisibleQuestions(a.Responses)
+	for _, q := range visible {
+		if a.GetResponse(q.QuestionID) != nil {
+			answered++
+		}
+	}
+	total = len(visible)
 	if total > 0 {
 		percentage = (float64(answered) / float64(total)) * 100
 	}
 	return answered, total, percentage
 }
 
-// IsComplete checks if all required questions are answered
-func (a *UserQuestionnaireAssignment) IsComplete(totalRequiredQuestions int) bool {
-	return len(a.Responses) >= totalRequiredQuestions
+// GetMissingRequiredQuestions returns the IDs of visible required questions without a response
+func (a *UserQuestionnaireAssignment) GetMissingRequiredQuestions(questionnaire *Questionnaire) []string {
+	missing := []string{}
+	for _, q := range questionnaire.VisibleQuestions(a.Responses) {
+		if q.IsRequired && a.GetResponse(q.QuestionID) == nil {
+			missing = append(missing, q.QuestionID)
+		}
+	}
+	return missing
+}
+
+// DropHiddenResponses removes the responses to questions hidden by the respondent's other answers,
+// so they are neither scored nor reported
+func (a *UserQuestionnaireAssignment) DropHiddenResponses(questionnaire *Questionnaire) {
+	visible := make(map[string]bool)
+	for _, q := range questionnaire.VisibleQuestions(a.Responses) {
+		visible[q.QuestionID] = true
+	}
+
+	kept := make([]Response, 0, len(a.Responses))
+	for _, r := range a.Responses {
+		if visible[r.QuestionID] {
+			kept = append(kept, r)
+		}
+	}
+	a.Responses = kept
+}
+
+// IsComplete checks if all visible required questions are answered
+func (a *UserQuestionnaireAssignment) IsComplete(questionnaire *Questionnaire) bool {
+	return len(a.GetMissingRequiredQuestions(questionnaire)) == 0
 }
 
 // GetTimeToComplete returns the duration taken to complete the assignment
@@ -0,0 +1,256 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CampaignFrequency is how often a campaign starts a new period
+type CampaignFrequency string
+
+const (
+	CampaignFrequencyMonthly   CampaignFrequency = "monthly"
+	CampaignFrequencyQuarterly CampaignFrequency = "quarterly"
+	CampaignFrequencyCron      CampaignFrequency = "cron"
+)
+
+// CampaignAudienceType is who a campaign assigns each period to
+type CampaignAudienceType string
+
+const (
+	CampaignAudienceCompany     CampaignAudienceType = "company"     // Every user of the company when the period starts
+	CampaignAudienceDepartments CampaignAudienceType = "departments" // Every user of the listed departments when the period starts
+	CampaignAudienceUsers       CampaignAudienceType = "users"       // The listed users that are still in the company
+)
+
+// MaxCampaignNameLength is the maximum length of a campaign name
+const MaxCampaignNameLength = 200
+
+// Campaign repeats a questionnaire for a company on a schedule. Each run creates a new
+// CompanyQuestionnaire period, linked back through its CampaignID, and assigns it to the audience.
+type Campaign struct {
+	ID              primitive.ObjectID  `bson:"_id,omitempty" json:"id,omitempty"`
+	CompanyID       primitive.ObjectID  `bson:"company_id" json:"company_id"`
+	QuestionnaireID primitive.ObjectID  `bson:"questionnaire_id" json:"questionnaire_id"`
+	Name            string              `bson:"name" json:"name"`
+	Schedule        CampaignSchedule    `bson:"schedule" json:"schedule"`
+	Audience        CampaignAudience    `bson:"audience" json:"audience"`
+	IsAnonymous     bool                `bson:"is_anonymous" json:"is_anonymous"`
+	MinGroupSize    int                 `bson:"min_group_size,omitempty" json:"min_group_size,omitempty"`
+	StartsAt        time.Time           `bson:"starts_at" json:"starts_at"`                 // Anchor of the schedule; no period starts before it
+	EndsAt          *time.Time          `bson:"ends_at,omitempty" json:"ends_at,omitempty"` // No period starts after it
+	NextRunAt       *time.Time          `bson:"next_run_at" json:"next_run_at"`             // Start of the next period (nil once the campaign is over)
+	RunCount        int                 `bson:"run_count" json:"run_count"`                 // Periods generated so far
+	LastRunAt       *time.Time          `bson:"last_run_at,omitempty" json:"last_run_at,omitempty"`
+	LastError       string              `bson:"last_error,omitempty" json:"last_error,omitempty"`               // Why the last run failed or was skipped
+	PendingPeriodID *primitive.ObjectID `bson:"pending_period_id,omitempty" json:"pending_period_id,omitempty"` // Period generated but not yet assigned to the audience
+	IsActive        bool                `bson:"is_active" json:"is_active"`
+	CreatedBy       string              `bson:"created_by" json:"created_by"`
+	CreatedAt       time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt       time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// CampaignSchedule decides when the periods of a campaign start and how long they last
+type CampaignSchedule struct {
+	Frequency    CampaignFrequency `bson:"frequency" json:"frequency"`
+	Cron         string            `bson:"cron,omitempty" json:"cron,omitempty"`                   // Five-field expression, with frequency "cron"
+	Timezone     string            `bson:"timezone,omitempty" json:"timezone,omitempty"`           // IANA time zone of the schedule (default UTC)
+	DurationDays int               `bson:"duration_days,omitempty" json:"duration_days,omitempty"` // Length of each period (0 = until the next one starts)
+}
+
+// CampaignAudience is who each period of a campaign is assigned to
+type CampaignAudience struct {
+	Type        CampaignAudienceType `bson:"type" json:"type"`
+	Departments []string             `bson:"departments,omitempty" json:"departments,omitempty"`
+	UserIDs     []string             `bson:"user_ids,omitempty" json:"user_ids,omitempty"`
+}
+
+// NewCampaign creates a new active Campaign with timestamps
+func NewCampaign(companyID, questionnaireID primitive.ObjectID, name string, schedule CampaignSchedule, audience CampaignAudience, startsAt time.Time, createdBy string) *Campaign {
+	now := time.Now()
+	return &Campaign{
+		ID:              primitive.NewObjectID(),
+		CompanyID:       companyID,
+		QuestionnaireID: questionnaireID,
+		Name:            name,
+		Schedule:        schedule,
+		Audience:        audience,
+		StartsAt:        startsAt,
+		IsActive:        true,
+		CreatedBy:       createdBy,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
+
+// Validate checks the name, schedule, audience and dates of the campaign
+func (c *Campaign) Validate() error {
+	if c.Name == "" {
+		return fieldError("name", "is required")
+	}
+	if len(c.Name) > MaxCampaignNameLength {
+		return fieldError("name", "must be at most %d characters", MaxCampaignNameLength)
+	}
+	if c.StartsAt.IsZero() {
+		return fieldError("starts_at", "is required")
+	}
+	if c.EndsAt != nil && !c.EndsAt.After(c.StartsAt) {
+		return fieldError("ends_at", "must be after starts_at")
+	}
+	if err := c.Schedule.Validate(); err != nil {
+		return err
+	}
+	if err := c.Audience.Validate(); err != nil {
+		return err
+	}
+	return ValidateMinGroupSize(c.MinGroupSize)
+}
+
+// ScheduleFirstRun sets NextRunAt to the first period start of the campaign
+func (c *Campaign) ScheduleFirstRun() {
+	c.NextRunAt = c.NextRunAfter(c.StartsAt.Add(-time.Nanosecond))
+}
+
+// Reschedule sets NextRunAt to the first period start after t, for a changed schedule or end date
+func (c *Campaign) Reschedule(t time.Time) {
+	if t.Before(c.StartsAt) {
+		c.ScheduleFirstRun()
+		return
+	}
+	c.NextRunAt = c.NextRunAfter(t)
+}
+
+// NextRunAfter returns the first period start after t, or nil if the campaign ends first
+func (c *Campaign) NextRunAfter(t time.Time) *time.Time {
+	next := c.Schedule.NextStart(c.StartsAt, t)
+	if next.IsZero() || (c.EndsAt != nil && next.After(*c.EndsAt)) {
+		return nil
+	}
+	return &next
+}
+
+// PeriodEnd returns the end of the period starting at start, given the start of the next one (nil if none)
+func (c *Campaign) PeriodEnd(start time.Time, next *time.Time) time.Time {
+	nextStart := time.Time{}
+	if next != nil {
+		nextStart = *next
+	} else if following := c.Schedule.NextStart(c.StartsAt, start); !following.IsZero() {
+		// The last period still ends where the schedule would start the next one
+		nextStart = following
+	}
+	return c.Schedule.PeriodEnd(start, nextStart)
+}
+
+// Validate checks the frequency, cron expression, time zone and duration of a schedule
+func (s *CampaignSchedule) Validate() error {
+	switch s.Frequency {
+	case CampaignFrequencyMonthly, CampaignFrequencyQuarterly:
+		if s.Cron != "" {
+			return fieldError("schedule.cron", "is only allowed with frequency cron")
+		}
+	case CampaignFrequencyCron:
+		cron, err := ParseCron(s.Cron)
+		if err != nil {
+			return fieldError("schedule.cron", "%s", err.Error())
+		}
+		if cron.Next(time.Now()).IsZero() {
+			return fieldError("schedule.cron", "expression never matches a date")
+		}
+	default:
+		return fieldError("schedule.frequency", "must be one of: monthly, quarterly, cron")
+	}
+	if _, err := time.LoadLocation(s.Timezone); err != nil {
+		return fieldError("schedule.timezone", "unknown time zone %q", s.Timezone)
+	}
+	if s.DurationDays < 0 {
+		return fieldError("schedule.duration_days", "must be zero or greater")
+	}
+	return nil
+}
+
+// location returns the time zone of the schedule, UTC by default
+func (s *CampaignSchedule) location() *time.Location {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// NextStart returns the first period start after t, or the zero time if there is none. Monthly and
+// quarterly periods start on the day and time of anchor, on the last day of shorter months.
+func (s *CampaignSchedule) NextStart(anchor, t time.Time) time.Time {
+	loc := s.location()
+	anchor, t = anchor.In(loc), t.In(loc)
+
+	switch s.Frequency {
+	case CampaignFrequencyCron:
+		cron, err := ParseCron(s.Cron)
+		if err != nil {
+			return time.Time{}
+		}
+		return cron.Next(t)
+	case CampaignFrequencyMonthly, CampaignFrequencyQuarterly:
+		step := 1
+		if s.Frequency == CampaignFrequencyQuarterly {
+			step = 3
+		}
+		months := (t.Year()-anchor.Year())*12 + int(t.Month()-anchor.Month())
+		k := months/step*step - step
+		if k < 0 {
+			k = 0
+		}
+		for {
+			start := addMonthsClamped(anchor, k)
+			if start.After(t) {
+				return start
+			}
+			k += step
+		}
+	}
+	return time.Time{}
+}
+
+// PeriodEnd returns the end of the period starting at start. Periods never overlap: without a
+// duration, or with one that reaches the next start, a period ends just before the next one begins.
+func (s *CampaignSchedule) PeriodEnd(start, nextStart time.Time) time.Time {
+	if s.DurationDays > 0 {
+		end := start.In(s.location()).AddDate(0, 0, s.DurationDays)
+		if nextStart.IsZero() || end.Before(nextStart) {
+			return end
+		}
+	}
+	if nextStart.IsZero() {
+		return start.AddDate(0, 1, 0).Add(-time.Second)
+	}
+	return nextStart.Add(-time.Second)
+}
+
+// addMonthsClamped adds months to t, keeping its day unless the target month is shorter
+func addMonthsClamped(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	lastDay := time.Date(year, month+time.Month(months)+1, 0, 0, 0, 0, 0, t.Location()).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(year, month+time.Month(months), day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+// Validate checks that the audience lists departments or users when its type needs them
+func (a *CampaignAudience) Validate() error {
+	switch a.Type {
+	case CampaignAudienceCompany:
+	case CampaignAudienceDepartments:
+		if len(a.Departments) == 0 {
+			return fieldError("audience.departments", "at least one department is required")
+		}
+	case CampaignAudienceUsers:
+		if len(a.UserIDs) == 0 {
+			return fieldError("audience.user_ids", "at least one user is required")
+		}
+	default:
+		return fieldError("audience.type", "must be one of: company, departments, users")
+	}
+	return nil
+}
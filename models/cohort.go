@@ -0,0 +1,80 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	// DefaultMinGroupSize is the minimum cohort size used when neither the company nor the
+	// company questionnaire configures one
+	DefaultMinGroupSize = 5
+	// MaxMinGroupSize is the largest minimum cohort size that can be configured
+	MaxMinGroupSize = 100
+	// OtherSegment is the segment that report breakdowns merge small cohorts into
+	OtherSegment = "Other"
+)
+
+// ValidateMinGroupSize checks a configured minimum cohort size (0 = inherit the default)
+func ValidateMinGroupSize(size int) error {
+	if size < 0 || size > MaxMinGroupSize {
+		return fmt.Errorf("invalid min_group_size: must be between 0 and %d", MaxMinGroupSize)
+	}
+	return nil
+}
+
+// ResolveMinGroupSize returns the minimum cohort size that applies to a company questionnaire's
+// reports: its own setting, else the company's, else DefaultMinGroupSize
+func ResolveMinGroupSize(company *Company, cq *CompanyQuestionnaire) int {
+	if cq != nil && cq.MinGroupSize > 0 {
+		return cq.MinGroupSize
+	}
+	if company != nil && company.MinGroupSize > 0 {
+		return company.MinGroupSize
+	}
+	return DefaultMinGroupSize
+}
+
+// GroupSegments decides under which segment each segment of a breakdown is reported, given the
+// number of people in each. Segments smaller than minSize are merged into OtherSegment. When
+// "Other" would itself be too small, the smallest remaining segments are folded into it, since a
+// single small group could otherwise be derived from the total. If "Other" still stays below
+// minSize its segments map to "", meaning they are suppressed.
+func GroupSegments(sizes map[string]int, minSize int) map[string]string {
+	groups := make(map[string]string, len(sizes))
+	other := 0
+	visible := []string{}
+	for name, size := range sizes {
+		if size < minSize {
+			groups[name] = OtherSegment
+			other += size
+		} else {
+			groups[name] = name
+			visible = append(visible, name)
+		}
+	}
+	if other == 0 {
+		return groups
+	}
+
+	sort.Slice(visible, func(i, j int) bool {
+		if sizes[visible[i]] != sizes[visible[j]] {
+			return sizes[visible[i]] < sizes[visible[j]]
+		}
+		return visible[i] < visible[j]
+	})
+	for other < minSize && len(visible) > 0 {
+		groups[visible[0]] = OtherSegment
+		other += sizes[visible[0]]
+		visible = visible[1:]
+	}
+
+	if other < minSize {
+		for name, group := range groups {
+			if group == OtherSegment {
+				groups[name] = ""
+			}
+		}
+	}
+	return groups
+}
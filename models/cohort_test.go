@@ -0,0 +1,79 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGroupSegments(t *testing.T) {
+	tests := []struct {
+		name    string
+		sizes   map[string]int
+		minSize int
+		want    map[string]string
+	}{
+		{
+			name:    "no segments",
+			sizes:   map[string]int{},
+			minSize: 5,
+			want:    map[string]string{},
+		},
+		{
+			name:    "every segment at or above the threshold",
+			sizes:   map[string]int{"Sales": 5, "IT": 12},
+			minSize: 5,
+			want:    map[string]string{"Sales": "Sales", "IT": "IT"},
+		},
+		{
+			name:    "small segments that reach the threshold together",
+			sizes:   map[string]int{"Sales": 4, "Legal": 3, "IT": 12},
+			minSize: 5,
+			want:    map[string]string{"Sales": OtherSegment, "Legal": OtherSegment, "IT": "IT"},
+		},
+		{
+			name:    "one segment just below the threshold absorbs the smallest visible one",
+			sizes:   map[string]int{"Sales": 4, "Legal": 5, "IT": 12},
+			minSize: 5,
+			want:    map[string]string{"Sales": OtherSegment, "Legal": OtherSegment, "IT": "IT"},
+		},
+		{
+			name:    "one tiny segment absorbs the smallest visible one",
+			sizes:   map[string]int{"Sales": 1, "Legal": 6, "IT": 8},
+			minSize: 5,
+			want:    map[string]string{"Sales": OtherSegment, "Legal": OtherSegment, "IT": "IT"},
+		},
+		{
+			name:    "visible segments of the same size are absorbed by name",
+			sizes:   map[string]int{"Sales": 1, "Legal": 6, "IT": 6},
+			minSize: 5,
+			want:    map[string]string{"Sales": OtherSegment, "IT": OtherSegment, "Legal": "Legal"},
+		},
+		{
+			name:    "one visible segment and one small one are merged",
+			sizes:   map[string]int{"Sales": 2, "IT": 10},
+			minSize: 5,
+			want:    map[string]string{"Sales": OtherSegment, "IT": OtherSegment},
+		},
+		{
+			name:    "everything small is suppressed",
+			sizes:   map[string]int{"Sales": 2, "IT": 1},
+			minSize: 5,
+			want:    map[string]string{"Sales": "", "IT": ""},
+		},
+		{
+			name:    "a single small segment is suppressed",
+			sizes:   map[string]int{"Sales": 4},
+			minSize: 5,
+			want:    map[string]string{"Sales": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GroupSegments(tt.sizes, tt.minSize)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GroupSegments(%v, %d) = %v, want %v", tt.sizes, tt.minSize, got, tt.want)
+			}
+		})
+	}
+}
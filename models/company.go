@@ -8,10 +8,12 @@ import (
 
 // Company represents a company entity
 type Company struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-	Name      string             `bson:"name" json:"name" validate:"required,min=3,max=200"`
-	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	Name           string             `bson:"name" json:"name" validate:"required,min=3,max=200"`
+	MinGroupSize   int                `bson:"min_group_size,omitempty" json:"min_group_size,omitempty"`   // Minimum cohort size in report breakdowns (0 = default)
+	ReminderPolicy *ReminderPolicy    `bson:"reminder_policy,omitempty" json:"reminder_policy,omitempty"` // Unset uses DefaultReminderPolicy
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt      time.Time          `bson:"updated_at" json:"updated_at"`
 }
 
 // NewCompany creates a new Company with timestamps
@@ -24,3 +26,11 @@ func NewCompany(name string) *Company {
 		UpdatedAt: now,
 	}
 }
+
+// EffectiveReminderPolicy returns the company's reminder policy, or the default one
+func (c *Company) EffectiveReminderPolicy() ReminderPolicy {
+	if c.ReminderPolicy != nil {
+		return *c.ReminderPolicy
+	}
+	return DefaultReminderPolicy()
+}
@@ -0,0 +1,156 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule is a parsed five-field cron expression: minute, hour, day of month, month and day
+// of week. Fields accept *, numbers, ranges (1-5), lists (1,15) and steps (*/15, 1-10/3). Day of
+// week runs from 0 (Sunday) to 6; 7 is also Sunday. As in cron, when both day fields are
+// restricted a day matches if either of them does; a day field starting with * (such as */2)
+// counts as unrestricted.
+type CronSchedule struct {
+	minute, hour, dayOfMonth, month, dayOfWeek uint64
+	anyDayOfMonth, anyDayOfWeek                bool
+}
+
+// cronField describes the range of a cron field
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = []cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// ParseCron parses a five-field cron expression
+func ParseCron(expr string) (*CronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("invalid cron expression: expected 5 fields, got %d", len(fields))
+	}
+
+	bits := make([]uint64, len(fields))
+	for i, field := range fields {
+		b, err := parseCronField(field, cronFields[i])
+		if err != nil {
+			return nil, err
+		}
+		bits[i] = b
+	}
+
+	schedule := &CronSchedule{
+		minute:        bits[0],
+		hour:          bits[1],
+		dayOfMonth:    bits[2],
+		month:         bits[3],
+		dayOfWeek:     bits[4],
+		anyDayOfMonth: strings.HasPrefix(fields[2], "*"),
+		anyDayOfWeek:  strings.HasPrefix(fields[4], "*"),
+	}
+	if schedule.dayOfWeek&(1<<7) != 0 {
+		schedule.dayOfWeek |= 1
+	}
+	return schedule, nil
+}
+
+// parseCronField returns the set of values a cron field matches, as a bitset
+func parseCronField(field string, spec cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid cron expression: bad step in %s field %q", spec.name, part)
+			}
+			rng, step = part[:i], n
+		}
+
+		low, high := spec.min, spec.max
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			bounds := strings.SplitN(rng, "-", 2)
+			a, errA := strconv.Atoi(bounds[0])
+			b, errB := strconv.Atoi(bounds[1])
+			if errA != nil || errB != nil || a > b {
+				return 0, fmt.Errorf("invalid cron expression: bad range in %s field %q", spec.name, part)
+			}
+			low, high = a, b
+		default:
+			n, err := strconv.Atoi(rng)
+			if err != nil {
+				return 0, fmt.Errorf("invalid cron expression: bad value in %s field %q", spec.name, part)
+			}
+			low = n
+			if step == 1 {
+				high = n
+			}
+		}
+		if low < spec.min || high > spec.max {
+			return 0, fmt.Errorf("invalid cron expression: %s must be between %d and %d", spec.name, spec.min, spec.max)
+		}
+
+		for v := low; v <= high; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// Next returns the first time after t that matches the schedule, in t's location, or the zero
+// time if there is none within five years. Times skipped when clocks go forward never match, and
+// times repeated when they go back match once.
+func (c *CronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		year, month, day := t.Date()
+		switch {
+		case c.month&(1<<uint(month)) == 0:
+			t = cronAdvance(t, time.Date(year, month+1, 1, 0, 0, 0, 0, loc))
+		case !c.matchesDay(t):
+			t = cronAdvance(t, time.Date(year, month, day+1, 0, 0, 0, 0, loc))
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			t = t.Add(time.Duration(60-t.Minute()) * time.Minute)
+		case c.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		case !t.Equal(time.Date(year, month, day, t.Hour(), t.Minute(), 0, 0, loc)):
+			// Second occurrence of a wall clock time repeated when clocks went back
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// cronAdvance returns next, the start of the following day or month, moved on by whole hours
+// when a clock change put that midnight at or before t
+func cronAdvance(t, next time.Time) time.Time {
+	for !next.After(t) {
+		next = next.Add(time.Hour)
+	}
+	return next
+}
+
+// matchesDay checks the day of month and day of week fields
+func (c *CronSchedule) matchesDay(t time.Time) bool {
+	dom := c.dayOfMonth&(1<<uint(t.Day())) != 0
+	dow := c.dayOfWeek&(1<<uint(t.Weekday())) != 0
+	if c.anyDayOfMonth || c.anyDayOfWeek {
+		return dom && dow
+	}
+	return dom || dow
+}
@@ -0,0 +1,141 @@
+package models
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr bool
+	}{
+		{"* * * * *", false},
+		{"*/15 * * * *", false},
+		{"0 9 1,15 * 1-5", false},
+		{"0 0 */2 * */3", false},
+		{"5-50/5 0-23/6 1-31 1-12 0-7", false},
+		{"0 0 * * 7", false},
+		{"", true},
+		{"* * * *", true},
+		{"* * * * * *", true},
+		{"*/0 * * * *", true},
+		{"*/x * * * *", true},
+		{"5-1 * * * *", true},
+		{"a * * * *", true},
+		{"60 * * * *", true},
+		{"* 24 * * *", true},
+		{"* * 0 * *", true},
+		{"* * 32 * *", true},
+		{"* * * 0 *", true},
+		{"* * * 13 *", true},
+		{"* * * * 8", true},
+		{"1-2-3 * * * *", true},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseCron(tt.expr)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseCron(%q) error = %v, wantErr %v", tt.expr, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	santiago, err := time.LoadLocation("America/Santiago")
+	if err != nil {
+		t.Fatal(err)
+	}
+	utc := func(year int, month time.Month, day, hour, min int) time.Time {
+		return time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"every 15 minutes", "*/15 * * * *", utc(2024, 9, 2, 10, 7), utc(2024, 9, 2, 10, 15)},
+		{"strictly after a match", "*/15 * * * *", utc(2024, 9, 2, 10, 15), utc(2024, 9, 2, 10, 30)},
+		{"seconds are ignored", "*/15 * * * *", utc(2024, 9, 2, 10, 15).Add(30 * time.Second), utc(2024, 9, 2, 10, 30)},
+		{"hour rolls over the day", "0 9 * * *", utc(2024, 9, 2, 9, 0), utc(2024, 9, 3, 9, 0)},
+		{"monthly", "0 9 1 * *", utc(2024, 1, 1, 9, 0), utc(2024, 2, 1, 9, 0)},
+		{"listed months", "0 0 1 1,7 *", utc(2024, 2, 1, 0, 0), utc(2024, 7, 1, 0, 0)},
+		{"year rolls over", "0 0 1 1 *", utc(2024, 7, 1, 0, 0), utc(2025, 1, 1, 0, 0)},
+		{"leap day", "0 0 29 2 *", utc(2024, 3, 1, 0, 0), utc(2028, 2, 29, 0, 0)},
+		{"day that never exists", "0 0 30 2 *", utc(2024, 1, 1, 0, 0), time.Time{}},
+		{"day of month only", "0 0 10 * *", utc(2024, 9, 3, 0, 0), utc(2024, 9, 10, 0, 0)},
+		{"day of week only", "0 0 * * 1", utc(2024, 9, 3, 0, 0), utc(2024, 9, 9, 0, 0)},
+		{"7 is Sunday", "0 0 * * 7", utc(2024, 9, 3, 0, 0), utc(2024, 9, 8, 0, 0)},
+		{"both day fields match either: weekday first", "0 0 10 * 1", utc(2024, 9, 3, 0, 0), utc(2024, 9, 9, 0, 0)},
+		{"both day fields match either: day of month first", "0 0 10 * 1", utc(2024, 9, 9, 0, 0), utc(2024, 9, 10, 0, 0)},
+		{"day of week range", "0 9 * * 1-5", utc(2024, 9, 6, 9, 0), utc(2024, 9, 9, 9, 0)},
+		{"step in day of month is unrestricted", "0 0 */10 * 1", utc(2024, 9, 2, 0, 0), utc(2024, 10, 21, 0, 0)},
+		{"step in day of week is unrestricted", "0 0 10 * */2", utc(2024, 9, 11, 0, 0), utc(2024, 10, 10, 0, 0)},
+		{
+			"skipped hour when clocks go forward",
+			"30 2 * * *",
+			time.Date(2024, 3, 9, 23, 0, 0, 0, newYork),
+			time.Date(2024, 3, 11, 2, 30, 0, 0, newYork),
+		},
+		{
+			"hour after clocks go forward",
+			"0 3 * * *",
+			time.Date(2024, 3, 10, 0, 0, 0, 0, newYork),
+			time.Date(2024, 3, 10, 3, 0, 0, 0, newYork),
+		},
+		{
+			"first occurrence of a repeated hour",
+			"30 1 * * *",
+			time.Date(2024, 11, 3, 0, 0, 0, 0, newYork),
+			time.Date(2024, 11, 3, 1, 30, 0, 0, newYork),
+		},
+		{
+			"repeated hour matches once",
+			"30 1 * * *",
+			time.Date(2024, 11, 3, 1, 30, 0, 0, newYork),
+			time.Date(2024, 11, 4, 1, 30, 0, 0, newYork),
+		},
+		{
+			"repeated half hours are skipped",
+			"*/30 * * * *",
+			time.Date(2024, 11, 3, 1, 30, 0, 0, newYork),
+			time.Date(2024, 11, 3, 2, 0, 0, 0, newYork),
+		},
+		{
+			"missing midnight",
+			"0 0 * * *",
+			time.Date(2024, 9, 7, 12, 0, 0, 0, santiago),
+			time.Date(2024, 9, 9, 0, 0, 0, 0, santiago),
+		},
+		{
+			"day starting after a missing midnight",
+			"0 12 8 9 *",
+			time.Date(2024, 9, 7, 12, 0, 0, 0, santiago),
+			time.Date(2024, 9, 8, 12, 0, 0, 0, santiago),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCron(%q): %v", tt.expr, err)
+			}
+
+			got := schedule.Next(tt.from)
+			if !got.Equal(tt.want) {
+				t.Fatalf("Next(%v) = %v, want %v", tt.from, got, tt.want)
+			}
+			if !got.IsZero() && got.Location() != tt.from.Location() {
+				t.Errorf("Next(%v) is in %v, want %v", tt.from, got.Location(), tt.from.Location())
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MaxDimensionNameLength is the longest dimension name accepted
+const MaxDimensionNameLength = 100
+
+// DimensionTag assigns a question to a dimension (subscale), e.g. "exhaustion"
+type DimensionTag struct {
+	Dimension string `bson:"dimension" json:"dimension"`
+	Reverse   bool   `bson:"reverse,omitempty" json:"reverse,omitempty"` // Points are mirrored within the question's point range
+}
+
+// DimensionScore is the score of one dimension in a submitted assignment
+type DimensionScore struct {
+	Dimension string  `bson:"dimension" json:"dimension"`
+	Total     float64 `bson:"total" json:"total"`
+	Mean      float64 `bson:"mean" json:"mean"`         // Total over the answered questions
+	Answered  int     `bson:"answered" json:"answered"` // Tagged questions that were answered
+}
+
+// ValidateDimensions checks the dimension tags of a question whose options are already normalized.
+// Dimension names are trimmed in place.
+func (q *Question) ValidateDimensions() error {
+	if len(q.Dimensions) == 0 {
+		return nil
+	}
+
+	def, ok := GetQuestionTypeDefinition(q.QuestionType)
+	if !ok || def.Score == nil {
+		return fieldError("dimensions", "question type %s cannot be scored", q.QuestionType)
+	}
+
+	seen := make(map[string]bool, len(q.Dimensions))
+	for i := range q.Dimensions {
+		tag := &q.Dimensions[i]
+		tag.Dimension = strings.TrimSpace(tag.Dimension)
+		field := fmt.Sprintf("dimensions[%d]", i)
+		if tag.Dimension == "" {
+			return fieldError(field+".dimension", "is required")
+		}
+		if len(tag.Dimension) > MaxDimensionNameLength {
+			return fieldError(field+".dimension", "must be at most %d characters", MaxDimensionNameLength)
+		}
+		if seen[tag.Dimension] {
+			return fieldError(field+".dimension", "duplicate dimension %q", tag.Dimension)
+		}
+		seen[tag.Dimension] = true
+
+		if tag.Reverse {
+			if _, _, ok := def.PointRange(q); !ok {
+				return fieldError(field+".reverse", "requires a bounded point range (numeric questions need min and max)")
+			}
+		}
+	}
+	return nil
+}
+
+// DimensionNames returns the dimensions the questionnaire's questions are tagged with, sorted
+func (q *Questionnaire) DimensionNames() []string {
+	seen := map[string]bool{}
+	names := []string{}
+	for i := range q.Questions {
+		for _, tag := range q.Questions[i].Dimensions {
+			if !seen[tag.Dimension] {
+				seen[tag.Dimension] = true
+				names = append(names, tag.Dimension)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
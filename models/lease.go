@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Lease is a named lock held by one service instance until it expires, used to elect the
+// instance that runs background jobs
+type Lease struct {
+	Name       string    `bson:"_id" json:"name"`
+	Holder     string    `bson:"holder" json:"holder"` // Instance that holds the lease
+	AcquiredAt time.Time `bson:"acquired_at" json:"acquired_at"`
+	RenewedAt  time.Time `bson:"renewed_at" json:"renewed_at"`
+	ExpiresAt  time.Time `bson:"expires_at" json:"expires_at"`
+}
@@ -0,0 +1,160 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NotificationEvent is what a notification tells its recipient about
+type NotificationEvent string
+
+const (
+	NotificationEventAssigned  NotificationEvent = "assigned"  // A questionnaire was assigned to the user
+	NotificationEventReminder  NotificationEvent = "reminder"  // The period ends in a few days and the assignment is unfinished
+	NotificationEventOverdue   NotificationEvent = "overdue"   // The period ended before the assignment was completed
+	NotificationEventCompleted NotificationEvent = "completed" // The user submitted the assignment
+)
+
+// NotificationStatus is the delivery state of a notification in the outbox
+type NotificationStatus string
+
+const (
+	NotificationStatusPending NotificationStatus = "pending" // Waiting for its first or next delivery attempt
+	NotificationStatusSent    NotificationStatus = "sent"
+	NotificationStatusFailed  NotificationStatus = "failed" // Gave up after MaxNotificationAttempts or a permanent error
+)
+
+// MaxNotificationAttempts is how many times delivery of a notification is tried
+const MaxNotificationAttempts = 5
+
+// Notification is a rendered message in the notification outbox. DedupKey is unique, so the same
+// event is never queued twice for an assignment.
+type Notification struct {
+	ID                     primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	DedupKey               string             `bson:"dedup_key" json:"dedup_key"`
+	Event                  NotificationEvent  `bson:"event" json:"event"`
+	CompanyID              primitive.ObjectID `bson:"company_id" json:"company_id"`
+	CompanyQuestionnaireID primitive.ObjectID `bson:"company_questionnaire_id" json:"company_questionnaire_id"`
+	AssignmentID           primitive.ObjectID `bson:"assignment_id" json:"assignment_id"`
+	UserID                 string             `bson:"user_id" json:"user_id"`
+	Recipient              string             `bson:"recipient,omitempty" json:"recipient,omitempty"` // Email address, when known
+	Subject                string             `bson:"subject" json:"subject"`
+	Body                   string             `bson:"body" json:"body"`
+	Status                 NotificationStatus `bson:"status" json:"status"`
+	Attempts               int                `bson:"attempts" json:"attempts"`
+	NextAttemptAt          time.Time          `bson:"next_attempt_at" json:"next_attempt_at"`
+	LastError              string             `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	Transport              string             `bson:"transport,omitempty" json:"transport,omitempty"` // Transport that delivered it
+	SentAt                 *time.Time         `bson:"sent_at,omitempty" json:"sent_at,omitempty"`
+	CreatedAt              time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt              time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// NewNotification creates a pending notification about an assignment, due right away
+func NewNotification(event NotificationEvent, dedupKey string, cq *CompanyQuestionnaire, assignment *UserQuestionnaireAssignment) *Notification {
+	now := time.Now()
+	return &Notification{
+		ID:                     primitive.NewObjectID(),
+		DedupKey:               dedupKey,
+		Event:                  event,
+		CompanyID:              cq.CompanyID,
+		CompanyQuestionnaireID: cq.ID,
+		AssignmentID:           assignment.ID,
+		UserID:                 assignment.UserID,
+		Status:                 NotificationStatusPending,
+		NextAttemptAt:          now,
+		CreatedAt:              now,
+		UpdatedAt:              now,
+	}
+}
+
+// NotificationRetryDelay returns how long to wait before the next delivery attempt, doubling from
+// one minute up to an hour
+func NotificationRetryDelay(attempts int) time.Duration {
+	delay := time.Minute
+	for i := 1; i < attempts && delay < time.Hour; i++ {
+		delay *= 2
+	}
+	if delay > time.Hour {
+		delay = time.Hour
+	}
+	return delay
+}
+
+// MaxReminderDays is the furthest before the end of a period a reminder can be sent
+const MaxReminderDays = 90
+
+// MaxReminders is the maximum number of reminders per period
+const MaxReminders = 5
+
+// ReminderPolicy decides which reminders a company's employees get about unfinished assignments
+type ReminderPolicy struct {
+	Enabled    bool  `bson:"enabled" json:"enabled"`
+	DaysBefore []int `bson:"days_before" json:"days_before"` // Days before the end of the period to remind on
+	Overdue    bool  `bson:"overdue" json:"overdue"`         // Notify when the period ended with the assignment unfinished
+}
+
+// DefaultReminderPolicy is the policy of companies that have not set their own
+func DefaultReminderPolicy() ReminderPolicy {
+	return ReminderPolicy{Enabled: true, DaysBefore: []int{3, 1}, Overdue: true}
+}
+
+// Validate checks the reminder days of a policy and sorts them, furthest first
+func (p *ReminderPolicy) Validate() error {
+	if len(p.DaysBefore) > MaxReminders {
+		return fieldError("reminder_policy.days_before", "at most %d reminders are allowed", MaxReminders)
+	}
+	seen := make(map[int]bool, len(p.DaysBefore))
+	for _, days := range p.DaysBefore {
+		if days < 1 || days > MaxReminderDays {
+			return fieldError("reminder_policy.days_before", "must be between 1 and %d", MaxReminderDays)
+		}
+		if seen[days] {
+			return fieldError("reminder_policy.days_before", "duplicate value %d", days)
+		}
+		seen[days] = true
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(p.DaysBefore)))
+	return nil
+}
+
+// DueReminder returns the reminder that is due at now for an assignment of a period ending at
+// periodEnd: the latest reminder day already reached. Reminder days reached before the user was
+// assigned are skipped, and so are earlier ones missed while nothing was running.
+func (p *ReminderPolicy) DueReminder(assignedAt, periodEnd, now time.Time) (int, bool) {
+	if !p.Enabled || !now.Before(periodEnd) {
+		return 0, false
+	}
+	due, found := 0, false
+	for _, days := range p.DaysBefore {
+		remindAt := periodEnd.AddDate(0, 0, -days)
+		if !now.Before(remindAt) && assignedAt.Before(remindAt) && (!found || days < due) {
+			due, found = days, true
+		}
+	}
+	return due, found
+}
+
+// MaxDaysBefore returns the furthest reminder day of the policy, or 0 without reminders
+func (p *ReminderPolicy) MaxDaysBefore() int {
+	max := 0
+	for _, days := range p.DaysBefore {
+		if days > max {
+			max = days
+		}
+	}
+	return max
+}
+
+// ReminderDedupKey identifies the reminder sent days before the end of an assignment's period
+func ReminderDedupKey(assignmentID primitive.ObjectID, days int) string {
+	return fmt.Sprintf("%s:%s:%d", NotificationEventReminder, assignmentID.Hex(), days)
+}
+
+// NotificationDedupKey identifies the notification of an event that happens once per assignment
+func NotificationDedupKey(event NotificationEvent, assignmentID primitive.ObjectID) string {
+	return fmt.Sprintf("%s:%s", event, assignmentID.Hex())
+}
@@ -0,0 +1,58 @@
+package models
+
+// Net Promoter Score scale and category bounds
+const (
+	NPSMin          = 0
+	NPSMax          = 10
+	NPSPromoterMin  = 9 // 9-10 are promoters
+	NPSDetractorMax = 6 // 0-6 are detractors, 7-8 passives
+)
+
+// NPSResult counts promoters, passives and detractors and derives the Net Promoter Score
+type NPSResult struct {
+	Responses    int     `json:"responses"`
+	Promoters    int     `json:"promoters"`
+	Passives     int     `json:"passives"`
+	Detractors   int     `json:"detractors"`
+	PromoterPct  float64 `json:"promoters_percentage"`
+	PassivePct   float64 `json:"passives_percentage"`
+	DetractorPct float64 `json:"detractors_percentage"`
+	Score        float64 `json:"nps"` // %promoters - %detractors, from -100 to 100
+}
+
+// Add counts a score; values outside the 0-10 scale are ignored
+func (r *NPSResult) Add(score int) {
+	if score < NPSMin || score > NPSMax {
+		return
+	}
+	r.Responses++
+	switch {
+	case score >= NPSPromoterMin:
+		r.Promoters++
+	case score <= NPSDetractorMax:
+		r.Detractors++
+	default:
+		r.Passives++
+	}
+	r.calculate()
+}
+
+// Merge adds the counts of another result
+func (r *NPSResult) Merge(other NPSResult) {
+	r.Responses += other.Responses
+	r.Promoters += other.Promoters
+	r.Passives += other.Passives
+	r.Detractors += other.Detractors
+	r.calculate()
+}
+
+func (r *NPSResult) calculate() {
+	if r.Responses == 0 {
+		return
+	}
+	total := float64(r.Responses)
+	r.PromoterPct = float64(r.Promoters) / total * 100
+	r.PassivePct = float64(r.Passives) / total * 100
+	r.DetractorPct = float64(r.Detractors) / total * 100
+	r.Score = r.PromoterPct - r.DetractorPct
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OrgChart is the supervisor tree of a company
+type OrgChart struct {
+	CompanyID  primitive.ObjectID `json:"company_id"`
+	TotalUsers int                `json:"total_users"`
+	Roots      []*OrgChartNode    `json:"roots"`            // Users without a supervisor in the company
+	Issues     []OrgChartIssue    `json:"issues,omitempty"` // Links that break the tree, from data older than its validation
+}
+
+// OrgChartNode is a user in an org chart with the users that report to them
+type OrgChartNode struct {
+	UserID     string          `json:"user_id"`
+	Department string          `json:"department,omitempty"`
+	Reports    []*OrgChartNode `json:"reports"`
+}
+
+// OrgChartIssue is a supervisor link that does not fit in the tree
+type OrgChartIssue struct {
+	UserID       string `json:"user_id"`
+	SupervisorID string `json:"supervisor_id"`
+	Message      string `json:"message"`
+}
+
+// NewOrgChart builds the org chart of a company from its users. Users whose supervisor is not in
+// the company are shown as roots, and users caught in a supervisor cycle are left out; both are
+// listed in Issues.
+func NewOrgChart(companyID primitive.ObjectID, users []*UserMetadata) *OrgChart {
+	chart := &OrgChart{CompanyID: companyID, TotalUsers: len(users), Roots: []*OrgChartNode{}}
+
+	nodes := make(map[string]*OrgChartNode, len(users))
+	for _, user := range users {
+		nodes[user.ID] = &OrgChartNode{UserID: user.ID, Department: user.Department, Reports: []*OrgChartNode{}}
+	}
+
+	for _, user := range users {
+		node := nodes[user.ID]
+		if !user.HasSupervisor() {
+			chart.Roots = append(chart.Roots, node)
+			continue
+		}
+		supervisor, ok := nodes[user.SupervisorID]
+		if !ok {
+			chart.Roots = append(chart.Roots, node)
+			chart.Issues = append(chart.Issues, OrgChartIssue{UserID: user.ID, SupervisorID: user.SupervisorID, Message: "supervisor is not in this company"})
+			continue
+		}
+		supervisor.Reports = append(supervisor.Reports, node)
+	}
+
+	// Whatever cannot be reached from a root hangs off a cycle
+	reached := make(map[string]bool, len(users))
+	var visit func(nodes []*OrgChartNode)
+	visit = func(nodes []*OrgChartNode) {
+		sortOrgChartNodes(nodes)
+		for _, node := range nodes {
+			reached[node.UserID] = true
+			visit(node.Reports)
+		}
+	}
+	visit(chart.Roots)
+	for _, user := range users {
+		if !reached[user.ID] {
+			chart.Issues = append(chart.Issues, OrgChartIssue{UserID: user.ID, SupervisorID: user.SupervisorID, Message: "part of or below a supervisor cycle"})
+		}
+	}
+
+	return chart
+}
+
+// sortOrgChartNodes orders nodes by department, then user ID
+func sortOrgChartNodes(nodes []*OrgChartNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Department != nodes[j].Department {
+			return nodes[i].Department < nodes[j].Department
+		}
+		return nodes[i].UserID < nodes[j].UserID
+	})
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OutboxStatus is the state of a domain event in the outbox
+type OutboxStatus string
+
+const (
+	OutboxPending   OutboxStatus = "pending" // Waiting to be relayed to the sink
+	OutboxPublished OutboxStatus = "published"
+)
+
+// OutboxEvent is a domain event stored in the same transaction as the change it reports, and
+// relayed to the configured sink afterwards. Events are never given up on: a sink that keeps
+// failing delays them, but they are not lost.
+type OutboxEvent struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	Type          WebhookEventType   `bson:"type" json:"type"`
+	CompanyID     primitive.ObjectID `bson:"company_id" json:"company_id"`
+	AggregateID   primitive.ObjectID `bson:"aggregate_id" json:"aggregate_id"` // Assignment or company questionnaire the event is about
+	Payload       string             `bson:"payload" json:"payload"`           // JSON WebhookEvent whose ID is the event ID
+	Status        OutboxStatus       `bson:"status" json:"status"`
+	Attempts      int                `bson:"attempts" json:"attempts"`
+	NextAttemptAt time.Time          `bson:"next_attempt_at" json:"next_attempt_at"`
+	LastError     string             `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	PublishedAt   *time.Time         `bson:"published_at,omitempty" json:"published_at,omitempty"`
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt     time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// NewOutboxEvent creates a pending domain event about an aggregate of a company, due right away
+func NewOutboxEvent(companyID, aggregateID primitive.ObjectID, eventType WebhookEventType, data interface{}) (*OutboxEvent, error) {
+	now := time.Now()
+	id := primitive.NewObjectID()
+
+	payload, err := json.Marshal(WebhookEvent{
+		ID:        id.Hex(),
+		Type:      eventType,
+		CompanyID: companyID.Hex(),
+		CreatedAt: now.UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+
+	return &OutboxEvent{
+		ID:            id,
+		Type:          eventType,
+		CompanyID:     companyID,
+		AggregateID:   aggregateID,
+		Payload:       string(payload),
+		Status:        OutboxPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}, nil
+}
+
+// OutboxRetryDelay returns how long to wait before relaying an event again, doubling from
+// 10 seconds up to one hour
+func OutboxRetryDelay(attempts int) time.Duration {
+	delay := 10 * time.Second
+	for i := 1; i < attempts && delay < time.Hour; i++ {
+		delay *= 2
+	}
+	if delay > time.Hour {
+		delay = time.Hour
+	}
+	return delay
+}
@@ -12,16 +12,29 @@ const (
 	QuestionTypeLikertScale    QuestionType = "likert_scale"
 	QuestionTypeFreeText       QuestionType = "free_text"
 	QuestionTypeYesNo          QuestionType = "yes_no"
+	QuestionTypeCheckbox       QuestionType = "checkbox" // Multi-select
+	QuestionTypeNumeric        QuestionType = "numeric"
+	QuestionTypeDate           QuestionType = "date"
+	QuestionTypeRating         QuestionType = "rating" // Star rating
+	QuestionTypeRanking        QuestionType = "ranking"
+	QuestionTypeMatrix         QuestionType = "matrix" // Grid of rows answered on shared columns
+	QuestionTypeNPS            QuestionType = "nps"    // Net Promoter Score, fixed 0-10 scale
 )
 
 // Question represents an embedded question within a questionnaire
 type Question struct {
 	QuestionID   string                 `bson:"question_id" json:"question_id"`
 	QuestionText string                 `bson:"question_text" json:"question_text" validate:"required,min=5"`
-	QuestionType QuestionType           `bson:"question_type" json:"question_type" validate:"required,oneof=multiple_choice likert_scale free_text yes_no"`
+	QuestionType QuestionType           `bson:"question_type" json:"question_type" validate:"required"` // Must be registered in questionTypes
 	Options      map[string]interface{} `bson:"options,omitempty" json:"options,omitempty"`
 	OrderIndex   int                    `bson:"order_index" json:"order_index" validate:"min=0"`
 	IsRequired   bool                   `bson:"is_required" json:"is_required"`
+	SectionID    string                 `bson:"section_id,omitempty" json:"section_id,omitempty"` // Empty when the question is not in a section
+	Scoring      *QuestionScoring       `bson:"scoring,omitempty" json:"scoring,omitempty"`       // Nil when the question is not scored
+	Dimensions   []DimensionTag         `bson:"dimensions,omitempty" json:"dimensions,omitempty"` // Subscales the question feeds
+
+	DisplayConditions []QuestionCondition `bson:"display_conditions,omitempty" json:"display_conditions,omitempty"` // Shown only when all hold
+	SkipConditions    []QuestionCondition `bson:"skip_conditions,omitempty" json:"skip_conditions,omitempty"`       // Hidden when any holds
 }
 
 // NewQuestion creates a new Question with a unique ID
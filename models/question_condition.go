@@ -0,0 +1,385 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ConditionOperator represents a comparison used by question conditions
+type ConditionOperator string
+
+const (
+	ConditionOperatorEquals      ConditionOperator = "eq"
+	ConditionOperatorNotEquals   ConditionOperator = "neq"
+	ConditionOperatorLessThan    ConditionOperator = "lt"
+	ConditionOperatorLessOrEqual ConditionOperator = "lte"
+	ConditionOperatorGreaterThan ConditionOperator = "gt"
+	ConditionOperatorGreaterOrEq ConditionOperator = "gte"
+	ConditionOperatorIn          ConditionOperator = "in"
+	ConditionOperatorNotIn       ConditionOperator = "not_in"
+	ConditionOperatorAnswered    ConditionOperator = "answered"
+	ConditionOperatorNotAnswered ConditionOperator = "not_answered"
+	ConditionOperatorContains    ConditionOperator = "contains" // A multi-select answer includes the value
+)
+
+// QuestionCondition compares the answer of another question against a value
+type QuestionCondition struct {
+	QuestionID string            `bson:"question_id" json:"question_id"`
+	Operator   ConditionOperator `bson:"operator" json:"operator"`
+	Value      interface{}       `bson:"value,omitempty" json:"value,omitempty"`
+}
+
+// Validate checks that the operator is known and the value has a suitable shape
+func (c QuestionCondition) Validate() error {
+	if c.QuestionID == "" {
+		return fmt.Errorf("question_id is required")
+	}
+
+	switch c.Operator {
+	case ConditionOperatorEquals, ConditionOperatorNotEquals, ConditionOperatorContains:
+		if c.Value == nil {
+			return fmt.Errorf("value is required for operator %s", c.Operator)
+		}
+	case ConditionOperatorLessThan, ConditionOperatorLessOrEqual, ConditionOperatorGreaterThan, ConditionOperatorGreaterOrEq:
+		if _, ok := ToFloat(c.Value); !ok {
+			return fmt.Errorf("value must be a number for operator %s", c.Operator)
+		}
+	case ConditionOperatorIn, ConditionOperatorNotIn:
+		if arrayLen(c.Value) <= 0 {
+			return fmt.Errorf("value must be a non-empty list for operator %s", c.Operator)
+		}
+	case ConditionOperatorAnswered, ConditionOperatorNotAnswered:
+	default:
+		return fmt.Errorf("unknown operator: %s", c.Operator)
+	}
+
+	return nil
+}
+
+// ValidateAgainst checks that the condition can hold for some answer of the referenced question:
+// compared values must be valid answers, contains needs a choice of a multi-select question and
+// ordering operators need a question answered with numbers
+func (c QuestionCondition) ValidateAgainst(target *Question) error {
+	switch c.Operator {
+	case ConditionOperatorEquals, ConditionOperatorNotEquals:
+		if err := target.ValidateAnswer(c.Value); err != nil {
+			return fmt.Errorf("value is not a valid answer of question %s: %v", target.QuestionID, err)
+		}
+	case ConditionOperatorIn, ConditionOperatorNotIn:
+		for i, item := range toInterfaceSlice(c.Value) {
+			if err := target.ValidateAnswer(item); err != nil {
+				return fmt.Errorf("value[%d] is not a valid answer of question %s: %v", i, target.QuestionID, err)
+			}
+		}
+	case ConditionOperatorContains:
+		if target.QuestionType != QuestionTypeCheckbox && target.QuestionType != QuestionTypeRanking {
+			return fmt.Errorf("operator %s needs a %s or %s question", c.Operator, QuestionTypeCheckbox, QuestionTypeRanking)
+		}
+		choice, ok := c.Value.(string)
+		if !ok || !containsString(target.GetChoices(), choice) {
+			return fmt.Errorf("value must be one of the choices of question %s", target.QuestionID)
+		}
+	case ConditionOperatorLessThan, ConditionOperatorLessOrEqual, ConditionOperatorGreaterThan, ConditionOperatorGreaterOrEq:
+		switch target.QuestionType {
+		case QuestionTypeLikertScale, QuestionTypeNumeric, QuestionTypeRating, QuestionTypeNPS:
+		default:
+			return fmt.Errorf("operator %s needs a question answered with a number", c.Operator)
+		}
+	}
+	return nil
+}
+
+// Evaluate checks the condition against an answer; answered is false when the
+// referenced question has no (visible) answer
+func (c QuestionCondition) Evaluate(value interface{}, answered bool) bool {
+	switch c.Operator {
+	case ConditionOperatorAnswered:
+		return answered
+	case ConditionOperatorNotAnswered:
+		return !answered
+	}
+
+	if !answered {
+		return false
+	}
+
+	switch c.Operator {
+	case ConditionOperatorEquals:
+		return valuesEqual(value, c.Value)
+	case ConditionOperatorNotEquals:
+		return !valuesEqual(value, c.Value)
+	case ConditionOperatorContains:
+		for _, item := range toInterfaceSlice(value) {
+			if valuesEqual(item, c.Value) {
+				return true
+			}
+		}
+		return false
+	case ConditionOperatorIn, ConditionOperatorNotIn:
+		found := false
+		for _, item := range toInterfaceSlice(c.Value) {
+			if valuesEqual(value, item) {
+				found = true
+				break
+			}
+		}
+		return found == (c.Operator == ConditionOperatorIn)
+	}
+
+	a, okA := ToFloat(value)
+	b, okB := ToFloat(c.Value)
+	if !okA || !okB {
+		return false
+	}
+
+	switch c.Operator {
+	case ConditionOperatorLessThan:
+		return a < b
+	case ConditionOperatorLessOrEqual:
+		return a <= b
+	case ConditionOperatorGreaterThan:
+		return a > b
+	case ConditionOperatorGreaterOrEq:
+		return a >= b
+	}
+	return false
+}
+
+// HasConditions checks if the question's visibility depends on other answers
+func (q *Question) HasConditions() bool {
+	return len(q.DisplayConditions) > 0 || len(q.SkipConditions) > 0
+}
+
+// DependsOn checks if any of the question's conditions reference the given question
+func (q *Question) DependsOn(questionID string) bool {
+	for _, c := range q.DisplayConditions {
+		if c.QuestionID == questionID {
+			return true
+		}
+	}
+	for _, c := range q.SkipConditions {
+		if c.QuestionID == questionID {
+			return true
+		}
+	}
+	return false
+}
+
+// VisibleQuestions returns the questions shown to a respondent given their responses,
+// ordered by OrderIndex. A question is shown when all display conditions hold and no
+// skip condition holds. Answers to hidden questions are ignored when evaluating others.
+func (q *Questionnaire) VisibleQuestions(responses []Response) []Question {
+	answers := make(map[string]interface{}, len(responses))
+	for _, r := range responses {
+		answers[r.QuestionID] = r.GetValue()
+	}
+
+	byID := make(map[string]*Question, len(q.Questions))
+	for i := range q.Questions {
+		byID[q.Questions[i].QuestionID] = &q.Questions[i]
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		hidden
+		shown
+	)
+	state := make(map[string]int, len(q.Questions))
+
+	var isVisible func(id string) bool
+	isVisible = func(id string) bool {
+		switch state[id] {
+		case visiting, hidden:
+			// A cycle or a hidden question never shows
+			return false
+		case shown:
+			return true
+		}
+
+		question, ok := byID[id]
+		if !ok {
+			return false
+		}
+		state[id] = visiting
+
+		evaluate := func(c QuestionCondition) bool {
+			value, answered := answers[c.QuestionID]
+			if answered && !isVisible(c.QuestionID) {
+				answered = false
+			}
+			return c.Evaluate(value, answered)
+		}
+
+		visible := true
+		for _, c := range question.DisplayConditions {
+			if !evaluate(c) {
+				visible = false
+				break
+			}
+		}
+		if visible {
+			for _, c := range question.SkipConditions {
+				if evaluate(c) {
+					visible = false
+					break
+				}
+			}
+		}
+
+		if visible {
+			state[id] = shown
+		} else {
+			state[id] = hidden
+		}
+		return visible
+	}
+
+	visible := make([]Question, 0, len(q.Questions))
+	for _, question := range q.Questions {
+		if isVisible(question.QuestionID) {
+			visible = append(visible, question)
+		}
+	}
+
+	sort.SliceStable(visible, func(i, j int) bool {
+		return visible[i].OrderIndex < visible[j].OrderIndex
+	})
+	return visible
+}
+
+// ErrConditionCycle is reported when question conditions depend on each other in a circle
+var ErrConditionCycle = errors.New("conditions create a circular dependency between questions")
+
+// ValidateConditions checks that every condition of the given question is well formed,
+// references another question of the questionnaire with a value that question can be
+// answered with, and does not create a dependency cycle.
+// The questionnaire is expected to already contain the question.
+func (q *Questionnaire) ValidateConditions(question *Question) error {
+	if err := q.ValidateConditionReferences(question); err != nil {
+		return err
+	}
+	if q.HasConditionCycle() {
+		return &OptionsError{Field: "display_conditions", Message: ErrConditionCycle.Error()}
+	}
+	return nil
+}
+
+// ValidateConditionReferences runs the checks of ValidateConditions on one question's conditions,
+// without looking for cycles across the questionnaire
+func (q *Questionnaire) ValidateConditionReferences(question *Question) error {
+	check := func(field string, conditions []QuestionCondition) error {
+		for i, c := range conditions {
+			if err := c.Validate(); err != nil {
+				return &OptionsError{Field: fmt.Sprintf("%s[%d]", field, i), Message: err.Error()}
+			}
+			if c.QuestionID == question.QuestionID {
+				return &OptionsError{Field: fmt.Sprintf("%s[%d].question_id", field, i), Message: "cannot reference the question itself"}
+			}
+			target := q.GetQuestionByID(c.QuestionID)
+			if target == nil {
+				return &OptionsError{Field: fmt.Sprintf("%s[%d].question_id", field, i), Message: "question does not exist in this questionnaire"}
+			}
+			if err := c.ValidateAgainst(target); err != nil {
+				return &OptionsError{Field: fmt.Sprintf("%s[%d].value", field, i), Message: err.Error()}
+			}
+		}
+		return nil
+	}
+
+	if err := check("display_conditions", question.DisplayConditions); err != nil {
+		return err
+	}
+	return check("skip_conditions", question.SkipConditions)
+}
+
+// HasConditionCycle detects circular references between question conditions
+func (q *Questionnaire) HasConditionCycle() bool {
+	deps := make(map[string][]string, len(q.Questions))
+	for _, question := range q.Questions {
+		for _, c := range question.DisplayConditions {
+			deps[question.QuestionID] = append(deps[question.QuestionID], c.QuestionID)
+		}
+		for _, c := range question.SkipConditions {
+			deps[question.QuestionID] = append(deps[question.QuestionID], c.QuestionID)
+		}
+	}
+
+	state := make(map[string]int)
+	var visit func(id string) bool
+	visit = func(id string) bool {
+		switch state[id] {
+		case 1:
+			return true
+		case 2:
+			return false
+		}
+		state[id] = 1
+		for _, dep := range deps[id] {
+			if visit(dep) {
+				return true
+			}
+		}
+		state[id] = 2
+		return false
+	}
+
+	for id := range deps {
+		if visit(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// ToFloat converts JSON/BSON numeric values to float64
+func ToFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+func valuesEqual(a, b interface{}) bool {
+	if fa, ok := ToFloat(a); ok {
+		fb, ok := ToFloat(b)
+		return ok && fa == fb
+	}
+	switch va := a.(type) {
+	case string:
+		vb, ok := b.(string)
+		return ok && va == vb
+	case bool:
+		vb, ok := b.(bool)
+		return ok && va == vb
+	}
+	return false
+}
+
+func toInterfaceSlice(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		return v
+	case primitive.A:
+		return []interface{}(v)
+	case []string:
+		result := make([]interface{}, len(v))
+		for i, s := range v {
+			result[i] = s
+		}
+		return result
+	}
+	return nil
+}
@@ -0,0 +1,512 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OptionsError describes an invalid field inside a question's options or scoring
+type OptionsError struct {
+	Field   string
+	Message string
+}
+
+// Error implements the error interface
+func (e *OptionsError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+func optionsError(field, format string, args ...interface{}) *OptionsError {
+	return &OptionsError{Field: "options." + field, Message: fmt.Sprintf(format, args...)}
+}
+
+// MultipleChoiceOptions is the option schema for multiple choice questions
+type MultipleChoiceOptions struct {
+	Choices []string
+}
+
+// LikertScaleOptions is the option schema for Likert scale questions
+type LikertScaleOptions struct {
+	Min    int
+	Max    int
+	Labels []string
+}
+
+// CheckboxOptions is the option schema for multi-select questions
+type CheckboxOptions struct {
+	Choices       []string
+	MinSelections int
+	MaxSelections int
+}
+
+// NumericOptions is the option schema for numeric questions; nil bounds are open
+type NumericOptions struct {
+	Min  *float64
+	Max  *float64
+	Step *float64
+}
+
+// DateOptions is the option schema for date questions; nil bounds are open
+type DateOptions struct {
+	MinDate *time.Time
+	MaxDate *time.Time
+}
+
+// RatingOptions is the option schema for star rating questions (1 to Max)
+type RatingOptions struct {
+	Max    int
+	Labels []string
+}
+
+// MatrixOptions is the option schema for matrix questions: each row is answered with one column
+type MatrixOptions struct {
+	Rows    []string
+	Columns []string
+}
+
+// Bounds for rating questions and the layout of date options and answers
+const (
+	DefaultRatingMax = 5
+	MinRatingMax     = 3
+	MaxRatingMax     = 10
+	DateLayout       = "2006-01-02"
+)
+
+// NormalizeOptions validates the question options against its type's schema and
+// replaces them with the canonical shape. Returns an *OptionsError on failure.
+func (q *Question) NormalizeOptions() error {
+	def, ok := GetQuestionTypeDefinition(q.QuestionType)
+	if !ok {
+		return &OptionsError{Field: "question_type", Message: fmt.Sprintf("unsupported question type: %s", q.QuestionType)}
+	}
+	return def.NormalizeOptions(q)
+}
+
+func checkAllowedKeys(options map[string]interface{}, allowed ...string) error {
+	for key := range options {
+		found := false
+		for _, a := range allowed {
+			if key == a {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return optionsError(key, "unknown option")
+		}
+	}
+	return nil
+}
+
+func normalizeMultipleChoiceOptions(q *Question) error {
+	if err := checkAllowedKeys(q.Options, "choices"); err != nil {
+		return err
+	}
+
+	choices, err := normalizeStringList(q.Options, "choices", 2)
+	if err != nil {
+		return err
+	}
+
+	q.SetMultipleChoiceOptions(choices)
+	return nil
+}
+
+// normalizeStringList validates a list of unique, non-empty strings with at least minCount items
+func normalizeStringList(options map[string]interface{}, key string, minCount int) ([]string, error) {
+	raw, ok := options[key]
+	if !ok {
+		return nil, optionsError(key, "is required")
+	}
+	items := ToStringSlice(raw)
+	if len(items) != arrayLen(raw) {
+		return nil, optionsError(key, "must be a list of strings")
+	}
+	if len(items) < minCount {
+		return nil, optionsError(key, "must contain at least %d items", minCount)
+	}
+
+	result := make([]string, len(items))
+	seen := make(map[string]bool)
+	for i, item := range items {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			return nil, optionsError(fmt.Sprintf("%s[%d]", key, i), "cannot be empty")
+		}
+		if seen[item] {
+			return nil, optionsError(fmt.Sprintf("%s[%d]", key, i), "duplicate value %q", item)
+		}
+		seen[item] = true
+		result[i] = item
+	}
+	return result, nil
+}
+
+func normalizeLikertScaleOptions(q *Question) error {
+	if err := checkAllowedKeys(q.Options, "min", "max", "labels"); err != nil {
+		return err
+	}
+
+	min, ok := ToInt(q.Options["min"])
+	if !ok {
+		return optionsError("min", "is required and must be an integer")
+	}
+	max, ok := ToInt(q.Options["max"])
+	if !ok {
+		return optionsError("max", "is required and must be an integer")
+	}
+	if min >= max {
+		return optionsError("max", "must be greater than min")
+	}
+
+	count := max - min + 1
+	labels := []string{}
+	rawLabels := q.Options["labels"]
+	if m, ok := ToMap(rawLabels); ok {
+		rawLabels = m
+	}
+	switch raw := rawLabels.(type) {
+	case nil:
+	case map[string]interface{}:
+		// Labels keyed by scale value, e.g. {"1": "Never", ...}
+		labels = make([]string, count)
+		for key, value := range raw {
+			n, err := strconv.Atoi(key)
+			if err != nil || n < min || n > max {
+				return optionsError("labels."+key, "key must be a scale value between %d and %d", min, max)
+			}
+			label, ok := value.(string)
+			if !ok {
+				return optionsError("labels."+key, "must be a string")
+			}
+			labels[n-min] = label
+		}
+		for i, label := range labels {
+			if label == "" {
+				return optionsError("labels", "missing label for value %d", min+i)
+			}
+		}
+	default:
+		labels = ToStringSlice(raw)
+		if len(labels) != arrayLen(raw) {
+			return optionsError("labels", "must be a list of strings or a map of value to label")
+		}
+	}
+
+	if len(labels) > 0 && len(labels) != count {
+		return optionsError("labels", "must have exactly %d labels (one per scale value)", count)
+	}
+
+	q.SetLikertScaleOptions(min, max, labels)
+	return nil
+}
+
+func normalizeEmptyOptions(q *Question) error {
+	if err := checkAllowedKeys(q.Options); err != nil {
+		return err
+	}
+	q.Options = map[string]interface{}{}
+	return nil
+}
+
+func normalizeCheckboxOptions(q *Question) error {
+	if err := checkAllowedKeys(q.Options, "choices", "min_selections", "max_selections"); err != nil {
+		return err
+	}
+
+	choices, err := normalizeStringList(q.Options, "choices", 2)
+	if err != nil {
+		return err
+	}
+
+	minSel, maxSel := 1, len(choices)
+	if raw, ok := q.Options["min_selections"]; ok {
+		if minSel, ok = ToInt(raw); !ok {
+			return optionsError("min_selections", "must be an integer")
+		}
+	}
+	if raw, ok := q.Options["max_selections"]; ok {
+		if maxSel, ok = ToInt(raw); !ok {
+			return optionsError("max_selections", "must be an integer")
+		}
+	}
+	if minSel < 1 {
+		return optionsError("min_selections", "must be at least 1")
+	}
+	if maxSel < minSel || maxSel > len(choices) {
+		return optionsError("max_selections", "must be between min_selections and the number of choices")
+	}
+
+	q.Options = map[string]interface{}{
+		"choices":        choices,
+		"min_selections": minSel,
+		"max_selections": maxSel,
+	}
+	return nil
+}
+
+func normalizeNumericOptions(q *Question) error {
+	if err := checkAllowedKeys(q.Options, "min", "max", "step"); err != nil {
+		return err
+	}
+
+	options := map[string]interface{}{}
+	for _, key := range []string{"min", "max", "step"} {
+		raw, ok := q.Options[key]
+		if !ok || raw == nil {
+			continue
+		}
+		v, ok := ToFloat(raw)
+		if !ok || math.IsNaN(v) || math.IsInf(v, 0) {
+			return optionsError(key, "must be a number")
+		}
+		options[key] = v
+	}
+
+	if min, ok := options["min"].(float64); ok {
+		if max, ok := options["max"].(float64); ok && min >= max {
+			return optionsError("max", "must be greater than min")
+		}
+	}
+	if step, ok := options["step"].(float64); ok && step <= 0 {
+		return optionsError("step", "must be greater than 0")
+	}
+
+	q.Options = options
+	return nil
+}
+
+func normalizeDateOptions(q *Question) error {
+	if err := checkAllowedKeys(q.Options, "min_date", "max_date"); err != nil {
+		return err
+	}
+
+	options := map[string]interface{}{}
+	var bounds [2]*time.Time
+	for i, key := range []string{"min_date", "max_date"} {
+		raw, ok := q.Options[key]
+		if !ok || raw == nil {
+			continue
+		}
+		text, _ := raw.(string)
+		date, err := time.Parse(DateLayout, text)
+		if err != nil {
+			return optionsError(key, "must be a date in YYYY-MM-DD format")
+		}
+		bounds[i] = &date
+		options[key] = date.Format(DateLayout)
+	}
+	if bounds[0] != nil && bounds[1] != nil && bounds[1].Before(*bounds[0]) {
+		return optionsError("max_date", "must not be before min_date")
+	}
+
+	q.Options = options
+	return nil
+}
+
+func normalizeRatingOptions(q *Question) error {
+	if err := checkAllowedKeys(q.Options, "max", "labels"); err != nil {
+		return err
+	}
+
+	max := DefaultRatingMax
+	if raw, ok := q.Options["max"]; ok {
+		if max, ok = ToInt(raw); !ok {
+			return optionsError("max", "must be an integer")
+		}
+	}
+	if max < MinRatingMax || max > MaxRatingMax {
+		return optionsError("max", "must be between %d and %d", MinRatingMax, MaxRatingMax)
+	}
+
+	labels := []string{}
+	if raw, ok := q.Options["labels"]; ok && raw != nil {
+		labels = ToStringSlice(raw)
+		if len(labels) != arrayLen(raw) {
+			return optionsError("labels", "must be a list of strings")
+		}
+		if len(labels) != max {
+			return optionsError("labels", "must have exactly %d labels (one per star)", max)
+		}
+	}
+
+	q.Options = map[string]interface{}{
+		"max":    max,
+		"labels": labels,
+	}
+	return nil
+}
+
+func normalizeRankingOptions(q *Question) error {
+	if err := checkAllowedKeys(q.Options, "choices"); err != nil {
+		return err
+	}
+
+	choices, err := normalizeStringList(q.Options, "choices", 2)
+	if err != nil {
+		return err
+	}
+
+	q.Options = map[string]interface{}{
+		"choices": choices,
+	}
+	return nil
+}
+
+func normalizeMatrixOptions(q *Question) error {
+	if err := checkAllowedKeys(q.Options, "rows", "columns"); err != nil {
+		return err
+	}
+
+	rows, err := normalizeStringList(q.Options, "rows", 1)
+	if err != nil {
+		return err
+	}
+	columns, err := normalizeStringList(q.Options, "columns", 2)
+	if err != nil {
+		return err
+	}
+
+	q.Options = map[string]interface{}{
+		"rows":    rows,
+		"columns": columns,
+	}
+	return nil
+}
+
+// normalizeNPSOptions accepts optional anchor labels; the 0-10 scale itself is fixed
+func normalizeNPSOptions(q *Question) error {
+	if err := checkAllowedKeys(q.Options, "low_label", "high_label"); err != nil {
+		return err
+	}
+
+	options := map[string]interface{}{}
+	for _, key := range []string{"low_label", "high_label"} {
+		raw, ok := q.Options[key]
+		if !ok || raw == nil {
+			continue
+		}
+		label, ok := raw.(string)
+		if !ok {
+			return optionsError(key, "must be a string")
+		}
+		if label = strings.TrimSpace(label); label != "" {
+			options[key] = label
+		}
+	}
+
+	q.Options = options
+	return nil
+}
+
+// GetMultipleChoiceOptions returns the typed options of a multiple choice question
+func (q *Question) GetMultipleChoiceOptions() MultipleChoiceOptions {
+	return MultipleChoiceOptions{Choices: q.GetChoices()}
+}
+
+// GetLikertScaleOptions returns the typed options of a Likert scale question
+func (q *Question) GetLikertScaleOptions() LikertScaleOptions {
+	min, max := q.GetLikertRange()
+	var labels []string
+	if q.Options != nil {
+		labels = ToStringSlice(q.Options["labels"])
+	}
+	return LikertScaleOptions{Min: min, Max: max, Labels: labels}
+}
+
+// GetCheckboxOptions returns the typed options of a multi-select question
+func (q *Question) GetCheckboxOptions() CheckboxOptions {
+	choices := q.GetChoices()
+	opts := CheckboxOptions{Choices: choices, MinSelections: 1, MaxSelections: len(choices)}
+	if v, ok := ToInt(q.option("min_selections")); ok {
+		opts.MinSelections = v
+	}
+	if v, ok := ToInt(q.option("max_selections")); ok {
+		opts.MaxSelections = v
+	}
+	return opts
+}
+
+// GetNumericOptions returns the typed options of a numeric question
+func (q *Question) GetNumericOptions() NumericOptions {
+	var opts NumericOptions
+	for key, target := range map[string]**float64{"min": &opts.Min, "max": &opts.Max, "step": &opts.Step} {
+		if v, ok := ToFloat(q.option(key)); ok {
+			*target = &v
+		}
+	}
+	return opts
+}
+
+// GetDateOptions returns the typed options of a date question
+func (q *Question) GetDateOptions() DateOptions {
+	var opts DateOptions
+	for key, target := range map[string]**time.Time{"min_date": &opts.MinDate, "max_date": &opts.MaxDate} {
+		if text, ok := q.option(key).(string); ok {
+			if date, err := time.Parse(DateLayout, text); err == nil {
+				*target = &date
+			}
+		}
+	}
+	return opts
+}
+
+// GetRatingOptions returns the typed options of a star rating question
+func (q *Question) GetRatingOptions() RatingOptions {
+	opts := RatingOptions{Max: DefaultRatingMax, Labels: ToStringSlice(q.option("labels"))}
+	if v, ok := ToInt(q.option("max")); ok {
+		opts.Max = v
+	}
+	return opts
+}
+
+// GetMatrixOptions returns the typed options of a matrix question
+func (q *Question) GetMatrixOptions() MatrixOptions {
+	return MatrixOptions{
+		Rows:    ToStringSlice(q.option("rows")),
+		Columns: ToStringSlice(q.option("columns")),
+	}
+}
+
+// option returns a raw option value, or nil if the question has no such option
+func (q *Question) option(key string) interface{} {
+	if q.Options == nil {
+		return nil
+	}
+	return q.Options[key]
+}
+
+// ToMap converts JSON/BSON documents to a map
+func ToMap(value interface{}) (map[string]interface{}, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v, true
+	case primitive.M:
+		return map[string]interface{}(v), true
+	case primitive.D:
+		m := make(map[string]interface{}, len(v))
+		for _, e := range v {
+			m[e.Key] = e.Value
+		}
+		return m, true
+	}
+	return nil, false
+}
+
+// arrayLen returns the length of a JSON/BSON array value, or -1 if it is not an array
+func arrayLen(value interface{}) int {
+	switch v := value.(type) {
+	case []string:
+		return len(v)
+	case []interface{}:
+		return len(v)
+	case primitive.A:
+		return len(v)
+	}
+	return -1
+}
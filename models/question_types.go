@@ -0,0 +1,123 @@
+package models
+
+import (
+	"sort"
+)
+
+// QuestionTypeDefinition describes how a question type is authored, answered and reported.
+// Adding a question type means adding a constant in question.go and an entry to questionTypes.
+type QuestionTypeDefinition struct {
+	// NormalizeOptions validates the options and rewrites them in canonical form
+	NormalizeOptions func(q *Question) error
+	// ValidateAnswer checks a non-nil submitted value against the question
+	ValidateAnswer func(q *Question, value interface{}) error
+	// Aggregate summarises the answers given to the question
+	Aggregate func(q *Question, values []interface{}) *AnswerSummary
+	// Score returns the unweighted points of an answer; nil when the type cannot be scored
+	Score func(q *Question, value interface{}) (float64, bool)
+	// PointKeys lists the answers that can be given points in QuestionScoring
+	PointKeys func(q *Question) []string
+	// PointRange returns the lowest and highest unweighted points, used to reverse-score answers
+	PointRange func(q *Question) (float64, float64, bool)
+}
+
+// questionTypes is the registry of supported question types
+var questionTypes = map[QuestionType]QuestionTypeDefinition{
+	QuestionTypeMultipleChoice: {
+		NormalizeOptions: normalizeMultipleChoiceOptions,
+		ValidateAnswer:   validateMultipleChoiceAnswer,
+		Aggregate:        aggregateChoices,
+		Score:            scoreChoice,
+		PointKeys:        (*Question).GetChoices,
+		PointRange:       choicePointRange,
+	},
+	QuestionTypeLikertScale: {
+		NormalizeOptions: normalizeLikertScaleOptions,
+		ValidateAnswer:   validateLikertScaleAnswer,
+		Aggregate:        aggregateScale,
+		Score:            scoreScale,
+		PointKeys:        scalePointKeys,
+		PointRange:       scalePointRange,
+	},
+	QuestionTypeFreeText: {
+		NormalizeOptions: normalizeEmptyOptions,
+		ValidateAnswer:   validateFreeTextAnswer,
+		Aggregate:        aggregateText,
+	},
+	QuestionTypeYesNo: {
+		NormalizeOptions: normalizeEmptyOptions,
+		ValidateAnswer:   validateYesNoAnswer,
+		Aggregate:        aggregateYesNo,
+		Score:            scoreYesNo,
+		PointKeys:        yesNoPointKeys,
+		PointRange:       yesNoPointRange,
+	},
+	QuestionTypeCheckbox: {
+		NormalizeOptions: normalizeCheckboxOptions,
+		ValidateAnswer:   validateCheckboxAnswer,
+		Aggregate:        aggregateCheckbox,
+		Score:            scoreCheckbox,
+		PointKeys:        (*Question).GetChoices,
+		PointRange:       checkboxPointRange,
+	},
+	QuestionTypeNumeric: {
+		NormalizeOptions: normalizeNumericOptions,
+		ValidateAnswer:   validateNumericAnswer,
+		Aggregate:        aggregateNumeric,
+		Score:            scoreNumeric,
+		PointRange:       numericPointRange,
+	},
+	QuestionTypeDate: {
+		NormalizeOptions: normalizeDateOptions,
+		ValidateAnswer:   validateDateAnswer,
+		Aggregate:        aggregateDate,
+	},
+	QuestionTypeRating: {
+		NormalizeOptions: normalizeRatingOptions,
+		ValidateAnswer:   validateRatingAnswer,
+		Aggregate:        aggregateScale,
+		Score:            scoreScale,
+		PointKeys:        scalePointKeys,
+		PointRange:       scalePointRange,
+	},
+	QuestionTypeRanking: {
+		NormalizeOptions: normalizeRankingOptions,
+		ValidateAnswer:   validateRankingAnswer,
+		Aggregate:        aggregateRanking,
+	},
+	QuestionTypeMatrix: {
+		NormalizeOptions: normalizeMatrixOptions,
+		ValidateAnswer:   validateMatrixAnswer,
+		Aggregate:        aggregateMatrix,
+	},
+	QuestionTypeNPS: {
+		NormalizeOptions: normalizeNPSOptions,
+		ValidateAnswer:   validateNPSAnswer,
+		Aggregate:        aggregateNPS,
+		Score:            scoreScale,
+		PointKeys:        scalePointKeys,
+		PointRange:       scalePointRange,
+	},
+}
+
+// GetQuestionTypeDefinition retrieves the definition of a question type
+func GetQuestionTypeDefinition(questionType QuestionType) (QuestionTypeDefinition, bool) {
+	def, ok := questionTypes[questionType]
+	return def, ok
+}
+
+// IsValidQuestionType checks if a question type is registered
+func IsValidQuestionType(questionType QuestionType) bool {
+	_, ok := questionTypes[questionType]
+	return ok
+}
+
+// QuestionTypeNames returns the registered question types in alphabetical order
+func QuestionTypeNames() []string {
+	names := make([]string, 0, len(questionTypes))
+	for t := range questionTypes {
+		names = append(names, string(t))
+	}
+	sort.Strings(names)
+	return names
+}
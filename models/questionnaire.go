@@ -1,35 +1,186 @@
 package models
 
 import (
+	"fmt"
+	"sort"
 	"time"
 
+	"github.com/google/uuid"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// QuestionnaireStatus represents the lifecycle status of a questionnaire
+type QuestionnaireStatus string
+
+const (
+	QuestionnaireStatusDraft     QuestionnaireStatus = "draft"
+	QuestionnaireStatusPublished QuestionnaireStatus = "published"
+	QuestionnaireStatusArchived  QuestionnaireStatus = "archived"
+)
+
+// questionnaireTransitions lists the allowed lifecycle transitions
+var questionnaireTransitions = map[QuestionnaireStatus][]QuestionnaireStatus{
+	QuestionnaireStatusDraft:     {QuestionnaireStatusPublished, QuestionnaireStatusArchived},
+	QuestionnaireStatusPublished: {QuestionnaireStatusDraft, QuestionnaireStatusArchived},
+	QuestionnaireStatusArchived:  {QuestionnaireStatusDraft},
+}
+
+// QuestionnaireStatusChange is an audit entry for a lifecycle transition
+type QuestionnaireStatusChange struct {
+	From      QuestionnaireStatus `bson:"from" json:"from"`
+	To        QuestionnaireStatus `bson:"to" json:"to"`
+	ChangedBy string              `bson:"changed_by" json:"changed_by"` // FusionAuth user ID
+	ChangedAt time.Time           `bson:"changed_at" json:"changed_at"`
+}
+
 // Questionnaire represents a questionnaire with embedded questions
 type Questionnaire struct {
-	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-	Title       string             `bson:"title" json:"title" validate:"required,min=5,max=200"`
-	Description string             `bson:"description" json:"description"`
-	CreatedBy   string             `bson:"created_by" json:"created_by"` // FusionAuth user ID
-	IsActive    bool               `bson:"is_active" json:"is_active"`
-	Questions   []Question         `bson:"questions" json:"questions"`
-	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+	ID                    primitive.ObjectID          `bson:"_id,omitempty" json:"id,omitempty"`
+	Title                 string                      `bson:"title" json:"title" validate:"required,min=5,max=200"`
+	Description           string                      `bson:"description" json:"description"`
+	CreatedBy             string                      `bson:"created_by" json:"created_by"` // FusionAuth user ID
+	IsActive              bool                        `bson:"is_active" json:"is_active"`   // False once archived
+	Status                QuestionnaireStatus         `bson:"status" json:"status"`
+	StatusHistory         []QuestionnaireStatusChange `bson:"status_history,omitempty" json:"status_history,omitempty"`
+	PublishedVersion      int                         `bson:"published_version" json:"published_version"`                                 // Latest published snapshot in questionnaire_versions (0 = never published)
+	HasUnpublishedChanges bool                        `bson:"has_unpublished_changes" json:"has_unpublished_changes"`                     // Draft differs from the latest published version
+	SourceQuestionnaireID *primitive.ObjectID         `bson:"source_questionnaire_id,omitempty" json:"source_questionnaire_id,omitempty"` // Set when cloned from another questionnaire
+	SourceVersion         int                         `bson:"source_version,omitempty" json:"source_version,omitempty"`                   // Published version cloned from (0 = the source's draft)
+	Sections              []Section                   `bson:"sections,omitempty" json:"sections,omitempty"`
+	Questions             []Question                  `bson:"questions" json:"questions"`
+	ScoreBands            []ScoreBand                 `bson:"score_bands,omitempty" json:"score_bands,omitempty"` // Labels for ranges of the total score
+	CreatedAt             time.Time                   `bson:"created_at" json:"created_at"`
+	UpdatedAt             time.Time                   `bson:"updated_at" json:"updated_at"`
 }
 
 // NewQuestionnaire creates a new Questionnaire with timestamps
 func NewQuestionnaire(title, description, createdBy string) *Questionnaire {
 	now := time.Now()
 	return &Questionnaire{
-		ID:          primitive.NewObjectID(),
-		Title:       title,
-		Description: description,
-		CreatedBy:   createdBy,
-		IsActive:    true,
-		Questions:   []Question{},
-		CreatedAt:   now,
-		UpdatedAt:   now,
+		ID:                    primitive.NewObjectID(),
+		Title:                 title,
+		Description:           description,
+		CreatedBy:             createdBy,
+		IsActive:              true,
+		Status:                QuestionnaireStatusDraft,
+		HasUnpublishedChanges: true,
+		Questions:             []Question{},
+		CreatedAt:             now,
+		UpdatedAt:             now,
+	}
+}
+
+// Clone returns a new draft questionnaire with a deep copy of the sections and questions.
+// Questions and sections get fresh IDs, and condition and section references are remapped.
+func (q *Questionnaire) Clone(title, createdBy string, sourceVersion int) *Questionnaire {
+	clone := NewQuestionnaire(title, q.Description, createdBy)
+	sourceID := q.ID
+	clone.SourceQuestionnaireID = &sourceID
+	clone.SourceVersion = sourceVersion
+
+	sectionIDs := make(map[string]string, len(q.Sections))
+	clone.Sections = make([]Section, len(q.Sections))
+	for i, section := range q.Sections {
+		section.SectionID = uuid.New().String()
+		sectionIDs[q.Sections[i].SectionID] = section.SectionID
+		clone.Sections[i] = section
+	}
+
+	questionIDs := make(map[string]string, len(q.Questions))
+	for _, question := range q.Questions {
+		questionIDs[question.QuestionID] = uuid.New().String()
+	}
+
+	remap := func(conditions []QuestionCondition) []QuestionCondition {
+		if conditions == nil {
+			return nil
+		}
+		result := make([]QuestionCondition, len(conditions))
+		for i, c := range conditions {
+			if id, ok := questionIDs[c.QuestionID]; ok {
+				c.QuestionID = id
+			}
+			result[i] = c
+		}
+		return result
+	}
+
+	clone.Questions = make([]Question, len(q.Questions))
+	for i, question := range q.Questions {
+		question.QuestionID = questionIDs[question.QuestionID]
+		if question.SectionID != "" {
+			question.SectionID = sectionIDs[question.SectionID]
+		}
+		options := make(map[string]interface{}, len(question.Options))
+		for k, v := range question.Options {
+			options[k] = v
+		}
+		question.Options = options
+		if question.Scoring != nil {
+			scoring := QuestionScoring{Weight: question.Scoring.Weight, Points: make(map[string]float64, len(question.Scoring.Points))}
+			for k, v := range question.Scoring.Points {
+				scoring.Points[k] = v
+			}
+			question.Scoring = &scoring
+		}
+		question.Dimensions = append([]DimensionTag(nil), question.Dimensions...)
+		question.DisplayConditions = remap(question.DisplayConditions)
+		question.SkipConditions = remap(question.SkipConditions)
+		clone.Questions[i] = question
+	}
+
+	clone.ScoreBands = append([]ScoreBand(nil), q.ScoreBands...)
+
+	return clone
+}
+
+// IsPublished checks if at least one version of the questionnaire has been published
+func (q *Questionnaire) IsPublished() bool {
+	return q.PublishedVersion > 0
+}
+
+// IsAssignable checks if the questionnaire can be assigned to companies: it has a published version
+// and is not archived. A questionnaire being revised is assigned at its last published version.
+func (q *Questionnaire) IsAssignable() bool {
+	return q.IsPublished() && q.CurrentStatus() != QuestionnaireStatusArchived
+}
+
+// CurrentStatus returns the lifecycle status, deriving it for documents created before statuses existed
+func (q *Questionnaire) CurrentStatus() QuestionnaireStatus {
+	if q.Status != "" {
+		return q.Status
+	}
+	if !q.IsActive {
+		return QuestionnaireStatusArchived
+	}
+	if q.IsPublished() {
+		return QuestionnaireStatusPublished
+	}
+	return QuestionnaireStatusDraft
+}
+
+// CanTransitionTo checks if the questionnaire may move to the given status
+func (q *Questionnaire) CanTransitionTo(to QuestionnaireStatus) bool {
+	for _, allowed := range questionnaireTransitions[q.CurrentStatus()] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEditable checks if questions can be changed (only drafts are structurally editable)
+func (q *Questionnaire) IsEditable() bool {
+	return q.CurrentStatus() == QuestionnaireStatusDraft
+}
+
+// NewStatusChange builds the audit entry for moving the questionnaire to a new status
+func (q *Questionnaire) NewStatusChange(to QuestionnaireStatus, changedBy string) QuestionnaireStatusChange {
+	return QuestionnaireStatusChange{
+		From:      q.CurrentStatus(),
+		To:        to,
+		ChangedBy: changedBy,
+		ChangedAt: time.Now(),
 	}
 }
 
@@ -63,6 +214,46 @@ func (q *Questionnaire) RemoveQuestion(questionID string) bool {
 	return false
 }
 
+// SortQuestions orders the questions by OrderIndex
+func (q *Questionnaire) SortQuestions() {
+	SortQuestions(q.Questions)
+}
+
+// ReorderQuestions rewrites OrderIndex to follow the given list, which must contain
+// every question of the questionnaire exactly once. The questions end up sorted.
+func (q *Questionnaire) ReorderQuestions(questionIDs []string) error {
+	if len(questionIDs) != len(q.Questions) {
+		return fmt.Errorf("expected %d question IDs, got %d", len(q.Questions), len(questionIDs))
+	}
+
+	position := make(map[string]int, len(questionIDs))
+	for i, id := range questionIDs {
+		if _, dup := position[id]; dup {
+			return fmt.Errorf("duplicate question ID: %s", id)
+		}
+		position[id] = i
+	}
+
+	for i := range q.Questions {
+		index, ok := position[q.Questions[i].QuestionID]
+		if !ok {
+			return fmt.Errorf("missing question ID: %s", q.Questions[i].QuestionID)
+		}
+		q.Questions[i].OrderIndex = index
+	}
+
+	q.SortQuestions()
+	q.UpdatedAt = time.Now()
+	return nil
+}
+
+// SortQuestions orders a list of questions by OrderIndex, keeping ties in their current order
+func SortQuestions(questions []Question) {
+	sort.SliceStable(questions, func(i, j int) bool {
+		return questions[i].OrderIndex < questions[j].OrderIndex
+	})
+}
+
 // GetQuestionByID retrieves a question by ID
 func (q *Questionnaire) GetQuestionByID(questionID string) *Question {
 	for _, question := range q.Questions {
@@ -75,30 +266,53 @@ func (q *Questionnaire) GetQuestionByID(questionID string) *Question {
 
 // CompanyQuestionnaire represents a questionnaire assigned to a company
 type CompanyQuestionnaire struct {
-	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-	CompanyID       primitive.ObjectID `bson:"company_id" json:"company_id" validate:"required"`
-	QuestionnaireID primitive.ObjectID `bson:"questionnaire_id" json:"questionnaire_id" validate:"required"`
-	AssignedBy      string             `bson:"assigned_by" json:"assigned_by"`   // FusionAuth user ID
-	AssignedAt      time.Time          `bson:"assigned_at" json:"assigned_at"`
-	PeriodStart     time.Time          `bson:"period_start" json:"period_start"`
-	PeriodEnd       time.Time          `bson:"period_end" json:"period_end"`
-	IsActive        bool               `bson:"is_active" json:"is_active"`
+	ID                   primitive.ObjectID  `bson:"_id,omitempty" json:"id,omitempty"`
+	CompanyID            primitive.ObjectID  `bson:"company_id" json:"company_id" validate:"required"`
+	QuestionnaireID      primitive.ObjectID  `bson:"questionnaire_id" json:"questionnaire_id" validate:"required"`
+	QuestionnaireVersion int                 `bson:"questionnaire_version" json:"questionnaire_version"` // Published version this period runs on (0 = assigned before versioning)
+	AssignedBy           string              `bson:"assigned_by" json:"assigned_by"`                     // FusionAuth user ID
+	AssignedAt           time.Time           `bson:"assigned_at" json:"assigned_at"`
+	PeriodStart          time.Time           `bson:"period_start" json:"period_start"`
+	PeriodEnd            time.Time           `bson:"period_end" json:"period_end"`
+	IsActive             bool                `bson:"is_active" json:"is_active"`
+	IsAnonymous          bool                `bson:"is_anonymous" json:"is_anonymous"`                                 // Answers are detached from the respondent on submit
+	MinGroupSize         int                 `bson:"min_group_size,omitempty" json:"min_group_size,omitempty"`         // Overrides the company's minimum cohort size (0 = inherit)
+	CampaignID           *primitive.ObjectID `bson:"campaign_id,omitempty" json:"campaign_id,omitempty"`               // Recurring campaign that generated this period
+	CampaignRun          int                 `bson:"campaign_run,omitempty" json:"campaign_run,omitempty"`             // Number of the period within its campaign, from 1
+	PendingActivation    bool                `bson:"pending_activation,omitempty" json:"pending_activation,omitempty"` // Inactive until PeriodStart, when the scheduler activates it
+	ActivatedAt          *time.Time          `bson:"activated_at,omitempty" json:"activated_at,omitempty"`
+	ClosedAt             *time.Time          `bson:"closed_at,omitempty" json:"closed_at,omitempty"` // Set when the period ended and unfinished assignments expired
 }
 
 // NewCompanyQuestionnaire creates a new company questionnaire assignment
-func NewCompanyQuestionnaire(companyID, questionnaireID primitive.ObjectID, assignedBy string, periodStart, periodEnd time.Time) *CompanyQuestionnaire {
+func NewCompanyQuestionnaire(companyID, questionnaireID primitive.ObjectID, questionnaireVersion int, assignedBy string, periodStart, periodEnd time.Time) *CompanyQuestionnaire {
 	return &CompanyQuestionnaire{
-		ID:              primitive.NewObjectID(),
-		CompanyID:       companyID,
-		QuestionnaireID: questionnaireID,
-		AssignedBy:      assignedBy,
-		AssignedAt:      time.Now(),
-		PeriodStart:     periodStart,
-		PeriodEnd:       periodEnd,
-		IsActive:        true,
+		ID:                   primitive.NewObjectID(),
+		CompanyID:            companyID,
+		QuestionnaireID:      questionnaireID,
+		QuestionnaireVersion: questionnaireVersion,
+		AssignedBy:           assignedBy,
+		AssignedAt:           time.Now(),
+		PeriodStart:          periodStart,
+		PeriodEnd:            periodEnd,
+		IsActive:             true,
+	}
+}
+
+// ScheduleActivation leaves a company questionnaire whose period has not started inactive until
+// PeriodStart, when the scheduler activates it
+func (cq *CompanyQuestionnaire) ScheduleActivation(now time.Time) {
+	if cq.PeriodStart.After(now) {
+		cq.IsActive = false
+		cq.PendingActivation = true
 	}
 }
 
+// IsClosed reports whether the period of a company questionnaire has been closed
+func (cq *CompanyQuestionnaire) IsClosed() bool {
+	return cq.ClosedAt != nil
+}
+
 // IsWithinPeriod checks if the current time is within the assignment period
 func (cq *CompanyQuestionnaire) IsWithinPeriod() bool {
 	now := time.Now()
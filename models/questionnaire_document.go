@@ -0,0 +1,210 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// QuestionnaireDocumentVersion is the current version of the questionnaire import/export format
+const QuestionnaireDocumentVersion = 1
+
+// QuestionnaireDocument is the portable form of a questionnaire, exported and imported as JSON or
+// YAML. Sections and questions are identified by keys that are only meaningful within the document;
+// imports assign fresh IDs. Exports use the existing IDs as keys, so exporting twice gives the same file.
+type QuestionnaireDocument struct {
+	FormatVersion int                 `json:"format_version" yaml:"format_version"` // 0 is read as the current version
+	Title         string              `json:"title" yaml:"title"`
+	Description   string              `json:"description,omitempty" yaml:"description,omitempty"`
+	Sections      []SectionDocument   `json:"sections,omitempty" yaml:"sections,omitempty"`
+	Questions     []QuestionDocument  `json:"questions" yaml:"questions"`
+	ScoreBands    []ScoreBandDocument `json:"score_bands,omitempty" yaml:"score_bands,omitempty"`
+}
+
+// SectionDocument is a section in a QuestionnaireDocument
+type SectionDocument struct {
+	Key         string `json:"key" yaml:"key"`
+	Title       string `json:"title" yaml:"title"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	OrderIndex  *int   `json:"order_index,omitempty" yaml:"order_index,omitempty"` // Defaults to the position in the list
+}
+
+// QuestionDocument is a question in a QuestionnaireDocument
+type QuestionDocument struct {
+	Key               string                 `json:"key,omitempty" yaml:"key,omitempty"` // Needed only when conditions refer to the question
+	QuestionText      string                 `json:"question_text" yaml:"question_text"`
+	QuestionType      string                 `json:"question_type" yaml:"question_type"`
+	Options           map[string]interface{} `json:"options,omitempty" yaml:"options,omitempty"`
+	OrderIndex        *int                   `json:"order_index,omitempty" yaml:"order_index,omitempty"` // Defaults to the position in the list
+	IsRequired        bool                   `json:"is_required,omitempty" yaml:"is_required,omitempty"`
+	Section           string                 `json:"section,omitempty" yaml:"section,omitempty"` // Section key
+	Scoring           *ScoringDocument       `json:"scoring,omitempty" yaml:"scoring,omitempty"`
+	Dimensions        []DimensionDocument    `json:"dimensions,omitempty" yaml:"dimensions,omitempty"`
+	DisplayConditions []ConditionDocument    `json:"display_conditions,omitempty" yaml:"display_conditions,omitempty"`
+	SkipConditions    []ConditionDocument    `json:"skip_conditions,omitempty" yaml:"skip_conditions,omitempty"`
+}
+
+// ScoringDocument is the scoring of a question in a QuestionnaireDocument
+type ScoringDocument struct {
+	Points map[string]float64 `json:"points,omitempty" yaml:"points,omitempty"`
+	Weight float64            `json:"weight,omitempty" yaml:"weight,omitempty"`
+}
+
+// DimensionDocument is a dimension tag of a question in a QuestionnaireDocument
+type DimensionDocument struct {
+	Dimension string `json:"dimension" yaml:"dimension"`
+	Reverse   bool   `json:"reverse,omitempty" yaml:"reverse,omitempty"`
+}
+
+// ConditionDocument is a display or skip condition in a QuestionnaireDocument
+type ConditionDocument struct {
+	Question string            `json:"question" yaml:"question"` // Question key
+	Operator ConditionOperator `json:"operator" yaml:"operator"`
+	Value    interface{}       `json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+// ScoreBandDocument is a score band in a QuestionnaireDocument
+type ScoreBandDocument struct {
+	Label string  `json:"label" yaml:"label"`
+	Min   float64 `json:"min" yaml:"min"`
+	Max   float64 `json:"max" yaml:"max"`
+}
+
+// NewQuestionnaireDocument exports a questionnaire, with questions and sections in order
+func NewQuestionnaireDocument(q *Questionnaire) *QuestionnaireDocument {
+	doc := &QuestionnaireDocument{
+		FormatVersion: QuestionnaireDocumentVersion,
+		Title:         q.Title,
+		Description:   q.Description,
+		Questions:     make([]QuestionDocument, 0, len(q.Questions)),
+	}
+
+	for _, section := range q.SortedSections() {
+		orderIndex := section.OrderIndex
+		doc.Sections = append(doc.Sections, SectionDocument{
+			Key:         section.SectionID,
+			Title:       section.Title,
+			Description: section.Description,
+			OrderIndex:  &orderIndex,
+		})
+	}
+
+	questions := make([]Question, len(q.Questions))
+	copy(questions, q.Questions)
+	SortQuestions(questions)
+	for _, question := range questions {
+		orderIndex := question.OrderIndex
+		qd := QuestionDocument{
+			Key:               question.QuestionID,
+			QuestionText:      question.QuestionText,
+			QuestionType:      string(question.QuestionType),
+			Options:           question.Options,
+			OrderIndex:        &orderIndex,
+			IsRequired:        question.IsRequired,
+			Section:           question.SectionID,
+			DisplayConditions: exportConditions(question.DisplayConditions),
+			SkipConditions:    exportConditions(question.SkipConditions),
+		}
+		if question.Scoring != nil {
+			qd.Scoring = &ScoringDocument{Points: question.Scoring.Points, Weight: question.Scoring.Weight}
+		}
+		for _, tag := range question.Dimensions {
+			qd.Dimensions = append(qd.Dimensions, DimensionDocument{Dimension: tag.Dimension, Reverse: tag.Reverse})
+		}
+		doc.Questions = append(doc.Questions, qd)
+	}
+
+	for _, band := range q.ScoreBands {
+		doc.ScoreBands = append(doc.ScoreBands, ScoreBandDocument{Label: band.Label, Min: band.Min, Max: band.Max})
+	}
+
+	return doc
+}
+
+// ToQuestionnaire builds the draft questionnaire a document describes, with fresh section and
+// question IDs. Section and condition keys that match no section or question are kept as they are
+// so validation can report them. Missing order indexes become the position in the list.
+func (d *QuestionnaireDocument) ToQuestionnaire(createdBy string) *Questionnaire {
+	q := NewQuestionnaire(d.Title, d.Description, createdBy)
+
+	sectionIDs := make(map[string]string, len(d.Sections))
+	for i, sd := range d.Sections {
+		section := Section{
+			SectionID:   uuid.New().String(),
+			Title:       sd.Title,
+			Description: sd.Description,
+			OrderIndex:  i,
+		}
+		if sd.OrderIndex != nil {
+			section.OrderIndex = *sd.OrderIndex
+		}
+		if sd.Key != "" {
+			sectionIDs[sd.Key] = section.SectionID
+		}
+		q.Sections = append(q.Sections, section)
+	}
+
+	questionIDs := make(map[string]string, len(d.Questions))
+	for _, qd := range d.Questions {
+		if qd.Key != "" {
+			questionIDs[qd.Key] = uuid.New().String()
+		}
+	}
+	lookup := func(ids map[string]string, key string) string {
+		if id, ok := ids[key]; ok {
+			return id
+		}
+		return key
+	}
+	conditions := func(docs []ConditionDocument) []QuestionCondition {
+		var result []QuestionCondition
+		for _, cd := range docs {
+			result = append(result, QuestionCondition{QuestionID: lookup(questionIDs, cd.Question), Operator: cd.Operator, Value: cd.Value})
+		}
+		return result
+	}
+
+	for i, qd := range d.Questions {
+		question := Question{
+			QuestionID:        uuid.New().String(),
+			QuestionText:      qd.QuestionText,
+			QuestionType:      QuestionType(qd.QuestionType),
+			Options:           qd.Options,
+			OrderIndex:        i,
+			IsRequired:        qd.IsRequired,
+			DisplayConditions: conditions(qd.DisplayConditions),
+			SkipConditions:    conditions(qd.SkipConditions),
+		}
+		if qd.Key != "" {
+			question.QuestionID = questionIDs[qd.Key]
+		}
+		if qd.OrderIndex != nil {
+			question.OrderIndex = *qd.OrderIndex
+		}
+		if qd.Section != "" {
+			question.SectionID = lookup(sectionIDs, qd.Section)
+		}
+		if question.Options == nil {
+			question.Options = map[string]interface{}{}
+		}
+		if qd.Scoring != nil {
+			question.Scoring = &QuestionScoring{Points: qd.Scoring.Points, Weight: qd.Scoring.Weight}
+		}
+		for _, dd := range qd.Dimensions {
+			question.Dimensions = append(question.Dimensions, DimensionTag{Dimension: dd.Dimension, Reverse: dd.Reverse})
+		}
+		q.Questions = append(q.Questions, question)
+	}
+
+	for _, bd := range d.ScoreBands {
+		q.ScoreBands = append(q.ScoreBands, ScoreBand{Label: bd.Label, Min: bd.Min, Max: bd.Max})
+	}
+
+	return q
+}
+
+func exportConditions(conditions []QuestionCondition) []ConditionDocument {
+	var result []ConditionDocument
+	for _, c := range conditions {
+		result = append(result, ConditionDocument{Question: c.QuestionID, Operator: c.Operator, Value: c.Value})
+	}
+	return result
+}
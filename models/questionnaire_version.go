@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// QuestionnaireVersion is an immutable snapshot of a questionnaire taken when it is published
+type QuestionnaireVersion struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	QuestionnaireID primitive.ObjectID `bson:"questionnaire_id" json:"questionnaire_id"`
+	Version         int                `bson:"version" json:"version"`
+	Title           string             `bson:"title" json:"title"`
+	Description     string             `bson:"description" json:"description"`
+	Sections        []Section          `bson:"sections,omitempty" json:"sections,omitempty"`
+	Questions       []Question         `bson:"questions" json:"questions"`
+	ScoreBands      []ScoreBand        `bson:"score_bands,omitempty" json:"score_bands,omitempty"`
+	PublishedBy     string             `bson:"published_by" json:"published_by"` // FusionAuth user ID
+	PublishedAt     time.Time          `bson:"published_at" json:"published_at"`
+}
+
+// NewQuestionnaireVersion snapshots the current state of a questionnaire
+func NewQuestionnaireVersion(questionnaire *Questionnaire, version int, publishedBy string) *QuestionnaireVersion {
+	questions := make([]Question, len(questionnaire.Questions))
+	copy(questions, questionnaire.Questions)
+	sections := make([]Section, len(questionnaire.Sections))
+	copy(sections, questionnaire.Sections)
+
+	return &QuestionnaireVersion{
+		ID:              primitive.NewObjectID(),
+		QuestionnaireID: questionnaire.ID,
+		Version:         version,
+		Title:           questionnaire.Title,
+		Description:     questionnaire.Description,
+		Sections:        sections,
+		Questions:       questions,
+		ScoreBands:      append([]ScoreBand(nil), questionnaire.ScoreBands...),
+		PublishedBy:     publishedBy,
+		PublishedAt:     time.Now(),
+	}
+}
+
+// SortQuestions orders the snapshot's questions by OrderIndex
+func (v *QuestionnaireVersion) SortQuestions() {
+	SortQuestions(v.Questions)
+}
+
+// ToQuestionnaire returns a read-only view of the questionnaire as it was at this version
+func (v *QuestionnaireVersion) ToQuestionnaire(base *Questionnaire) *Questionnaire {
+	view := *base
+	view.Title = v.Title
+	view.Description = v.Description
+	view.Sections = v.Sections
+	view.Questions = v.Questions
+	view.ScoreBands = v.ScoreBands
+	return &view
+}
@@ -1,6 +1,9 @@
 package models
 
 import (
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -65,3 +68,39 @@ func (r *Response) GetValue() interface{} {
 	}
 	return r.ResponseValue["value"]
 }
+
+// ExportValue flattens an answer value into a spreadsheet cell: numbers stay float64, yes/no
+// answers become "yes"/"no", lists are joined with "; " and matrix answers become "row: column" pairs
+func ExportValue(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	if n, ok := ToFloat(value); ok {
+		return n
+	}
+	switch v := value.(type) {
+	case string:
+		return v
+	case bool:
+		if v {
+			return "yes"
+		}
+		return "no"
+	}
+	if arrayLen(value) >= 0 {
+		return strings.Join(ToStringSlice(value), "; ")
+	}
+	if m, ok := ToMap(value); ok {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		pairs := make([]string, 0, len(keys))
+		for _, k := range keys {
+			pairs = append(pairs, fmt.Sprintf("%s: %v", k, m[k]))
+		}
+		return strings.Join(pairs, "; ")
+	}
+	return fmt.Sprint(value)
+}
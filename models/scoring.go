@@ -0,0 +1,335 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// QuestionScoring assigns points to the answers of a question
+type QuestionScoring struct {
+	// Points per answer, keyed by choice (multiple choice, checkbox), "yes"/"no", or scale value
+	// (Likert, rating, NPS). Unlisted scale values score their own value; numeric answers always do.
+	Points map[string]float64 `bson:"points,omitempty" json:"points,omitempty"`
+	Weight float64            `bson:"weight,omitempty" json:"weight,omitempty"` // Multiplies the points (0 = 1)
+}
+
+// ScoreBand labels a range of total scores, e.g. "low burnout risk"
+type ScoreBand struct {
+	Label string  `bson:"label" json:"label"`
+	Min   float64 `bson:"min" json:"min"` // Inclusive
+	Max   float64 `bson:"max" json:"max"` // Inclusive
+}
+
+// AssignmentScore is the score of a submitted assignment
+type AssignmentScore struct {
+	Total      float64          `bson:"total" json:"total"`
+	Band       string           `bson:"band,omitempty" json:"band,omitempty"`             // Empty when no band contains the total
+	Answered   int              `bson:"answered" json:"answered"`                         // Scored questions that were answered
+	Dimensions []DimensionScore `bson:"dimensions,omitempty" json:"dimensions,omitempty"` // In dimension name order
+}
+
+// IsScored checks if the question contributes to the questionnaire score
+func (q *Question) IsScored() bool {
+	return q.Scoring != nil
+}
+
+// Score returns the weighted points of an answer, or false if the answer scores nothing
+func (q *Question) Score(value interface{}) (float64, bool) {
+	if q.Scoring == nil {
+		return 0, false
+	}
+	return q.points(value, false)
+}
+
+// points returns the weighted points of an answer, mirrored within the question's point range when
+// reverse is set. Questions without scoring use default points, so scale answers score their value.
+func (q *Question) points(value interface{}, reverse bool) (float64, bool) {
+	def, ok := GetQuestionTypeDefinition(q.QuestionType)
+	if !ok || def.Score == nil || value == nil {
+		return 0, false
+	}
+	points, ok := def.Score(q, value)
+	if !ok {
+		return 0, false
+	}
+	if reverse {
+		min, max, ok := def.PointRange(q)
+		if !ok {
+			return 0, false
+		}
+		points = min + max - points
+	}
+	return points * q.weight(), true
+}
+
+// weight returns the question's score multiplier
+func (q *Question) weight() float64 {
+	if q.Scoring == nil || q.Scoring.Weight == 0 {
+		return 1
+	}
+	return q.Scoring.Weight
+}
+
+// pointMap returns the configured points per answer, nil when the question has no scoring
+func (q *Question) pointMap() map[string]float64 {
+	if q.Scoring == nil {
+		return nil
+	}
+	return q.Scoring.Points
+}
+
+// ValidateScoring checks the scoring of a question whose options are already normalized
+func (q *Question) ValidateScoring() error {
+	if q.Scoring == nil {
+		return nil
+	}
+
+	def, ok := GetQuestionTypeDefinition(q.QuestionType)
+	if !ok || def.Score == nil {
+		return fieldError("scoring", "question type %s cannot be scored", q.QuestionType)
+	}
+
+	if q.Scoring.Weight < 0 {
+		return fieldError("scoring.weight", "must not be negative")
+	}
+
+	var allowed []string
+	if def.PointKeys != nil {
+		allowed = def.PointKeys(q)
+	}
+	for key := range q.Scoring.Points {
+		if !containsString(allowed, key) {
+			return fieldError("scoring.points", "%q is not an answer of this question", key)
+		}
+	}
+	return nil
+}
+
+// HasScoring checks if any question of the questionnaire is scored
+func (q *Questionnaire) HasScoring() bool {
+	for i := range q.Questions {
+		if q.Questions[i].IsScored() {
+			return true
+		}
+	}
+	return false
+}
+
+// ComputeScore scores the answers to the scored questions visible under those answers, and each
+// dimension over its tagged questions. It returns nil when the questionnaire has neither.
+func (q *Questionnaire) ComputeScore(responses []Response) *AssignmentScore {
+	hasScoring := q.HasScoring()
+	dimensions := q.DimensionNames()
+	if !hasScoring && len(dimensions) == 0 {
+		return nil
+	}
+
+	values := make(map[string]interface{}, len(responses))
+	for _, r := range responses {
+		values[r.QuestionID] = r.GetValue()
+	}
+
+	score := &AssignmentScore{}
+	byDimension := make(map[string]*DimensionScore, len(dimensions))
+	for _, question := range q.VisibleQuestions(responses) {
+		value := values[question.QuestionID]
+		if points, ok := question.Score(value); ok {
+			score.Total += points
+			score.Answered++
+		}
+
+		for _, tag := range question.Dimensions {
+			points, ok := question.points(value, tag.Reverse)
+			if !ok {
+				continue
+			}
+			if _, exists := byDimension[tag.Dimension]; !exists {
+				byDimension[tag.Dimension] = &DimensionScore{Dimension: tag.Dimension}
+			}
+			byDimension[tag.Dimension].Total += points
+			byDimension[tag.Dimension].Answered++
+		}
+	}
+
+	if hasScoring {
+		score.Band = q.FindScoreBand(score.Total)
+	}
+	for _, name := range dimensions {
+		if d, ok := byDimension[name]; ok {
+			d.Mean = d.Total / float64(d.Answered)
+			score.Dimensions = append(score.Dimensions, *d)
+		}
+	}
+	return score
+}
+
+// FindScoreBand returns the label of the band containing a score, or "" if none does
+func (q *Questionnaire) FindScoreBand(score float64) string {
+	for _, band := range q.ScoreBands {
+		if score >= band.Min && score <= band.Max {
+			return band.Label
+		}
+	}
+	return ""
+}
+
+// ValidateScoreBands checks that bands are labelled, unique and do not overlap.
+// Bands are sorted by Min in place.
+func ValidateScoreBands(bands []ScoreBand) error {
+	labels := make(map[string]bool, len(bands))
+	for i, band := range bands {
+		if band.Label == "" {
+			return fieldError(fmt.Sprintf("score_bands[%d].label", i), "is required")
+		}
+		if labels[band.Label] {
+			return fieldError(fmt.Sprintf("score_bands[%d].label", i), "duplicate label %q", band.Label)
+		}
+		labels[band.Label] = true
+		if band.Min > band.Max {
+			return fieldError(fmt.Sprintf("score_bands[%d].min", i), "must not be greater than max")
+		}
+	}
+
+	sort.SliceStable(bands, func(i, j int) bool {
+		return bands[i].Min < bands[j].Min
+	})
+	for i := 1; i < len(bands); i++ {
+		if bands[i].Min <= bands[i-1].Max {
+			return fieldError("score_bands", "bands %q and %q overlap", bands[i-1].Label, bands[i].Label)
+		}
+	}
+	return nil
+}
+
+// fieldError reports an invalid scoring field; unlike optionsError the field is not under options
+func fieldError(field, format string, args ...interface{}) *OptionsError {
+	return &OptionsError{Field: field, Message: fmt.Sprintf(format, args...)}
+}
+
+func scoreChoice(q *Question, value interface{}) (float64, bool) {
+	choice, ok := value.(string)
+	if !ok {
+		return 0, false
+	}
+	return q.pointMap()[choice], true
+}
+
+func scoreCheckbox(q *Question, value interface{}) (float64, bool) {
+	selected := ToStringSlice(value)
+	if len(selected) == 0 {
+		return 0, false
+	}
+	total := 0.0
+	for _, choice := range selected {
+		total += q.pointMap()[choice]
+	}
+	return total, true
+}
+
+func scoreYesNo(q *Question, value interface{}) (float64, bool) {
+	answer, ok := value.(bool)
+	if !ok {
+		return 0, false
+	}
+	if answer {
+		return q.pointMap()["yes"], true
+	}
+	return q.pointMap()["no"], true
+}
+
+// scoreScale scores Likert, rating and NPS answers by their mapped points or, if unmapped, their value
+func scoreScale(q *Question, value interface{}) (float64, bool) {
+	n, ok := ToInt(value)
+	if !ok {
+		return 0, false
+	}
+	if points, mapped := q.pointMap()[strconv.Itoa(n)]; mapped {
+		return points, true
+	}
+	return float64(n), true
+}
+
+func scoreNumeric(q *Question, value interface{}) (float64, bool) {
+	return ToFloat(value)
+}
+
+func yesNoPointKeys(q *Question) []string {
+	return []string{"yes", "no"}
+}
+
+// scalePointKeys lists the values of the question's integer scale
+func scalePointKeys(q *Question) []string {
+	var min, max int
+	switch q.QuestionType {
+	case QuestionTypeRating:
+		min, max = 1, q.GetRatingOptions().Max
+	case QuestionTypeNPS:
+		min, max = NPSMin, NPSMax
+	default:
+		min, max = q.GetLikertRange()
+	}
+
+	keys := make([]string, 0, max-min+1)
+	for v := min; v <= max; v++ {
+		keys = append(keys, strconv.Itoa(v))
+	}
+	return keys
+}
+
+func choicePointRange(q *Question) (float64, float64, bool) {
+	return keyPointRange(q, q.GetChoices())
+}
+
+func yesNoPointRange(q *Question) (float64, float64, bool) {
+	return keyPointRange(q, yesNoPointKeys(q))
+}
+
+// checkboxPointRange spans from selecting every negative choice to selecting every positive one
+func checkboxPointRange(q *Question) (float64, float64, bool) {
+	choices := q.GetChoices()
+	if len(choices) == 0 {
+		return 0, 0, false
+	}
+	min, max := 0.0, 0.0
+	for _, choice := range choices {
+		if p := q.pointMap()[choice]; p < 0 {
+			min += p
+		} else {
+			max += p
+		}
+	}
+	return min, max, true
+}
+
+func scalePointRange(q *Question) (float64, float64, bool) {
+	return keyPointRange(q, scalePointKeys(q))
+}
+
+// numericPointRange is the answer range, which must be bounded on both sides
+func numericPointRange(q *Question) (float64, float64, bool) {
+	opts := q.GetNumericOptions()
+	if opts.Min == nil || opts.Max == nil {
+		return 0, 0, false
+	}
+	return *opts.Min, *opts.Max, true
+}
+
+// keyPointRange returns the lowest and highest points over the given answers. Unmapped scale
+// values are worth their own value; other unmapped answers are worth nothing.
+func keyPointRange(q *Question, keys []string) (float64, float64, bool) {
+	if len(keys) == 0 {
+		return 0, 0, false
+	}
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, key := range keys {
+		p, mapped := q.pointMap()[key]
+		if !mapped {
+			p, _ = strconv.ParseFloat(key, 64)
+		}
+		min = math.Min(min, p)
+		max = math.Max(max, p)
+	}
+	return min, max, true
+}
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Section groups questions of a questionnaire into a page
+type Section struct {
+	SectionID   string `bson:"section_id" json:"section_id"`
+	Title       string `bson:"title" json:"title" validate:"required,min=3,max=200"`
+	Description string `bson:"description" json:"description"`
+	OrderIndex  int    `bson:"order_index" json:"order_index" validate:"min=0"`
+}
+
+// NewSection creates a new Section with a unique ID
+func NewSection(title, description string, orderIndex int) *Section {
+	return &Section{
+		SectionID:   uuid.New().String(),
+		Title:       title,
+		Description: description,
+		OrderIndex:  orderIndex,
+	}
+}
+
+// SectionProgress is the progress of an assignment within one section.
+// Questions without a section are reported under an empty SectionID.
+type SectionProgress struct {
+	SectionID  string  `json:"section_id"`
+	Title      string  `json:"title"`
+	Answered   int     `json:"answered"`
+	Total      int     `json:"total"`
+	Percentage float64 `json:"percentage"`
+	IsComplete bool    `json:"is_complete"` // All visible required questions answered
+}
+
+// GetSectionByID retrieves a section by ID
+func (q *Questionnaire) GetSectionByID(sectionID string) *Section {
+	for _, section := range q.Sections {
+		if section.SectionID == sectionID {
+			return &section
+		}
+	}
+	return nil
+}
+
+// SortedSections returns the sections ordered by OrderIndex
+func (q *Questionnaire) SortedSections() []Section {
+	sections := make([]Section, len(q.Sections))
+	copy(sections, q.Sections)
+	sort.SliceStable(sections, func(i, j int) bool {
+		return sections[i].OrderIndex < sections[j].OrderIndex
+	})
+	return sections
+}
+
+// AddSection adds a section to the questionnaire
+func (q *Questionnaire) AddSection(section Section) {
+	q.Sections = append(q.Sections, section)
+	q.UpdatedAt = time.Now()
+}
+
+// GetSectionQuestions returns the questions of a section ordered by OrderIndex.
+// An empty sectionID selects the questions that are not in any section.
+func (q *Questionnaire) GetSectionQuestions(sectionID string) []Question {
+	questions := []Question{}
+	for _, question := range q.Questions {
+		if question.SectionID == sectionID {
+			questions = append(questions, question)
+		}
+	}
+	sort.SliceStable(questions, func(i, j int) bool {
+		return questions[i].OrderIndex < questions[j].OrderIndex
+	})
+	return questions
+}
+
+// GetSectionProgress calculates progress per section over the questions visible to the respondent,
+// in section order. Unsectioned questions come last, and only if there are any.
+func (a *UserQuestionnaireAssignment) GetSectionProgress(questionnaire *Questionnaire) []SectionProgress {
+	bySection := make(map[string]*SectionProgress)
+	progress := []*SectionProgress{}
+	for _, section := range questionnaire.SortedSections() {
+		p := &SectionProgress{SectionID: section.SectionID, Title: section.Title, IsComplete: true}
+		bySection[section.SectionID] = p
+		progress = append(progress, p)
+	}
+
+	for _, question := range questionnaire.VisibleQuestions(a.Responses) {
+		p, ok := bySection[question.SectionID]
+		if !ok {
+			// Unsectioned, or pointing at a section that no longer exists
+			p, ok = bySection[""]
+			if !ok {
+				p = &SectionProgress{IsComplete: true}
+				bySection[""] = p
+			}
+		}
+
+		p.Total++
+		if a.GetResponse(question.QuestionID) != nil {
+			p.Answered++
+		} else if question.IsRequired {
+			p.IsComplete = false
+		}
+	}
+	if p, ok := bySection[""]; ok {
+		progress = append(progress, p)
+	}
+
+	result := make([]SectionProgress, len(progress))
+	for i, p := range progress {
+		if p.Total > 0 {
+			p.Percentage = (float64(p.Answered) / float64(p.Total)) * 100
+		}
+		result[i] = *p
+	}
+	return result
+}
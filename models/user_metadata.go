@@ -12,6 +12,7 @@ type UserMetadata struct {
 	CompanyID    primitive.ObjectID `bson:"company_id" json:"company_id" validate:"required"` // Reference to companies
 	SupervisorID string             `bson:"supervisor_id,omitempty" json:"supervisor_id,omitempty"` // FusionAuth ID of supervisor
 	Department   string             `bson:"department,omitempty" json:"department,omitempty"`
+	Email        string             `bson:"email,omitempty" json:"email,omitempty"` // Where notifications are sent
 	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
 	UpdatedAt    time.Time          `bson:"updated_at" json:"updated_at"`
 }
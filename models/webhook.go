@@ -0,0 +1,229 @@
+package models
+
+import (
+	"net/url"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// WebhookEventType is the kind of event a webhook subscription receives
+type WebhookEventType string
+
+const (
+	WebhookEventAssignmentCreated            WebhookEventType = "assignment.created"
+	WebhookEventAssignmentStarted            WebhookEventType = "assignment.started" // First response saved
+	WebhookEventAssignmentCompleted          WebhookEventType = "assignment.completed"
+	WebhookEventCompanyQuestionnaireAssigned WebhookEventType = "company_questionnaire.assigned"
+	WebhookEventCompanyQuestionnaireClosed   WebhookEventType = "company_questionnaire.closed" // Period ended
+)
+
+// WebhookEventTypes lists every event type a subscription can choose
+var WebhookEventTypes = []WebhookEventType{
+	WebhookEventAssignmentCreated,
+	WebhookEventAssignmentStarted,
+	WebhookEventAssignmentCompleted,
+	WebhookEventCompanyQuestionnaireAssigned,
+	WebhookEventCompanyQuestionnaireClosed,
+}
+
+// WebhookDeliveryStatus is the state of the delivery of an event to a subscription
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending" // Waiting for its first or next attempt
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed" // Gave up after MaxWebhookAttempts
+)
+
+const (
+	// MaxWebhookAttempts is how many times delivery of an event to a subscription is tried
+	MaxWebhookAttempts = 8
+	// MinWebhookSecretLength is the minimum length of a subscription secret
+	MinWebhookSecretLength = 16
+	// MaxWebhookSubscriptions is the maximum number of webhook subscriptions per company
+	MaxWebhookSubscriptions = 20
+)
+
+// WebhookSubscription sends a company's events of the chosen types to a URL, signed with its secret
+type WebhookSubscription struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	CompanyID   primitive.ObjectID `bson:"company_id" json:"company_id"`
+	URL         string             `bson:"url" json:"url"`
+	Secret      string             `bson:"secret" json:"-"` // Only returned when the subscription is created
+	EventTypes  []WebhookEventType `bson:"event_types" json:"event_types"`
+	Description string             `bson:"description,omitempty" json:"description,omitempty"`
+	IsActive    bool               `bson:"is_active" json:"is_active"`
+	CreatedBy   string             `bson:"created_by" json:"created_by"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// NewWebhookSubscription creates a new active WebhookSubscription with timestamps
+func NewWebhookSubscription(companyID primitive.ObjectID, endpoint, secret string, eventTypes []WebhookEventType, description, createdBy string) *WebhookSubscription {
+	now := time.Now()
+	return &WebhookSubscription{
+		ID:          primitive.NewObjectID(),
+		CompanyID:   companyID,
+		URL:         endpoint,
+		Secret:      secret,
+		EventTypes:  eventTypes,
+		Description: description,
+		IsActive:    true,
+		CreatedBy:   createdBy,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// Validate checks the URL, secret and event types of a subscription
+func (s *WebhookSubscription) Validate() error {
+	parsed, err := url.Parse(s.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fieldError("url", "must be an absolute http or https URL")
+	}
+	if len(s.Secret) < MinWebhookSecretLength {
+		return fieldError("secret", "must be at least %d characters", MinWebhookSecretLength)
+	}
+	if len(s.EventTypes) == 0 {
+		return fieldError("event_types", "at least one event type is required")
+	}
+	seen := make(map[WebhookEventType]bool, len(s.EventTypes))
+	for _, eventType := range s.EventTypes {
+		if !IsWebhookEventType(eventType) {
+			return fieldError("event_types", "unknown event type %q", eventType)
+		}
+		if seen[eventType] {
+			return fieldError("event_types", "duplicate event type %q", eventType)
+		}
+		seen[eventType] = true
+	}
+	return nil
+}
+
+// IsWebhookEventType reports whether eventType is a known event type
+func IsWebhookEventType(eventType WebhookEventType) bool {
+	for _, known := range WebhookEventTypes {
+		if eventType == known {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookEvent is the JSON body posted to subscriptions. Its ID is the same for every subscription
+// receiving the event, so receivers can drop repeated deliveries.
+type WebhookEvent struct {
+	ID        string           `json:"id"`
+	Type      WebhookEventType `json:"type"`
+	CompanyID string           `json:"company_id"`
+	CreatedAt time.Time        `json:"created_at"`
+	Data      interface{}      `json:"data"`
+}
+
+// WebhookDelivery is the delivery of one event to one subscription, and its log
+type WebhookDelivery struct {
+	ID             primitive.ObjectID    `bson:"_id,omitempty" json:"id,omitempty"`
+	SubscriptionID primitive.ObjectID    `bson:"subscription_id" json:"subscription_id"`
+	CompanyID      primitive.ObjectID    `bson:"company_id" json:"company_id"`
+	EventID        string                `bson:"event_id" json:"event_id"`
+	EventType      WebhookEventType      `bson:"event_type" json:"event_type"`
+	Payload        string                `bson:"payload" json:"payload"` // Exact body that is signed and posted
+	Status         WebhookDeliveryStatus `bson:"status" json:"status"`
+	Attempts       int                   `bson:"attempts" json:"attempts"`
+	NextAttemptAt  time.Time             `bson:"next_attempt_at" json:"next_attempt_at"`
+	LastStatusCode int                   `bson:"last_status_code,omitempty" json:"last_status_code,omitempty"`
+	LastError      string                `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	DeliveredAt    *time.Time            `bson:"delivered_at,omitempty" json:"delivered_at,omitempty"`
+	CreatedAt      time.Time             `bson:"created_at" json:"created_at"`
+	UpdatedAt      time.Time             `bson:"updated_at" json:"updated_at"`
+}
+
+// NewWebhookDelivery creates a pending delivery of an event to a subscription, due right away
+func NewWebhookDelivery(subscription *WebhookSubscription, eventID string, eventType WebhookEventType, payload string) *WebhookDelivery {
+	now := time.Now()
+	return &WebhookDelivery{
+		ID:             primitive.NewObjectID(),
+		SubscriptionID: subscription.ID,
+		CompanyID:      subscription.CompanyID,
+		EventID:        eventID,
+		EventType:      eventType,
+		Payload:        payload,
+		Status:         WebhookDeliveryPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+// WebhookRetryDelay returns how long to wait before the next delivery attempt, doubling from
+// 30 seconds up to six hours
+func WebhookRetryDelay(attempts int) time.Duration {
+	delay := 30 * time.Second
+	for i := 1; i < attempts && delay < 6*time.Hour; i++ {
+		delay *= 2
+	}
+	if delay > 6*time.Hour {
+		delay = 6 * time.Hour
+	}
+	return delay
+}
+
+// AssignmentEventData is the data of assignment events
+type AssignmentEventData struct {
+	AssignmentID           primitive.ObjectID `json:"assignment_id"`
+	CompanyQuestionnaireID primitive.ObjectID `json:"company_questionnaire_id"`
+	QuestionnaireID        primitive.ObjectID `json:"questionnaire_id"`
+	UserID                 string             `json:"user_id"`
+	Status                 AssignmentStatus   `json:"status"`
+	AssignedAt             time.Time          `json:"assigned_at"`
+	StartedAt              *time.Time         `json:"started_at,omitempty"`   // Never sent for anonymous questionnaires
+	CompletedAt            *time.Time         `json:"completed_at,omitempty"` // Never sent for anonymous questionnaires
+	Score                  *AssignmentScore   `json:"score,omitempty"`        // Never sent for anonymous questionnaires
+}
+
+// NewAssignmentEventData describes an assignment of a company questionnaire in an event
+func NewAssignmentEventData(cq *CompanyQuestionnaire, assignment *UserQuestionnaireAssignment) *AssignmentEventData {
+	data := &AssignmentEventData{
+		AssignmentID:           assignment.ID,
+		CompanyQuestionnaireID: cq.ID,
+		QuestionnaireID:        cq.QuestionnaireID,
+		UserID:                 assignment.UserID,
+		Status:                 assignment.Status,
+		AssignedAt:             assignment.AssignedAt,
+	}
+	if !cq.IsAnonymous {
+		data.StartedAt = assignment.StartedAt
+		data.CompletedAt = assignment.CompletedAt
+		data.Score = assignment.Score
+	}
+	return data
+}
+
+// CompanyQuestionnaireEventData is the data of company questionnaire events
+type CompanyQuestionnaireEventData struct {
+	CompanyQuestionnaireID primitive.ObjectID  `json:"company_questionnaire_id"`
+	QuestionnaireID        primitive.ObjectID  `json:"questionnaire_id"`
+	QuestionnaireVersion   int                 `json:"questionnaire_version,omitempty"`
+	CampaignID             *primitive.ObjectID `json:"campaign_id,omitempty"`
+	CampaignRun            int                 `json:"campaign_run,omitempty"`
+	PeriodStart            time.Time           `json:"period_start"`
+	PeriodEnd              time.Time           `json:"period_end"`
+	IsAnonymous            bool                `json:"is_anonymous"`
+	ClosedAt               *time.Time          `json:"closed_at,omitempty"`
+}
+
+// NewCompanyQuestionnaireEventData describes a company questionnaire in an event
+func NewCompanyQuestionnaireEventData(cq *CompanyQuestionnaire) *CompanyQuestionnaireEventData {
+	return &CompanyQuestionnaireEventData{
+		CompanyQuestionnaireID: cq.ID,
+		QuestionnaireID:        cq.QuestionnaireID,
+		QuestionnaireVersion:   cq.QuestionnaireVersion,
+		CampaignID:             cq.CampaignID,
+		CampaignRun:            cq.CampaignRun,
+		PeriodStart:            cq.PeriodStart,
+		PeriodEnd:              cq.PeriodEnd,
+		IsAnonymous:            cq.IsAnonymous,
+		ClosedAt:               cq.ClosedAt,
+	}
+}
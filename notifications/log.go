@@ -0,0 +1,49 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"questionarie-service/models"
+	"sync"
+)
+
+// LogTransport writes each notification as a JSON line, for development and auditing
+type LogTransport struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// NewLogTransport creates a LogTransport appending to path, or writing to stdout if path is empty
+func NewLogTransport(path string) (*LogTransport, error) {
+	if path == "" {
+		return &LogTransport{out: os.Stdout}, nil
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open notification log file: %w", err)
+	}
+	return &LogTransport{out: file}, nil
+}
+
+// Name implements Transport
+func (t *LogTransport) Name() string {
+	return "log"
+}
+
+// Send implements Transport
+func (t *LogTransport) Send(ctx context.Context, notification *models.Notification) error {
+	line, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, err := t.out.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write notification: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"questionarie-service/models"
+	"strconv"
+	"time"
+)
+
+// smtpTimeout bounds a whole SMTP exchange, from dialing the server to closing the connection
+const smtpTimeout = 30 * time.Second
+
+// SMTPConfig holds the settings of the SMTP transport
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string // Leave empty for servers without authentication
+	Password string
+	From     string
+}
+
+// SMTPTransport sends notifications as plain text emails
+type SMTPTransport struct {
+	config SMTPConfig
+}
+
+// NewSMTPTransport creates a new SMTPTransport
+func NewSMTPTransport(config SMTPConfig) (*SMTPTransport, error) {
+	if config.Host == "" {
+		return nil, fmt.Errorf("SMTP_HOST is required for the smtp notification transport")
+	}
+	if config.From == "" {
+		return nil, fmt.Errorf("SMTP_FROM is required for the smtp notification transport")
+	}
+	return &SMTPTransport{config: config}, nil
+}
+
+// Name implements Transport
+func (t *SMTPTransport) Name() string {
+	return "smtp"
+}
+
+// Send implements Transport. Users without an email address cannot be notified. The exchange
+// with the server is abandoned after smtpTimeout or when ctx is done, whichever comes first.
+func (t *SMTPTransport) Send(ctx context.Context, notification *models.Notification) error {
+	if notification.Recipient == "" {
+		return ErrNoRecipient
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, smtpTimeout)
+	defer cancel()
+
+	addr := net.JoinHostPort(t.config.Host, strconv.Itoa(t.config.Port))
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	defer conn.Close()
+
+	// The deadline covers slow servers; closing the connection covers a cancelled ctx
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	if err := t.send(conn, notification); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("failed to send email: %w", ctx.Err())
+		}
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// send delivers a notification over an open connection, as smtp.SendMail does: STARTTLS when the
+// server offers it, then authentication when a username is configured
+func (t *SMTPTransport) send(conn net.Conn, notification *models.Notification) error {
+	client, err := smtp.NewClient(conn, t.config.Host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: t.config.Host}); err != nil {
+			return err
+		}
+	}
+	if t.config.Username != "" {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return fmt.Errorf("server does not support authentication")
+		}
+		if err := client.Auth(smtp.PlainAuth("", t.config.Username, t.config.Password, t.config.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(t.config.From); err != nil {
+		return err
+	}
+	if err := client.Rcpt(notification.Recipient); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(t.message(notification)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// message builds the email of a notification
+func (t *SMTPTransport) message(notification *models.Notification) []byte {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", t.config.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", notification.Recipient)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", notification.Subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "Message-ID: <%s@%s>\r\n", notification.ID.Hex(), t.config.Host)
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	msg.WriteString(notification.Body)
+	return msg.Bytes()
+}
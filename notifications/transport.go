@@ -0,0 +1,52 @@
+// Package notifications delivers rendered notifications through a configurable transport
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"questionarie-service/models"
+	"strconv"
+)
+
+// ErrNoRecipient is returned when the transport has no address to deliver a notification to.
+// Retrying does not help, so the notification fails right away.
+var ErrNoRecipient = errors.New("notification has no recipient")
+
+// Transport delivers a notification to its recipient
+type Transport interface {
+	// Name identifies the transport in the notification outbox
+	Name() string
+	// Send delivers the notification, returning an error if it should be retried
+	Send(ctx context.Context, notification *models.Notification) error
+}
+
+// NewTransportFromEnv creates the transport selected by NOTIFICATION_TRANSPORT: log (default),
+// smtp or webhook
+func NewTransportFromEnv() (Transport, error) {
+	switch kind := os.Getenv("NOTIFICATION_TRANSPORT"); kind {
+	case "", "log":
+		return NewLogTransport(os.Getenv("NOTIFICATION_LOG_FILE"))
+	case "smtp":
+		port := 587
+		if value := os.Getenv("SMTP_PORT"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid SMTP_PORT: %w", err)
+			}
+			port = parsed
+		}
+		return NewSMTPTransport(SMTPConfig{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     port,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("SMTP_FROM"),
+		})
+	case "webhook":
+		return NewWebhookTransport(os.Getenv("NOTIFICATION_WEBHOOK_URL"))
+	default:
+		return nil, fmt.Errorf("invalid NOTIFICATION_TRANSPORT %q: must be one of log, smtp, webhook", kind)
+	}
+}
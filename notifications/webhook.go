@@ -0,0 +1,61 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"questionarie-service/models"
+	"time"
+)
+
+// WebhookTransport posts notifications as JSON to an HTTP endpoint, which delivers them
+type WebhookTransport struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookTransport creates a new WebhookTransport
+func NewWebhookTransport(endpoint string) (*WebhookTransport, error) {
+	parsed, err := url.Parse(endpoint)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("NOTIFICATION_WEBHOOK_URL must be an http or https URL for the webhook notification transport")
+	}
+	return &WebhookTransport{url: endpoint, client: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+// Name implements Transport
+func (t *WebhookTransport) Name() string {
+	return "webhook"
+}
+
+// Send implements Transport. Any 2xx response counts as delivered; the dedup key is sent as
+// Idempotency-Key so the receiver can drop retried deliveries.
+func (t *WebhookTransport) Send(ctx context.Context, notification *models.Notification) error {
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", notification.DedupKey)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package outbox
+
+import (
+	"context"
+	"questionarie-service/models"
+)
+
+// Publisher sends a message to a NATS, Kafka or similar broker. msgID is the same every time an
+// event is published, so it can be set as the NATS JetStream Nats-Msg-Id header or the Kafka record
+// key for the broker to drop repeats.
+type Publisher interface {
+	Publish(ctx context.Context, subject, msgID string, data []byte) error
+}
+
+// BrokerSink publishes each event to a subject named after its type, such as
+// "questionarie.assignment.completed"
+type BrokerSink struct {
+	publisher     Publisher
+	subjectPrefix string
+}
+
+// NewBrokerSink creates a BrokerSink; subjectPrefix defaults to "questionarie"
+func NewBrokerSink(publisher Publisher, subjectPrefix string) *BrokerSink {
+	if subjectPrefix == "" {
+		subjectPrefix = "questionarie"
+	}
+	return &BrokerSink{publisher: publisher, subjectPrefix: subjectPrefix}
+}
+
+// Name implements Sink
+func (s *BrokerSink) Name() string {
+	return "broker"
+}
+
+// Publish implements Sink
+func (s *BrokerSink) Publish(ctx context.Context, event *models.OutboxEvent) error {
+	subject := s.subjectPrefix + "." + string(event.Type)
+	return s.publisher.Publish(ctx, subject, event.ID.Hex(), []byte(event.Payload))
+}
@@ -0,0 +1,75 @@
+// Package outbox forwards domain events relayed from the transactional outbox to a configurable sink
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"questionarie-service/models"
+	"strings"
+)
+
+// Sink receives the domain events relayed from the outbox. An event is published at least once:
+// it is published again when the relay cannot record that it was, so sinks and their consumers
+// drop repeats by the event ID.
+type Sink interface {
+	// Name identifies the sink in logs and errors
+	Name() string
+	// Publish forwards the event, returning an error if it should be retried
+	Publish(ctx context.Context, event *models.OutboxEvent) error
+}
+
+// Sinks publishes each event to several sinks in order. When one fails the event is retried on
+// all of them, relying on their deduplication.
+type Sinks []Sink
+
+// Name implements Sink
+func (s Sinks) Name() string {
+	names := make([]string, len(s))
+	for i, sink := range s {
+		names[i] = sink.Name()
+	}
+	return strings.Join(names, ",")
+}
+
+// Publish implements Sink
+func (s Sinks) Publish(ctx context.Context, event *models.OutboxEvent) error {
+	for _, sink := range s {
+		if err := sink.Publish(ctx, event); err != nil {
+			return fmt.Errorf("%s: %w", sink.Name(), err)
+		}
+	}
+	return nil
+}
+
+// NewSinkFromEnv creates the sinks listed in OUTBOX_SINKS, separated by commas: webhook (the
+// default, fanning events out to the company webhook subscriptions), stdout and broker. broker
+// needs a Publisher for the message broker in use; with a nil publisher it is rejected.
+func NewSinkFromEnv(webhook Sink, publisher Publisher) (Sink, error) {
+	kinds := os.Getenv("OUTBOX_SINKS")
+	if kinds == "" {
+		kinds = "webhook"
+	}
+
+	var sinks Sinks
+	for _, kind := range strings.Split(kinds, ",") {
+		switch kind = strings.TrimSpace(kind); kind {
+		case "webhook":
+			sinks = append(sinks, webhook)
+		case "stdout":
+			sinks = append(sinks, NewWriterSink("stdout", os.Stdout))
+		case "broker":
+			if publisher == nil {
+				return nil, fmt.Errorf("OUTBOX_SINKS includes broker but no broker publisher is configured")
+			}
+			sinks = append(sinks, NewBrokerSink(publisher, os.Getenv("OUTBOX_SUBJECT_PREFIX")))
+		default:
+			return nil, fmt.Errorf("invalid OUTBOX_SINKS entry %q: must be one of webhook, stdout, broker", kind)
+		}
+	}
+
+	if len(sinks) == 1 {
+		return sinks[0], nil
+	}
+	return sinks, nil
+}
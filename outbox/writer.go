@@ -0,0 +1,36 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"questionarie-service/models"
+	"sync"
+)
+
+// WriterSink writes the payload of each event as a JSON line, for development and for log shippers
+type WriterSink struct {
+	name string
+	mu   sync.Mutex
+	out  io.Writer
+}
+
+// NewWriterSink creates a WriterSink named name that writes to out
+func NewWriterSink(name string, out io.Writer) *WriterSink {
+	return &WriterSink{name: name, out: out}
+}
+
+// Name implements Sink
+func (s *WriterSink) Name() string {
+	return s.name
+}
+
+// Publish implements Sink
+func (s *WriterSink) Publish(ctx context.Context, event *models.OutboxEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := io.WriteString(s.out, event.Payload+"\n"); err != nil {
+		return fmt.Errorf("failed to write event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"questionarie-service/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AnonymousResponseRepository handles responses detached from respondents of anonymous questionnaires
+type AnonymousResponseRepository struct {
+	collection *mongo.Collection
+}
+
+// NewAnonymousResponseRepository creates a new AnonymousResponseRepository
+func NewAnonymousResponseRepository(db *mongo.Database) *AnonymousResponseRepository {
+	return &AnonymousResponseRepository{
+		collection: db.Collection("anonymous_responses"),
+	}
+}
+
+// Create stores a new anonymous response set
+func (r *AnonymousResponseRepository) Create(ctx context.Context, set *models.AnonymousResponseSet) error {
+	_, err := r.collection.InsertOne(ctx, set)
+	if err != nil {
+		return fmt.Errorf("failed to store anonymous responses: %w", err)
+	}
+	return nil
+}
+
+// GetByCompanyQuestionnaireID retrieves every anonymous response set of a company questionnaire
+func (r *AnonymousResponseRepository) GetByCompanyQuestionnaireID(ctx context.Context, cqID primitive.ObjectID) ([]*models.AnonymousResponseSet, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"company_questionnaire_id": cqID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get anonymous responses: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var sets []*models.AnonymousResponseSet
+	if err = cursor.All(ctx, &sets); err != nil {
+		return nil, fmt.Errorf("failed to decode anonymous responses: %w", err)
+	}
+
+	return sets, nil
+}
+
+// StreamByCompanyQuestionnaireID calls fn for each anonymous response set of a company questionnaire,
+// decoding one document at a time. Sets are ordered by their random ID, so the order reveals
+// neither the day nor the order in which they were stored. Iteration stops at the first error fn returns.
+func (r *AnonymousResponseRepository) StreamByCompanyQuestionnaireID(ctx context.Context, cqID primitive.ObjectID, fn func(*models.AnonymousResponseSet) error) error {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"company_questionnaire_id": cqID}, opts)
+	if err != nil {
+		return fmt.Errorf("failed to get anonymous responses: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var set models.AnonymousResponseSet
+		if err := cursor.Decode(&set); err != nil {
+			return fmt.Errorf("failed to decode anonymous responses: %w", err)
+		}
+		if err := fn(&set); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed to read anonymous responses: %w", err)
+	}
+	return nil
+}
+
+// CountByCompanyQuestionnaireID counts the anonymous response sets of a company questionnaire
+func (r *AnonymousResponseRepository) CountByCompanyQuestionnaireID(ctx context.Context, cqID primitive.ObjectID) (int64, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"company_questionnaire_id": cqID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count anonymous responses: %w", err)
+	}
+	return count, nil
+}
+
+// CountByDepartment counts the anonymous response sets of a company questionnaire per department
+func (r *AnonymousResponseRepository) CountByDepartment(ctx context.Context, cqID primitive.ObjectID) (map[string]int, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"company_questionnaire_id": cqID}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$department",
+			"count": bson.M{"$sum": 1},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count anonymous responses: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Department string `bson:"_id"`
+		Count      int    `bson:"count"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode anonymous response counts: %w", err)
+	}
+
+	counts := make(map[string]int, len(results))
+	for _, result := range results {
+		counts[result.Department] += result.Count
+	}
+	return counts, nil
+}
+
+// AggregateResponsesByQuestion groups the anonymous response values of a company questionnaire by question
+func (r *AnonymousResponseRepository) AggregateResponsesByQuestion(ctx context.Context, cqID primitive.ObjectID) ([]QuestionResponseValues, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"company_questionnaire_id": cqID}}},
+		{{Key: "$unwind", Value: "$responses"}},
+		{{Key: "$group", Value: bson.M{
+			"_id":    "$responses.question_id",
+			"count":  bson.M{"$sum": 1},
+			"values": bson.M{"$push": "$responses.response_value.value"},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate anonymous responses: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []QuestionResponseValues
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode aggregated responses: %w", err)
+	}
+
+	return results, nil
+}
+
+// Delete deletes an anonymous response set
+func (r *AnonymousResponseRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete anonymous responses: %w", err)
+	}
+	return nil
+}
@@ -85,6 +85,32 @@ func (r *AssignmentRepository) GetByCompanyQuestionnaireID(ctx context.Context,
 	return assignments, nil
 }
 
+// StreamByCompanyQuestionnaireID calls fn for each assignment of a company questionnaire in assignment
+// order, decoding one document at a time. Iteration stops at the first error fn returns.
+func (r *AssignmentRepository) StreamByCompanyQuestionnaireID(ctx context.Context, cqID primitive.ObjectID, fn func(*models.UserQuestionnaireAssignment) error) error {
+	opts := options.Find().SetSort(bson.D{{Key: "assigned_at", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"company_questionnaire_id": cqID}, opts)
+	if err != nil {
+		return fmt.Errorf("failed to get assignments: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var assignment models.UserQuestionnaireAssignment
+		if err := cursor.Decode(&assignment); err != nil {
+			return fmt.Errorf("failed to decode assignment: %w", err)
+		}
+		if err := fn(&assignment); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed to read assignments: %w", err)
+	}
+	return nil
+}
+
 // Update updates an assignment
 func (r *AssignmentRepository) Update(ctx context.Context, id primitive.ObjectID, assignment *models.UserQuestionnaireAssignment) error {
 	update := bson.M{
@@ -134,8 +160,144 @@ func (r *AssignmentRepository) UpdateStatus(ctx context.Context, id primitive.Ob
 	return nil
 }
 
-// AddOrUpdateResponse adds or updates a response in an assignment
-func (r *AssignmentRepository) AddOrUpdateResponse(ctx context.Context, assignmentID primitive.ObjectID, response models.Response) error {
+// Complete marks an assignment as completed with its final responses and its score, if any.
+// It fails if the assignment was completed or expired in the meantime.
+func (r *AssignmentRepository) Complete(ctx context.Context, id primitive.ObjectID, responses []models.Response, score *models.AssignmentScore) error {
+	filter := bson.M{
+		"_id":    id,
+		"status": bson.M{"$nin": []models.AssignmentStatus{models.AssignmentStatusCompleted, models.AssignmentStatusExpired}},
+	}
+	set := bson.M{
+		"status":       models.AssignmentStatusCompleted,
+		"completed_at": time.Now(),
+		"responses":    responses,
+	}
+	if score != nil {
+		set["score"] = score
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": set})
+	if err != nil {
+		return fmt.Errorf("failed to complete assignment: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("conflict: assignment not found, already completed or expired")
+	}
+
+	return nil
+}
+
+// CompleteDetached marks an assignment as completed and clears its responses, which have been
+// moved to the anonymous store. It fails if the assignment was completed or expired in the meantime.
+func (r *AssignmentRepository) CompleteDetached(ctx context.Context, id primitive.ObjectID) error {
+	filter := bson.M{
+		"_id":    id,
+		"status": bson.M{"$nin": []models.AssignmentStatus{models.AssignmentStatusCompleted, models.AssignmentStatusExpired}},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":             models.AssignmentStatusCompleted,
+			"completed_at":       time.Now(),
+			"responses":          []models.Response{},
+			"responses_detached": true,
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to complete assignment: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("conflict: assignment not found, already completed or expired")
+	}
+
+	return nil
+}
+
+// GetByStatusWithoutResponses retrieves the assignments of a company questionnaire in one of the
+// given statuses, without their responses
+func (r *AssignmentRepository) GetByStatusWithoutResponses(ctx context.Context, cqID primitive.ObjectID, statuses ...models.AssignmentStatus) ([]*models.UserQuestionnaireAssignment, error) {
+	filter := bson.M{
+		"company_questionnaire_id": cqID,
+		"status":                   bson.M{"$in": statuses},
+	}
+	opts := options.Find().SetProjection(bson.M{"responses": 0})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assignments: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var assignments []*models.UserQuestionnaireAssignment
+	if err = cursor.All(ctx, &assignments); err != nil {
+		return nil, fmt.Errorf("failed to decode assignments: %w", err)
+	}
+
+	return assignments, nil
+}
+
+// ExpireOpen marks the pending and in progress assignments of a company questionnaire as expired
+func (r *AssignmentRepository) ExpireOpen(ctx context.Context, cqID primitive.ObjectID, now time.Time) (int64, error) {
+	filter := bson.M{
+		"company_questionnaire_id": cqID,
+		"status":                   bson.M{"$in": []models.AssignmentStatus{models.AssignmentStatusPending, models.AssignmentStatusInProgress}},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":     models.AssignmentStatusExpired,
+			"expired_at": now,
+		},
+	}
+
+	result, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire assignments: %w", err)
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// ReopenExpired returns the expired assignments of a company questionnaire to in progress, or to
+// pending when they were never started
+func (r *AssignmentRepository) ReopenExpired(ctx context.Context, cqID primitive.ObjectID) (int64, error) {
+	filter := bson.M{
+		"company_questionnaire_id": cqID,
+		"status":                   models.AssignmentStatusExpired,
+	}
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"status": bson.M{"$cond": bson.A{
+				bson.M{"$gt": bson.A{"$started_at", nil}},
+				models.AssignmentStatusInProgress,
+				models.AssignmentStatusPending,
+			}},
+		}}},
+		{{Key: "$unset", Value: "expired_at"}},
+	}
+
+	result, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reopen assignments: %w", err)
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// CountByCompanyQuestionnaireID counts the assignments of a company questionnaire
+func (r *AssignmentRepository) CountByCompanyQuestionnaireID(ctx context.Context, cqID primitive.ObjectID) (int64, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"company_questionnaire_id": cqID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count assignments: %w", err)
+	}
+	return count, nil
+}
+
+// AddOrUpdateResponse adds or updates a response in an assignment. It reports whether the response
+// started the assignment.
+func (r *AssignmentRepository) AddOrUpdateResponse(ctx context.Context, assignmentID primitive.ObjectID, response models.Response) (bool, error) {
 	// First, try to update existing response
 	filter := bson.M{
 		"_id":                   assignmentID,
@@ -150,7 +312,7 @@ func (r *AssignmentRepository) AddOrUpdateResponse(ctx context.Context, assignme
 
 	result, err := r.collection.UpdateOne(ctx, filter, update)
 	if err != nil {
-		return fmt.Errorf("failed to update response: %w", err)
+		return false, fmt.Errorf("failed to update response: %w", err)
 	}
 
 	// If no existing response found, add new one
@@ -162,18 +324,24 @@ func (r *AssignmentRepository) AddOrUpdateResponse(ctx context.Context, assignme
 
 		result, err = r.collection.UpdateOne(ctx, filter, update)
 		if err != nil {
-			return fmt.Errorf("failed to add response: %w", err)
+			return false, fmt.Errorf("failed to add response: %w", err)
 		}
 
 		if result.MatchedCount == 0 {
-			return fmt.Errorf("assignment not found")
+			return false, fmt.Errorf("assignment not found")
 		}
 
 		// Auto-start assignment if this is first response
-		r.UpdateStatus(ctx, assignmentID, models.AssignmentStatusInProgress)
+		started, err := r.collection.UpdateOne(ctx, bson.M{"_id": assignmentID, "status": models.AssignmentStatusPending}, bson.M{
+			"$set": bson.M{"status": models.AssignmentStatusInProgress, "started_at": time.Now()},
+		})
+		if err != nil {
+			return false, fmt.Errorf("failed to start assignment: %w", err)
+		}
+		return started.ModifiedCount > 0, nil
 	}
 
-	return nil
+	return false, nil
 }
 
 // GetCompletionStats retrieves completion statistics for a company questionnaire
@@ -196,6 +364,7 @@ func (r *AssignmentRepository) GetCompletionStats(ctx context.Context, cqID prim
 	stats["pending"] = 0
 	stats["in_progress"] = 0
 	stats["completed"] = 0
+	stats["expired"] = 0
 
 	for cursor.Next(ctx) {
 		var result struct {
@@ -211,6 +380,45 @@ func (r *AssignmentRepository) GetCompletionStats(ctx context.Context, cqID prim
 	return stats, nil
 }
 
+// QuestionResponseValues holds every answer value given to one question
+type QuestionResponseValues struct {
+	QuestionID string        `bson:"_id"`
+	Count      int64         `bson:"count"`
+	Values     []interface{} `bson:"values"`
+}
+
+// AggregateResponsesByQuestion groups the response values of a company questionnaire by question.
+// Only completed assignments are included unless includeIncomplete is set.
+func (r *AssignmentRepository) AggregateResponsesByQuestion(ctx context.Context, cqID primitive.ObjectID, includeIncomplete bool) ([]QuestionResponseValues, error) {
+	match := bson.M{"company_questionnaire_id": cqID}
+	if !includeIncomplete {
+		match["status"] = models.AssignmentStatusCompleted
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$unwind", Value: "$responses"}},
+		{{Key: "$group", Value: bson.M{
+			"_id":    "$responses.question_id",
+			"count":  bson.M{"$sum": 1},
+			"values": bson.M{"$push": "$responses.response_value.value"},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate responses: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []QuestionResponseValues
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode aggregated responses: %w", err)
+	}
+
+	return results, nil
+}
+
 // GetAverageCompletionTime calculates average time to complete for a company questionnaire
 func (r *AssignmentRepository) GetAverageCompletionTime(ctx context.Context, cqID primitive.ObjectID) (float64, error) {
 	pipeline := mongo.Pipeline{
@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"questionarie-service/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CampaignRepository handles recurring campaign operations
+type CampaignRepository struct {
+	collection *mongo.Collection
+}
+
+// NewCampaignRepository creates a new CampaignRepository
+func NewCampaignRepository(db *mongo.Database) *CampaignRepository {
+	return &CampaignRepository{
+		collection: db.Collection("campaigns"),
+	}
+}
+
+// Create creates a new campaign
+func (r *CampaignRepository) Create(ctx context.Context, campaign *models.Campaign) error {
+	_, err := r.collection.InsertOne(ctx, campaign)
+	if err != nil {
+		return fmt.Errorf("failed to create campaign: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a campaign by ID
+func (r *CampaignRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*models.Campaign, error) {
+	var campaign models.Campaign
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&campaign)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("campaign not found")
+		}
+		return nil, fmt.Errorf("failed to get campaign: %w", err)
+	}
+	return &campaign, nil
+}
+
+// GetByCompanyID retrieves the campaigns of a company, newest first
+func (r *CampaignRepository) GetByCompanyID(ctx context.Context, companyID primitive.ObjectID) ([]*models.Campaign, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"company_id": companyID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaigns: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var campaigns []*models.Campaign
+	if err = cursor.All(ctx, &campaigns); err != nil {
+		return nil, fmt.Errorf("failed to decode campaigns: %w", err)
+	}
+
+	return campaigns, nil
+}
+
+// GetDue retrieves the active campaigns whose next period starts at or before now
+func (r *CampaignRepository) GetDue(ctx context.Context, now time.Time) ([]*models.Campaign, error) {
+	filter := bson.M{
+		"is_active":   true,
+		"next_run_at": bson.M{"$lte": now},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "next_run_at", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due campaigns: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var campaigns []*models.Campaign
+	if err = cursor.All(ctx, &campaigns); err != nil {
+		return nil, fmt.Errorf("failed to decode campaigns: %w", err)
+	}
+
+	return campaigns, nil
+}
+
+// GetPendingAssignment retrieves the active campaigns with a period still to be assigned to their audience
+func (r *CampaignRepository) GetPendingAssignment(ctx context.Context) ([]*models.Campaign, error) {
+	filter := bson.M{
+		"is_active":         true,
+		"pending_period_id": bson.M{"$ne": nil},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaigns pending assignment: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var campaigns []*models.Campaign
+	if err = cursor.All(ctx, &campaigns); err != nil {
+		return nil, fmt.Errorf("failed to decode campaigns: %w", err)
+	}
+
+	return campaigns, nil
+}
+
+// GetActiveByQuestionnaireID retrieves the active campaigns of a questionnaire
+func (r *CampaignRepository) GetActiveByQuestionnaireID(ctx context.Context, questionnaireID primitive.ObjectID) ([]*models.Campaign, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"questionnaire_id": questionnaireID, "is_active": true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaigns: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var campaigns []*models.Campaign
+	if err = cursor.All(ctx, &campaigns); err != nil {
+		return nil, fmt.Errorf("failed to decode campaigns: %w", err)
+	}
+
+	return campaigns, nil
+}
+
+// Update updates the settings of a campaign
+func (r *CampaignRepository) Update(ctx context.Context, id primitive.ObjectID, campaign *models.Campaign) error {
+	campaign.UpdatedAt = time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"name":           campaign.Name,
+			"schedule":       campaign.Schedule,
+			"audience":       campaign.Audience,
+			"is_anonymous":   campaign.IsAnonymous,
+			"min_group_size": campaign.MinGroupSize,
+			"ends_at":        campaign.EndsAt,
+			"next_run_at":    campaign.NextRunAt,
+			"is_active":      campaign.IsActive,
+			"updated_at":     campaign.UpdatedAt,
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update campaign: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("campaign not found")
+	}
+
+	return nil
+}
+
+// ClaimRun moves a campaign from its scheduled run to the next one and counts the run. It only
+// matches while the campaign is still scheduled at expectedNextRunAt, so when several instances
+// run campaigns at once exactly one of them claims each run. It reports whether the claim succeeded.
+func (r *CampaignRepository) ClaimRun(ctx context.Context, id primitive.ObjectID, expectedNextRunAt time.Time, nextRunAt *time.Time, countRun bool) (bool, error) {
+	now := time.Now()
+	set := bson.M{
+		"next_run_at": nextRunAt,
+		"last_error":  "",
+		"updated_at":  now,
+	}
+	update := bson.M{"$set": set}
+	if countRun {
+		set["last_run_at"] = now
+		update["$inc"] = bson.M{"run_count": 1}
+	}
+
+	filter := bson.M{"_id": id, "is_active": true, "next_run_at": expectedNextRunAt}
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim campaign run: %w", err)
+	}
+
+	return result.ModifiedCount > 0, nil
+}
+
+// SetPendingPeriod records the period of a campaign still to be assigned to its audience, or
+// clears it with nil once it is, together with the error of the failed attempts
+func (r *CampaignRepository) SetPendingPeriod(ctx context.Context, id primitive.ObjectID, periodID *primitive.ObjectID) error {
+	set := bson.M{
+		"pending_period_id": periodID,
+		"updated_at":        time.Now(),
+	}
+	if periodID == nil {
+		set["last_error"] = ""
+	}
+	update := bson.M{"$set": set}
+
+	if _, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update); err != nil {
+		return fmt.Errorf("failed to update campaign: %w", err)
+	}
+	return nil
+}
+
+// PauseByQuestionnaireID pauses every active campaign of a questionnaire
+func (r *CampaignRepository) PauseByQuestionnaireID(ctx context.Context, questionnaireID primitive.ObjectID) error {
+	update := bson.M{
+		"$set": bson.M{
+			"is_active":  false,
+			"updated_at": time.Now(),
+		},
+	}
+
+	if _, err := r.collection.UpdateMany(ctx, bson.M{"questionnaire_id": questionnaireID, "is_active": true}, update); err != nil {
+		return fmt.Errorf("failed to pause campaigns: %w", err)
+	}
+	return nil
+}
+
+// SetLastError records why the last run of a campaign failed or was skipped
+func (r *CampaignRepository) SetLastError(ctx context.Context, id primitive.ObjectID, message string) error {
+	update := bson.M{
+		"$set": bson.M{
+			"last_error": message,
+			"updated_at": time.Now(),
+		},
+	}
+
+	if _, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update); err != nil {
+		return fmt.Errorf("failed to update campaign: %w", err)
+	}
+	return nil
+}
@@ -85,6 +85,24 @@ func (r *CompanyQuestionnaireRepository) GetByQuestionnaireID(ctx context.Contex
 	return cqs, nil
 }
 
+// GetByCampaignID retrieves the periods generated by a campaign, oldest first
+func (r *CompanyQuestionnaireRepository) GetByCampaignID(ctx context.Context, campaignID primitive.ObjectID) ([]*models.CompanyQuestionnaire, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "campaign_run", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"campaign_id": campaignID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign periods: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var cqs []*models.CompanyQuestionnaire
+	if err = cursor.All(ctx, &cqs); err != nil {
+		return nil, fmt.Errorf("failed to decode company questionnaires: %w", err)
+	}
+
+	return cqs, nil
+}
+
 // GetActiveByCompanyAndPeriod retrieves active questionnaires for a company within current period
 func (r *CompanyQuestionnaireRepository) GetActiveByCompanyAndPeriod(ctx context.Context, companyID primitive.ObjectID) ([]*models.CompanyQuestionnaire, error) {
 	now := time.Now()
@@ -113,9 +131,13 @@ func (r *CompanyQuestionnaireRepository) GetActiveByCompanyAndPeriod(ctx context
 func (r *CompanyQuestionnaireRepository) Update(ctx context.Context, id primitive.ObjectID, cq *models.CompanyQuestionnaire) error {
 	update := bson.M{
 		"$set": bson.M{
-			"period_start": cq.PeriodStart,
-			"period_end":   cq.PeriodEnd,
-			"is_active":    cq.IsActive,
+			"period_start":       cq.PeriodStart,
+			"period_end":         cq.PeriodEnd,
+			"is_active":          cq.IsActive,
+			"is_anonymous":       cq.IsAnonymous,
+			"min_group_size":     cq.MinGroupSize,
+			"pending_activation": cq.PendingActivation,
+			"closed_at":          cq.ClosedAt,
 		},
 	}
 
@@ -135,7 +157,8 @@ func (r *CompanyQuestionnaireRepository) Update(ctx context.Context, id primitiv
 func (r *CompanyQuestionnaireRepository) Deactivate(ctx context.Context, id primitive.ObjectID) error {
 	update := bson.M{
 		"$set": bson.M{
-			"is_active": false,
+			"is_active":          false,
+			"pending_activation": false,
 		},
 	}
 
@@ -151,6 +174,106 @@ func (r *CompanyQuestionnaireRepository) Deactivate(ctx context.Context, id prim
 	return nil
 }
 
+// ActivateDue activates the company questionnaires waiting for their period to start
+func (r *CompanyQuestionnaireRepository) ActivateDue(ctx context.Context, now time.Time) (int64, error) {
+	filter := bson.M{
+		"pending_activation": true,
+		"period_start":       bson.M{"$lte": now},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"is_active":          true,
+			"pending_activation": false,
+			"activated_at":       now,
+		},
+	}
+
+	result, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, fmt.Errorf("failed to activate company questionnaires: %w", err)
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// GetDueForClosing retrieves the company questionnaires whose period has ended but that are not closed yet
+func (r *CompanyQuestionnaireRepository) GetDueForClosing(ctx context.Context, now time.Time) ([]*models.CompanyQuestionnaire, error) {
+	filter := bson.M{
+		"period_end": bson.M{"$lte": now},
+		"closed_at":  nil,
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get company questionnaires to close: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var cqs []*models.CompanyQuestionnaire
+	if err = cursor.All(ctx, &cqs); err != nil {
+		return nil, fmt.Errorf("failed to decode company questionnaires: %w", err)
+	}
+
+	return cqs, nil
+}
+
+// GetEndingBetween retrieves the active company questionnaires whose period ends after from and
+// no later than to
+func (r *CompanyQuestionnaireRepository) GetEndingBetween(ctx context.Context, from, to time.Time) ([]*models.CompanyQuestionnaire, error) {
+	filter := bson.M{
+		"is_active":  true,
+		"period_end": bson.M{"$gt": from, "$lte": to},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get company questionnaires ending soon: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var cqs []*models.CompanyQuestionnaire
+	if err = cursor.All(ctx, &cqs); err != nil {
+		return nil, fmt.Errorf("failed to decode company questionnaires: %w", err)
+	}
+
+	return cqs, nil
+}
+
+// GetClosedSince retrieves the company questionnaires closed at the end of their period since the given time
+func (r *CompanyQuestionnaireRepository) GetClosedSince(ctx context.Context, since time.Time) ([]*models.CompanyQuestionnaire, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"closed_at": bson.M{"$gte": since}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get closed company questionnaires: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var cqs []*models.CompanyQuestionnaire
+	if err = cursor.All(ctx, &cqs); err != nil {
+		return nil, fmt.Errorf("failed to decode company questionnaires: %w", err)
+	}
+
+	return cqs, nil
+}
+
+// Close deactivates a company questionnaire at the end of its period. It returns false when the
+// company questionnaire was already closed.
+func (r *CompanyQuestionnaireRepository) Close(ctx context.Context, id primitive.ObjectID, now time.Time) (bool, error) {
+	update := bson.M{
+		"$set": bson.M{
+			"is_active":          false,
+			"pending_activation": false,
+			"closed_at":          now,
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id, "closed_at": nil}, update)
+	if err != nil {
+		return false, fmt.Errorf("failed to close company questionnaire: %w", err)
+	}
+
+	return result.ModifiedCount > 0, nil
+}
+
 // Delete deletes a company questionnaire
 func (r *CompanyQuestionnaireRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
 	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
@@ -170,12 +293,11 @@ func (r *CompanyQuestionnaireRepository) CheckDuplicate(ctx context.Context, com
 	filter := bson.M{
 		"company_id":       companyID,
 		"questionnaire_id": questionnaireID,
-		"is_active":        true,
+		"period_start":     bson.M{"$lte": periodEnd},
+		"period_end":       bson.M{"$gte": periodStart},
 		"$or": []bson.M{
-			{
-				"period_start": bson.M{"$lte": periodEnd},
-				"period_end":   bson.M{"$gte": periodStart},
-			},
+			{"is_active": true},
+			{"pending_activation": true},
 		},
 	}
 
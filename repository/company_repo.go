@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"questionarie-service/models"
+	"regexp"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -71,8 +72,10 @@ func (r *CompanyRepository) GetAll(ctx context.Context, page, pageSize int64) ([
 func (r *CompanyRepository) Update(ctx context.Context, id primitive.ObjectID, company *models.Company) error {
 	update := bson.M{
 		"$set": bson.M{
-			"name":       company.Name,
-			"updated_at": company.UpdatedAt,
+			"name":            company.Name,
+			"min_group_size":  company.MinGroupSize,
+			"reminder_policy": company.ReminderPolicy,
+			"updated_at":      company.UpdatedAt,
 		},
 	}
 
@@ -133,3 +136,26 @@ func (r *CompanyRepository) SearchByName(ctx context.Context, name string) ([]*m
 
 	return companies, nil
 }
+
+// FindByName retrieves the companies whose name is exactly name, ignoring case
+func (r *CompanyRepository) FindByName(ctx context.Context, name string) ([]*models.Company, error) {
+	filter := bson.M{
+		"name": bson.M{
+			"$regex":   "^" + regexp.QuoteMeta(name) + "$",
+			"$options": "i",
+		},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find companies by name: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var companies []*models.Company
+	if err = cursor.All(ctx, &companies); err != nil {
+		return nil, fmt.Errorf("failed to decode companies: %w", err)
+	}
+
+	return companies, nil
+}
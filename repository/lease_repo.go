@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"questionarie-service/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LeaseRepository handles the leases used for leader election
+type LeaseRepository struct {
+	collection *mongo.Collection
+}
+
+// NewLeaseRepository creates a new LeaseRepository
+func NewLeaseRepository(db *mongo.Database) *LeaseRepository {
+	return &LeaseRepository{
+		collection: db.Collection("leases"),
+	}
+}
+
+// Acquire takes or renews a lease for holder until now+ttl. It returns false while another
+// holder's lease has not expired.
+func (r *LeaseRepository) Acquire(ctx context.Context, name, holder string, now time.Time, ttl time.Duration) (bool, error) {
+	filter := bson.M{
+		"_id": name,
+		"$or": []bson.M{
+			{"holder": holder},
+			{"expires_at": bson.M{"$lte": now}},
+		},
+	}
+	// acquired_at only moves when the lease changes hands
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"acquired_at": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$holder", holder}}, "$acquired_at", now}},
+			"holder":      holder,
+			"renewed_at":  now,
+			"expires_at":  now.Add(ttl),
+		}}},
+	}
+
+	_, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		// The upsert collides with the _id of a lease someone else holds
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to acquire lease: %w", err)
+	}
+
+	return true, nil
+}
+
+// Release gives up a lease held by holder so another instance can take it right away
+func (r *LeaseRepository) Release(ctx context.Context, name, holder string) error {
+	update := bson.M{
+		"$set": bson.M{
+			"expires_at": time.Now(),
+		},
+	}
+
+	if _, err := r.collection.UpdateOne(ctx, bson.M{"_id": name, "holder": holder}, update); err != nil {
+		return fmt.Errorf("failed to release lease: %w", err)
+	}
+
+	return nil
+}
+
+// GetByName retrieves a lease by name
+func (r *LeaseRepository) GetByName(ctx context.Context, name string) (*models.Lease, error) {
+	var lease models.Lease
+	err := r.collection.FindOne(ctx, bson.M{"_id": name}).Decode(&lease)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("lease not found")
+		}
+		return nil, fmt.Errorf("failed to get lease: %w", err)
+	}
+	return &lease, nil
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"questionarie-service/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// NotificationRepository handles the notification outbox
+type NotificationRepository struct {
+	collection *mongo.Collection
+}
+
+// NewNotificationRepository creates a new NotificationRepository
+func NewNotificationRepository(db *mongo.Database) *NotificationRepository {
+	return &NotificationRepository{
+		collection: db.Collection("notifications"),
+	}
+}
+
+// Enqueue adds a notification to the outbox. It reports false, without error, when a notification
+// with the same dedup key was already queued.
+func (r *NotificationRepository) Enqueue(ctx context.Context, notification *models.Notification) (bool, error) {
+	_, err := r.collection.InsertOne(ctx, notification)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to enqueue notification: %w", err)
+	}
+	return true, nil
+}
+
+// GetExistingDedupKeys returns which of the given dedup keys are already in the outbox
+func (r *NotificationRepository) GetExistingDedupKeys(ctx context.Context, keys []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	if len(keys) == 0 {
+		return existing, nil
+	}
+
+	values, err := r.collection.Distinct(ctx, "dedup_key", bson.M{"dedup_key": bson.M{"$in": keys}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification dedup keys: %w", err)
+	}
+	for _, value := range values {
+		if key, ok := value.(string); ok {
+			existing[key] = true
+		}
+	}
+	return existing, nil
+}
+
+// GetByID retrieves a notification by ID
+func (r *NotificationRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*models.Notification, error) {
+	var notification models.Notification
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&notification)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("notification not found")
+		}
+		return nil, fmt.Errorf("failed to get notification: %w", err)
+	}
+	return &notification, nil
+}
+
+// GetByCompanyID retrieves the latest notifications of a company, optionally filtered by status and user
+func (r *NotificationRepository) GetByCompanyID(ctx context.Context, companyID primitive.ObjectID, status *models.NotificationStatus, userID string, limit int64) ([]*models.Notification, error) {
+	filter := bson.M{"company_id": companyID}
+	if status != nil {
+		filter["status"] = *status
+	}
+	if userID != "" {
+		filter["user_id"] = userID
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(limit)
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notifications: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var notifications []*models.Notification
+	if err = cursor.All(ctx, &notifications); err != nil {
+		return nil, fmt.Errorf("failed to decode notifications: %w", err)
+	}
+
+	return notifications, nil
+}
+
+// ClaimNext takes the pending notification that has waited longest for delivery and counts the
+// attempt. The notification is not due again until lockUntil, so an instance that dies while
+// sending it leaves it to be retried. It returns nil when no notification is due.
+func (r *NotificationRepository) ClaimNext(ctx context.Context, now, lockUntil time.Time) (*models.Notification, error) {
+	filter := bson.M{
+		"status":          models.NotificationStatusPending,
+		"next_attempt_at": bson.M{"$lte": now},
+	}
+	update := bson.M{
+		"$set": bson.M{"next_attempt_at": lockUntil, "updated_at": now},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var notification models.Notification
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&notification)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to claim notification: %w", err)
+	}
+	return &notification, nil
+}
+
+// MarkSent records the delivery of a notification
+func (r *NotificationRepository) MarkSent(ctx context.Context, id primitive.ObjectID, transport string, sentAt time.Time) error {
+	return r.setStatus(ctx, id, bson.M{
+		"status":     models.NotificationStatusSent,
+		"transport":  transport,
+		"sent_at":    sentAt,
+		"last_error": "",
+	})
+}
+
+// MarkRetry records a failed delivery attempt and when to try again
+func (r *NotificationRepository) MarkRetry(ctx context.Context, id primitive.ObjectID, lastError string, nextAttemptAt time.Time) error {
+	return r.setStatus(ctx, id, bson.M{
+		"status":          models.NotificationStatusPending,
+		"last_error":      lastError,
+		"next_attempt_at": nextAttemptAt,
+	})
+}
+
+// MarkFailed gives up on delivering a notification
+func (r *NotificationRepository) MarkFailed(ctx context.Context, id primitive.ObjectID, lastError string) error {
+	return r.setStatus(ctx, id, bson.M{
+		"status":     models.NotificationStatusFailed,
+		"last_error": lastError,
+	})
+}
+
+// Retry queues a failed notification for delivery again, with a fresh set of attempts
+func (r *NotificationRepository) Retry(ctx context.Context, id primitive.ObjectID, now time.Time) error {
+	filter := bson.M{"_id": id, "status": models.NotificationStatusFailed}
+	update := bson.M{
+		"$set": bson.M{
+			"status":          models.NotificationStatusPending,
+			"attempts":        0,
+			"next_attempt_at": now,
+			"updated_at":      now,
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to retry notification: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("conflict: only failed notifications can be retried")
+	}
+	return nil
+}
+
+// setStatus updates the delivery fields of a notification
+func (r *NotificationRepository) setStatus(ctx context.Context, id primitive.ObjectID, set bson.M) error {
+	set["updated_at"] = time.Now()
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": set})
+	if err != nil {
+		return fmt.Errorf("failed to update notification: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("notification not found")
+	}
+	return nil
+}
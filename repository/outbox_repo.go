@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"questionarie-service/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// OutboxRepository handles the outbox of domain events
+type OutboxRepository struct {
+	collection *mongo.Collection
+}
+
+// NewOutboxRepository creates a new OutboxRepository
+func NewOutboxRepository(db *mongo.Database) *OutboxRepository {
+	return &OutboxRepository{
+		collection: db.Collection("outbox_events"),
+	}
+}
+
+// Create stores a domain event. Called with the context of a transaction, the event is only
+// stored if the transaction commits.
+func (r *OutboxRepository) Create(ctx context.Context, event *models.OutboxEvent) error {
+	_, err := r.collection.InsertOne(ctx, event)
+	if err != nil {
+		return fmt.Errorf("failed to store outbox event: %w", err)
+	}
+	return nil
+}
+
+// ClaimNext takes the pending event that has waited longest and counts the attempt. The event is
+// not due again until lockUntil, so an instance that dies while relaying it leaves it to be
+// retried. It returns nil when no event is due.
+func (r *OutboxRepository) ClaimNext(ctx context.Context, now, lockUntil time.Time) (*models.OutboxEvent, error) {
+	filter := bson.M{
+		"status":          models.OutboxPending,
+		"next_attempt_at": bson.M{"$lte": now},
+	}
+	update := bson.M{
+		"$set": bson.M{"next_attempt_at": lockUntil, "updated_at": now},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var event models.OutboxEvent
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&event)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to claim outbox event: %w", err)
+	}
+	return &event, nil
+}
+
+// MarkPublished records that an event reached the sink
+func (r *OutboxRepository) MarkPublished(ctx context.Context, id primitive.ObjectID, publishedAt time.Time) error {
+	return r.setStatus(ctx, id, bson.M{
+		"status":       models.OutboxPublished,
+		"last_error":   "",
+		"published_at": publishedAt,
+	})
+}
+
+// MarkRetry records a failed attempt and when to try again
+func (r *OutboxRepository) MarkRetry(ctx context.Context, id primitive.ObjectID, lastError string, nextAttemptAt time.Time) error {
+	return r.setStatus(ctx, id, bson.M{
+		"last_error":      lastError,
+		"next_attempt_at": nextAttemptAt,
+	})
+}
+
+// setStatus updates the relay fields of an outbox event
+func (r *OutboxRepository) setStatus(ctx context.Context, id primitive.ObjectID, set bson.M) error {
+	set["updated_at"] = time.Now()
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": set})
+	if err != nil {
+		return fmt.Errorf("failed to update outbox event: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("outbox event not found")
+	}
+	return nil
+}
@@ -43,6 +43,7 @@ func (r *QuestionnaireRepository) GetByID(ctx context.Context, id primitive.Obje
 		}
 		return nil, fmt.Errorf("failed to get questionnaire: %w", err)
 	}
+	questionnaire.SortQuestions()
 	return &questionnaire, nil
 }
 
@@ -69,6 +70,9 @@ func (r *QuestionnaireRepository) GetAll(ctx context.Context, page, pageSize int
 	if err = cursor.All(ctx, &questionnaires); err != nil {
 		return nil, fmt.Errorf("failed to decode questionnaires: %w", err)
 	}
+	for _, questionnaire := range questionnaires {
+		questionnaire.SortQuestions()
+	}
 
 	return questionnaires, nil
 }
@@ -85,6 +89,30 @@ func (r *QuestionnaireRepository) GetByCreator(ctx context.Context, creatorID st
 	if err = cursor.All(ctx, &questionnaires); err != nil {
 		return nil, fmt.Errorf("failed to decode questionnaires: %w", err)
 	}
+	for _, questionnaire := range questionnaires {
+		questionnaire.SortQuestions()
+	}
+
+	return questionnaires, nil
+}
+
+// GetBySourceID retrieves questionnaires cloned from the given questionnaire, newest first
+func (r *QuestionnaireRepository) GetBySourceID(ctx context.Context, sourceID primitive.ObjectID) ([]*models.Questionnaire, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"source_questionnaire_id": sourceID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get questionnaire clones: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var questionnaires []*models.Questionnaire
+	if err = cursor.All(ctx, &questionnaires); err != nil {
+		return nil, fmt.Errorf("failed to decode questionnaires: %w", err)
+	}
+	for _, questionnaire := range questionnaires {
+		questionnaire.SortQuestions()
+	}
 
 	return questionnaires, nil
 }
@@ -94,11 +122,12 @@ func (r *QuestionnaireRepository) Update(ctx context.Context, id primitive.Objec
 	questionnaire.UpdatedAt = time.Now()
 	update := bson.M{
 		"$set": bson.M{
-			"title":       questionnaire.Title,
-			"description": questionnaire.Description,
-			"is_active":   questionnaire.IsActive,
-			"questions":   questionnaire.Questions,
-			"updated_at":  questionnaire.UpdatedAt,
+			"title":                   questionnaire.Title,
+			"description":             questionnaire.Description,
+			"is_active":               questionnaire.IsActive,
+			"questions":               questionnaire.Questions,
+			"updated_at":              questionnaire.UpdatedAt,
+			"has_unpublished_changes": true,
 		},
 	}
 
@@ -138,8 +167,11 @@ func (r *QuestionnaireRepository) Deactivate(ctx context.Context, id primitive.O
 // AddQuestion adds a question to a questionnaire
 func (r *QuestionnaireRepository) AddQuestion(ctx context.Context, id primitive.ObjectID, question models.Question) error {
 	update := bson.M{
-		"$push": bson.M{"questions": question},
-		"$set":  bson.M{"updated_at": time.Now()},
+		"$push": bson.M{"questions": bson.M{
+			"$each": []models.Question{question},
+			"$sort": bson.M{"order_index": 1},
+		}},
+		"$set": bson.M{"updated_at": time.Now(), "has_unpublished_changes": true},
 	}
 
 	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
@@ -163,8 +195,9 @@ func (r *QuestionnaireRepository) UpdateQuestion(ctx context.Context, questionna
 
 	update := bson.M{
 		"$set": bson.M{
-			"questions.$":  question,
-			"updated_at": time.Now(),
+			"questions.$":             question,
+			"updated_at":              time.Now(),
+			"has_unpublished_changes": true,
 		},
 	}
 
@@ -185,7 +218,7 @@ func (r *QuestionnaireRepository) RemoveQuestion(ctx context.Context, questionna
 	filter := bson.M{"_id": questionnaireID}
 	update := bson.M{
 		"$pull": bson.M{"questions": bson.M{"question_id": questionID}},
-		"$set":  bson.M{"updated_at": time.Now()},
+		"$set":  bson.M{"updated_at": time.Now(), "has_unpublished_changes": true},
 	}
 
 	result, err := r.collection.UpdateOne(ctx, filter, update)
@@ -200,6 +233,257 @@ func (r *QuestionnaireRepository) RemoveQuestion(ctx context.Context, questionna
 	return nil
 }
 
+// ReorderQuestions replaces the questions array with the given reordered questions in a single update.
+// It only matches if the questionnaire was not modified since expectedUpdatedAt and still holds
+// exactly the same set of questions, so a concurrent edit cannot be lost.
+func (r *QuestionnaireRepository) ReorderQuestions(ctx context.Context, id primitive.ObjectID, questions []models.Question, expectedUpdatedAt time.Time) error {
+	questionIDs := make([]string, len(questions))
+	for i, q := range questions {
+		questionIDs[i] = q.QuestionID
+	}
+
+	filter := bson.M{
+		"_id":        id,
+		"updated_at": expectedUpdatedAt,
+		"questions":  bson.M{"$size": len(questions)},
+	}
+	if len(questionIDs) > 0 {
+		filter["questions.question_id"] = bson.M{"$all": questionIDs}
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"questions":               questions,
+			"updated_at":              time.Now(),
+			"has_unpublished_changes": true,
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to reorder questions: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("conflict: questionnaire not found or modified concurrently")
+	}
+
+	return nil
+}
+
+// UpdateScoreBands replaces the score bands of a questionnaire
+func (r *QuestionnaireRepository) UpdateScoreBands(ctx context.Context, id primitive.ObjectID, bands []models.ScoreBand) error {
+	update := bson.M{
+		"$set": bson.M{
+			"score_bands":             bands,
+			"updated_at":              time.Now(),
+			"has_unpublished_changes": true,
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update score bands: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("questionnaire not found")
+	}
+
+	return nil
+}
+
+// ReplaceContent replaces the title, description, sections, questions and score bands of a
+// questionnaire in a single update. It only matches if the questionnaire was not modified since
+// expectedUpdatedAt, so a concurrent edit cannot be lost.
+func (r *QuestionnaireRepository) ReplaceContent(ctx context.Context, id primitive.ObjectID, questionnaire *models.Questionnaire, expectedUpdatedAt time.Time) error {
+	questionnaire.UpdatedAt = time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"title":                   questionnaire.Title,
+			"description":             questionnaire.Description,
+			"sections":                questionnaire.Sections,
+			"questions":               questionnaire.Questions,
+			"score_bands":             questionnaire.ScoreBands,
+			"updated_at":              questionnaire.UpdatedAt,
+			"has_unpublished_changes": true,
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id, "updated_at": expectedUpdatedAt}, update)
+	if err != nil {
+		return fmt.Errorf("failed to replace questionnaire: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("conflict: questionnaire not found or modified concurrently")
+	}
+
+	return nil
+}
+
+// AddSection adds a section to a questionnaire
+func (r *QuestionnaireRepository) AddSection(ctx context.Context, id primitive.ObjectID, section models.Section) error {
+	update := bson.M{
+		"$push": bson.M{"sections": section},
+		"$set":  bson.M{"updated_at": time.Now(), "has_unpublished_changes": true},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return fmt.Errorf("failed to add section: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("questionnaire not found")
+	}
+
+	return nil
+}
+
+// UpdateSection updates a specific section within a questionnaire
+func (r *QuestionnaireRepository) UpdateSection(ctx context.Context, questionnaireID primitive.ObjectID, sectionID string, section models.Section) error {
+	filter := bson.M{
+		"_id":                 questionnaireID,
+		"sections.section_id": sectionID,
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"sections.$":              section,
+			"updated_at":              time.Now(),
+			"has_unpublished_changes": true,
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to update section: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("questionnaire or section not found")
+	}
+
+	return nil
+}
+
+// RemoveSection removes a section from a questionnaire. The update only matches
+// while no question is still placed in the section.
+func (r *QuestionnaireRepository) RemoveSection(ctx context.Context, questionnaireID primitive.ObjectID, sectionID string) error {
+	filter := bson.M{
+		"_id":                  questionnaireID,
+		"sections.section_id":  sectionID,
+		"questions.section_id": bson.M{"$ne": sectionID},
+	}
+	update := bson.M{
+		"$pull": bson.M{"sections": bson.M{"section_id": sectionID}},
+		"$set":  bson.M{"updated_at": time.Now(), "has_unpublished_changes": true},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to remove section: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("conflict: section not found or still contains questions")
+	}
+
+	return nil
+}
+
+// MarkPublished records a newly published version and moves the questionnaire to
+// published. It only succeeds if no other version was published concurrently and the
+// questionnaire was not modified since expectedUpdatedAt, so an edit made while the version
+// snapshot was taken is not marked as published.
+func (r *QuestionnaireRepository) MarkPublished(ctx context.Context, id primitive.ObjectID, version int, change models.QuestionnaireStatusChange, expectedUpdatedAt time.Time) error {
+	filter := bson.M{
+		"_id":        id,
+		"updated_at": expectedUpdatedAt,
+		"$or": []bson.M{
+			{"published_version": bson.M{"$lt": version}},
+			{"published_version": bson.M{"$exists": false}},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"published_version":       version,
+			"has_unpublished_changes": false,
+			"status":                  change.To,
+			"is_active":               true,
+			"updated_at":              change.ChangedAt,
+		},
+		"$push": bson.M{"status_history": change},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to mark questionnaire as published: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("conflict: questionnaire was modified or published at version %d concurrently", version)
+	}
+
+	return nil
+}
+
+// UpdateStatus applies a lifecycle transition and records it in the status history.
+// The update only matches if the questionnaire is still in the expected source status.
+func (r *QuestionnaireRepository) UpdateStatus(ctx context.Context, id primitive.ObjectID, change models.QuestionnaireStatusChange) error {
+	filter := bson.M{
+		"_id": id,
+		"$or": []bson.M{
+			{"status": change.From},
+			{"status": bson.M{"$exists": false}}, // created before lifecycle statuses
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":     change.To,
+			"is_active":  change.To != models.QuestionnaireStatusArchived,
+			"updated_at": change.ChangedAt,
+		},
+		"$push": bson.M{"status_history": change},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to update questionnaire status: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("conflict: questionnaire not found or no longer %s", change.From)
+	}
+
+	return nil
+}
+
+// GetAllByStatus retrieves questionnaires in a lifecycle status with pagination
+func (r *QuestionnaireRepository) GetAllByStatus(ctx context.Context, page, pageSize int64, status models.QuestionnaireStatus) ([]*models.Questionnaire, error) {
+	skip := (page - 1) * pageSize
+	opts := options.Find().
+		SetSkip(skip).
+		SetLimit(pageSize).
+		SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"status": status}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get questionnaires: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var questionnaires []*models.Questionnaire
+	if err = cursor.All(ctx, &questionnaires); err != nil {
+		return nil, fmt.Errorf("failed to decode questionnaires: %w", err)
+	}
+	for _, questionnaire := range questionnaires {
+		questionnaire.SortQuestions()
+	}
+
+	return questionnaires, nil
+}
+
 // Count returns the total number of questionnaires
 func (r *QuestionnaireRepository) Count(ctx context.Context, activeOnly bool) (int64, error) {
 	filter := bson.M{}
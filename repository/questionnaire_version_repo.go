@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"questionarie-service/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// QuestionnaireVersionRepository handles published questionnaire snapshots
+type QuestionnaireVersionRepository struct {
+	collection *mongo.Collection
+}
+
+// NewQuestionnaireVersionRepository creates a new QuestionnaireVersionRepository
+func NewQuestionnaireVersionRepository(db *mongo.Database) *QuestionnaireVersionRepository {
+	return &QuestionnaireVersionRepository{
+		collection: db.Collection("questionnaire_versions"),
+	}
+}
+
+// Create stores a new questionnaire version
+func (r *QuestionnaireVersionRepository) Create(ctx context.Context, version *models.QuestionnaireVersion) error {
+	_, err := r.collection.InsertOne(ctx, version)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("questionnaire version %d already exists", version.Version)
+		}
+		return fmt.Errorf("failed to create questionnaire version: %w", err)
+	}
+	return nil
+}
+
+// GetByQuestionnaireAndVersion retrieves a specific version of a questionnaire
+func (r *QuestionnaireVersionRepository) GetByQuestionnaireAndVersion(ctx context.Context, questionnaireID primitive.ObjectID, version int) (*models.QuestionnaireVersion, error) {
+	var v models.QuestionnaireVersion
+	err := r.collection.FindOne(ctx, bson.M{
+		"questionnaire_id": questionnaireID,
+		"version":          version,
+	}).Decode(&v)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("questionnaire version not found")
+		}
+		return nil, fmt.Errorf("failed to get questionnaire version: %w", err)
+	}
+	v.SortQuestions()
+	return &v, nil
+}
+
+// GetByQuestionnaireID retrieves all versions of a questionnaire, newest first
+func (r *QuestionnaireVersionRepository) GetByQuestionnaireID(ctx context.Context, questionnaireID primitive.ObjectID) ([]*models.QuestionnaireVersion, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "version", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"questionnaire_id": questionnaireID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get questionnaire versions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var versions []*models.QuestionnaireVersion
+	if err = cursor.All(ctx, &versions); err != nil {
+		return nil, fmt.Errorf("failed to decode questionnaire versions: %w", err)
+	}
+	for _, v := range versions {
+		v.SortQuestions()
+	}
+
+	return versions, nil
+}
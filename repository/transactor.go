@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Transactor runs groups of repository calls in a MongoDB transaction
+type Transactor struct {
+	client  *mongo.Client
+	enabled bool
+}
+
+// NewTransactor creates a new Transactor. Transactions need a replica set or sharded cluster;
+// with enabled false the calls run one by one, without atomicity, for standalone servers.
+func NewTransactor(db *mongo.Database, enabled bool) *Transactor {
+	return &Transactor{
+		client:  db.Client(),
+		enabled: enabled,
+	}
+}
+
+// WithTransaction runs fn in a transaction and commits it if fn returns nil. Repository calls made
+// with the context passed to fn take part in the transaction. fn may run more than once when the
+// transaction hits a transient error, so it must not have side effects outside the database.
+// Called with the context of a transaction, fn joins it instead of starting its own.
+func (t *Transactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if !t.enabled || mongo.SessionFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+
+	session, err := t.client.StartSession()
+	if err != nil {
+		return fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+	return err
+}
@@ -107,6 +107,7 @@ func (r *UserMetadataRepository) Update(ctx context.Context, userID string, meta
 			"company_id":    metadata.CompanyID,
 			"supervisor_id": metadata.SupervisorID,
 			"department":    metadata.Department,
+			"email":         metadata.Email,
 			"updated_at":    metadata.UpdatedAt,
 		},
 	}
@@ -165,6 +166,61 @@ func (r *UserMetadataRepository) UpdateSupervisor(ctx context.Context, userID, s
 	return nil
 }
 
+// LockSupervisorChain writes a fresh token to the given users, so that a concurrent transaction
+// changing any of them conflicts with the calling one and retries. Called in the transaction that
+// changes a supervisor, with the users its cycle check walked through, it keeps two concurrent
+// changes from each passing the check and creating a cycle together.
+func (r *UserMetadataRepository) LockSupervisorChain(ctx context.Context, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	update := bson.M{"$set": bson.M{"supervisor_lock": primitive.NewObjectID()}}
+	if _, err := r.collection.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": userIDs}}, update); err != nil {
+		return fmt.Errorf("failed to lock supervisor chain: %w", err)
+	}
+	return nil
+}
+
+// UpsertMany creates or updates many users metadata in a single ordered bulk write, keyed by
+// user ID, stopping at the first failure. Called with the context of a transaction, nothing is
+// written unless every user is. It returns how many documents were created and how many existing
+// ones changed.
+func (r *UserMetadataRepository) UpsertMany(ctx context.Context, users []*models.UserMetadata) (int64, int64, error) {
+	if len(users) == 0 {
+		return 0, 0, nil
+	}
+
+	now := time.Now()
+	writes := make([]mongo.WriteModel, 0, len(users))
+	for _, user := range users {
+		user.UpdatedAt = now
+		update := bson.M{
+			"$set": bson.M{
+				"company_id":    user.CompanyID,
+				"supervisor_id": user.SupervisorID,
+				"department":    user.Department,
+				"email":         user.Email,
+				"updated_at":    user.UpdatedAt,
+			},
+			"$setOnInsert": bson.M{
+				"created_at": user.CreatedAt,
+			},
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": user.ID}).
+			SetUpdate(update).
+			SetUpsert(true))
+	}
+
+	result, err := r.collection.BulkWrite(ctx, writes)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to upsert users metadata: %w", err)
+	}
+
+	return result.UpsertedCount, result.ModifiedCount, nil
+}
+
 // Delete deletes user metadata
 func (r *UserMetadataRepository) Delete(ctx context.Context, userID string) error {
 	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": userID})
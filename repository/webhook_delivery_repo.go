@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"questionarie-service/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WebhookDeliveryRepository handles the webhook delivery queue and log
+type WebhookDeliveryRepository struct {
+	collection *mongo.Collection
+}
+
+// NewWebhookDeliveryRepository creates a new WebhookDeliveryRepository
+func NewWebhookDeliveryRepository(db *mongo.Database) *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{
+		collection: db.Collection("webhook_deliveries"),
+	}
+}
+
+// CreateMany queues deliveries, skipping those of an event already queued for the same subscription
+func (r *WebhookDeliveryRepository) CreateMany(ctx context.Context, deliveries []*models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, len(deliveries))
+	for i, delivery := range deliveries {
+		docs[i] = delivery
+	}
+
+	_, err := r.collection.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	if err != nil {
+		var bulkErr mongo.BulkWriteException
+		if errors.As(err, &bulkErr) && bulkErr.WriteConcernError == nil && onlyDuplicateKeyErrors(bulkErr.WriteErrors) {
+			return nil
+		}
+		return fmt.Errorf("failed to queue webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+// onlyDuplicateKeyErrors reports whether every write error is a duplicate key
+func onlyDuplicateKeyErrors(writeErrors []mongo.BulkWriteError) bool {
+	for _, writeErr := range writeErrors {
+		if writeErr.Code != 11000 {
+			return false
+		}
+	}
+	return true
+}
+
+// GetBySubscriptionID retrieves the latest deliveries of a subscription, optionally filtered by status
+func (r *WebhookDeliveryRepository) GetBySubscriptionID(ctx context.Context, subscriptionID primitive.ObjectID, status *models.WebhookDeliveryStatus, limit int64) ([]*models.WebhookDelivery, error) {
+	filter := bson.M{"subscription_id": subscriptionID}
+	if status != nil {
+		filter["status"] = *status
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(limit)
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var deliveries []*models.WebhookDelivery
+	if err = cursor.All(ctx, &deliveries); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// ClaimNext takes the pending delivery that has waited longest and counts the attempt. The
+// delivery is not due again until lockUntil, so an instance that dies while sending it leaves it
+// to be retried. It returns nil when no delivery is due.
+func (r *WebhookDeliveryRepository) ClaimNext(ctx context.Context, now, lockUntil time.Time) (*models.WebhookDelivery, error) {
+	filter := bson.M{
+		"status":          models.WebhookDeliveryPending,
+		"next_attempt_at": bson.M{"$lte": now},
+	}
+	update := bson.M{
+		"$set": bson.M{"next_attempt_at": lockUntil, "updated_at": now},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var delivery models.WebhookDelivery
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&delivery)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to claim webhook delivery: %w", err)
+	}
+	return &delivery, nil
+}
+
+// MarkDelivered records a successful delivery
+func (r *WebhookDeliveryRepository) MarkDelivered(ctx context.Context, id primitive.ObjectID, statusCode int, deliveredAt time.Time) error {
+	return r.setStatus(ctx, id, bson.M{
+		"status":           models.WebhookDeliveryDelivered,
+		"last_status_code": statusCode,
+		"last_error":       "",
+		"delivered_at":     deliveredAt,
+	})
+}
+
+// MarkRetry records a failed attempt and when to try again
+func (r *WebhookDeliveryRepository) MarkRetry(ctx context.Context, id primitive.ObjectID, statusCode int, lastError string, nextAttemptAt time.Time) error {
+	return r.setStatus(ctx, id, bson.M{
+		"last_status_code": statusCode,
+		"last_error":       lastError,
+		"next_attempt_at":  nextAttemptAt,
+	})
+}
+
+// MarkFailed gives up on a delivery
+func (r *WebhookDeliveryRepository) MarkFailed(ctx context.Context, id primitive.ObjectID, statusCode int, lastError string) error {
+	return r.setStatus(ctx, id, bson.M{
+		"status":           models.WebhookDeliveryFailed,
+		"last_status_code": statusCode,
+		"last_error":       lastError,
+	})
+}
+
+// DeleteBySubscriptionID deletes the deliveries of a subscription
+func (r *WebhookDeliveryRepository) DeleteBySubscriptionID(ctx context.Context, subscriptionID primitive.ObjectID) error {
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"subscription_id": subscriptionID}); err != nil {
+		return fmt.Errorf("failed to delete webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+// setStatus updates the delivery fields of a webhook delivery
+func (r *WebhookDeliveryRepository) setStatus(ctx context.Context, id primitive.ObjectID, set bson.M) error {
+	set["updated_at"] = time.Now()
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": set})
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("webhook delivery not found")
+	}
+	return nil
+}
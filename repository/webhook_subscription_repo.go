@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"questionarie-service/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WebhookSubscriptionRepository handles webhook subscription operations
+type WebhookSubscriptionRepository struct {
+	collection *mongo.Collection
+}
+
+// NewWebhookSubscriptionRepository creates a new WebhookSubscriptionRepository
+func NewWebhookSubscriptionRepository(db *mongo.Database) *WebhookSubscriptionRepository {
+	return &WebhookSubscriptionRepository{
+		collection: db.Collection("webhook_subscriptions"),
+	}
+}
+
+// Create creates a new webhook subscription
+func (r *WebhookSubscriptionRepository) Create(ctx context.Context, subscription *models.WebhookSubscription) error {
+	_, err := r.collection.InsertOne(ctx, subscription)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a webhook subscription by ID
+func (r *WebhookSubscriptionRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*models.WebhookSubscription, error) {
+	var subscription models.WebhookSubscription
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&subscription)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("webhook subscription not found")
+		}
+		return nil, fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+	return &subscription, nil
+}
+
+// GetByCompanyID retrieves the webhook subscriptions of a company, newest first
+func (r *WebhookSubscriptionRepository) GetByCompanyID(ctx context.Context, companyID primitive.ObjectID) ([]*models.WebhookSubscription, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"company_id": companyID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook subscriptions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var subscriptions []*models.WebhookSubscription
+	if err = cursor.All(ctx, &subscriptions); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook subscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+// GetActiveForEvent retrieves the active subscriptions of a company that receive an event type
+func (r *WebhookSubscriptionRepository) GetActiveForEvent(ctx context.Context, companyID primitive.ObjectID, eventType models.WebhookEventType) ([]*models.WebhookSubscription, error) {
+	filter := bson.M{
+		"company_id":  companyID,
+		"is_active":   true,
+		"event_types": eventType,
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook subscriptions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var subscriptions []*models.WebhookSubscription
+	if err = cursor.All(ctx, &subscriptions); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook subscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+// CountByCompanyID counts the webhook subscriptions of a company
+func (r *WebhookSubscriptionRepository) CountByCompanyID(ctx context.Context, companyID primitive.ObjectID) (int64, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"company_id": companyID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count webhook subscriptions: %w", err)
+	}
+	return count, nil
+}
+
+// Update updates the settings of a webhook subscription
+func (r *WebhookSubscriptionRepository) Update(ctx context.Context, id primitive.ObjectID, subscription *models.WebhookSubscription) error {
+	subscription.UpdatedAt = time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"url":         subscription.URL,
+			"secret":      subscription.Secret,
+			"event_types": subscription.EventTypes,
+			"description": subscription.Description,
+			"is_active":   subscription.IsActive,
+			"updated_at":  subscription.UpdatedAt,
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook subscription: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("webhook subscription not found")
+	}
+
+	return nil
+}
+
+// Delete deletes a webhook subscription
+func (r *WebhookSubscriptionRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("webhook subscription not found")
+	}
+
+	return nil
+}
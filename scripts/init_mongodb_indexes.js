@@ -15,9 +15,18 @@ db.companies.createIndex({ "created_at": -1 });
 print("Creating indexes for 'questionnaires' collection...");
 db.questionnaires.createIndex({ "created_by": 1 });
 db.questionnaires.createIndex({ "is_active": 1 });
+db.questionnaires.createIndex({ "status": 1 });
+db.questionnaires.createIndex({ "source_questionnaire_id": 1 }, { sparse: true });
 db.questionnaires.createIndex({ "created_at": -1 });
 db.questionnaires.createIndex({ "title": 1 });
 
+// ===== Collection: questionnaire_versions =====
+print("Creating indexes for 'questionnaire_versions' collection...");
+db.questionnaire_versions.createIndex(
+  { "questionnaire_id": 1, "version": 1 },
+  { unique: true }
+);
+
 // ===== Collection: company_questionnaires =====
 print("Creating indexes for 'company_questionnaires' collection...");
 db.company_questionnaires.createIndex({ "company_id": 1 });
@@ -27,6 +36,9 @@ db.company_questionnaires.createIndex({ "is_active": 1 });
 db.company_questionnaires.createIndex({ "company_id": 1, "is_active": 1 });
 db.company_questionnaires.createIndex({ "assigned_by": 1 });
 db.company_questionnaires.createIndex({ "assigned_at": -1 });
+db.company_questionnaires.createIndex({ "campaign_id": 1, "campaign_run": 1 }, { sparse: true });
+db.company_questionnaires.createIndex({ "pending_activation": 1, "period_start": 1 });
+db.company_questionnaires.createIndex({ "closed_at": 1, "period_end": 1 });
 
 // ===== Collection: user_questionnaire_assignments =====
 print("Creating indexes for 'user_questionnaire_assignments' collection...");
@@ -53,6 +65,38 @@ db.users_metadata.createIndex({ "company_id": 1, "department": 1 });
 db.users_metadata.createIndex({ "department": 1 });
 db.users_metadata.createIndex({ "created_at": -1 });
 
+// ===== Collection: anonymous_responses =====
+print("Creating indexes for 'anonymous_responses' collection...");
+db.anonymous_responses.createIndex({ "company_questionnaire_id": 1 });
+
+// ===== Collection: campaigns =====
+print("Creating indexes for 'campaigns' collection...");
+db.campaigns.createIndex({ "company_id": 1, "created_at": -1 });
+db.campaigns.createIndex({ "is_active": 1, "next_run_at": 1 });
+
+// ===== Collection: notifications =====
+print("Creating indexes for 'notifications' collection...");
+db.notifications.createIndex({ "dedup_key": 1 }, { unique: true });
+db.notifications.createIndex({ "status": 1, "next_attempt_at": 1 });
+db.notifications.createIndex({ "company_id": 1, "created_at": -1 });
+
+// ===== Collection: webhook_subscriptions =====
+print("Creating indexes for 'webhook_subscriptions' collection...");
+db.webhook_subscriptions.createIndex({ "company_id": 1, "created_at": -1 });
+db.webhook_subscriptions.createIndex({ "company_id": 1, "is_active": 1, "event_types": 1 });
+
+// ===== Collection: webhook_deliveries =====
+print("Creating indexes for 'webhook_deliveries' collection...");
+db.webhook_deliveries.createIndex({ "subscription_id": 1, "event_id": 1 }, { unique: true });
+db.webhook_deliveries.createIndex({ "status": 1, "next_attempt_at": 1 });
+db.webhook_deliveries.createIndex({ "subscription_id": 1, "created_at": -1 });
+
+// ===== Collection: outbox_events =====
+print("Creating indexes for 'outbox_events' collection...");
+db.outbox_events.createIndex({ "status": 1, "next_attempt_at": 1 });
+// Published events are kept for a week; pending ones have no published_at and are never removed
+db.outbox_events.createIndex({ "published_at": 1 }, { expireAfterSeconds: 604800 });
+
 print("All indexes created successfully!");
 
 // Display created indexes
@@ -63,6 +107,9 @@ printjson(db.companies.getIndexes());
 print("\nQuestionnaires indexes:");
 printjson(db.questionnaires.getIndexes());
 
+print("\nQuestionnaire Versions indexes:");
+printjson(db.questionnaire_versions.getIndexes());
+
 print("\nCompany Questionnaires indexes:");
 printjson(db.company_questionnaires.getIndexes());
 
@@ -72,4 +119,22 @@ printjson(db.user_questionnaire_assignments.getIndexes());
 print("\nUsers Metadata indexes:");
 printjson(db.users_metadata.getIndexes());
 
+print("\nAnonymous Responses indexes:");
+printjson(db.anonymous_responses.getIndexes());
+
+print("\nCampaigns indexes:");
+printjson(db.campaigns.getIndexes());
+
+print("\nNotifications indexes:");
+printjson(db.notifications.getIndexes());
+
+print("\n===== Webhook Subscriptions Indexes =====");
+printjson(db.webhook_subscriptions.getIndexes());
+
+print("\n===== Webhook Deliveries Indexes =====");
+printjson(db.webhook_deliveries.getIndexes());
+
+print("\n===== Outbox Events Indexes =====");
+printjson(db.outbox_events.getIndexes());
+
 print("\n===== Index creation completed! =====");
@@ -3,8 +3,11 @@ package services
 import (
 	"context"
 	"fmt"
+	"log"
 	"questionarie-service/models"
 	"questionarie-service/repository"
+	"questionarie-service/utils"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -15,6 +18,10 @@ type AssignmentService struct {
 	companyQuestionnaireRepo *repository.CompanyQuestionnaireRepository
 	userMetadataRepo         *repository.UserMetadataRepository
 	questionnaireRepo        *repository.QuestionnaireRepository
+	versionRepo              *repository.QuestionnaireVersionRepository
+	anonymousResponseRepo    *repository.AnonymousResponseRepository
+	notificationService      *NotificationService
+	outboxService            *OutboxService
 }
 
 // NewAssignmentService creates a new AssignmentService
@@ -23,12 +30,20 @@ func NewAssignmentService(
 	companyQuestionnaireRepo *repository.CompanyQuestionnaireRepository,
 	userMetadataRepo *repository.UserMetadataRepository,
 	questionnaireRepo *repository.QuestionnaireRepository,
+	versionRepo *repository.QuestionnaireVersionRepository,
+	anonymousResponseRepo *repository.AnonymousResponseRepository,
+	notificationService *NotificationService,
+	outboxService *OutboxService,
 ) *AssignmentService {
 	return &AssignmentService{
 		assignmentRepo:           assignmentRepo,
 		companyQuestionnaireRepo: companyQuestionnaireRepo,
 		userMetadataRepo:         userMetadataRepo,
 		questionnaireRepo:        questionnaireRepo,
+		versionRepo:              versionRepo,
+		anonymousResponseRepo:    anonymousResponseRepo,
+		notificationService:      notificationService,
+		outboxService:            outboxService,
 	}
 }
 
@@ -39,6 +54,32 @@ func (s *AssignmentService) AssignToUsers(
 	companyQuestionnaireID primitive.ObjectID,
 	userIDs []string,
 	isSuperAdmin bool,
+) ([]*models.UserQuestionnaireAssignment, error) {
+	assignments, err := s.assignMissing(ctx, assignedBy, companyQuestionnaireID, userIDs, isSuperAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if len(assignments) == 0 {
+		return nil, fmt.Errorf("no new assignments created (all users already assigned)")
+	}
+	return assignments, nil
+}
+
+// AssignCampaignAudience assigns a campaign period to the users of its audience that do not have
+// it yet. Users already assigned are skipped without an error, so a period whose assignment failed
+// part way can be assigned again.
+func (s *AssignmentService) AssignCampaignAudience(ctx context.Context, assignedBy string, companyQuestionnaireID primitive.ObjectID, userIDs []string) ([]*models.UserQuestionnaireAssignment, error) {
+	return s.assignMissing(ctx, assignedBy, companyQuestionnaireID, userIDs, true)
+}
+
+// assignMissing assigns a company questionnaire to the listed users that do not have it yet and
+// returns the assignments created
+func (s *AssignmentService) assignMissing(
+	ctx context.Context,
+	assignedBy string,
+	companyQuestionnaireID primitive.ObjectID,
+	userIDs []string,
+	isSuperAdmin bool,
 ) ([]*models.UserQuestionnaireAssignment, error) {
 	if len(userIDs) == 0 {
 		return nil, fmt.Errorf("user IDs list cannot be empty")
@@ -50,12 +91,13 @@ func (s *AssignmentService) AssignToUsers(
 		return nil, fmt.Errorf("company questionnaire not found: %w", err)
 	}
 
-	if !cq.IsActive {
+	// Users can be assigned ahead of a period that has not started yet
+	if !cq.IsActive && !cq.PendingActivation {
 		return nil, fmt.Errorf("company questionnaire is not active")
 	}
 
 	// Verify questionnaire has questions
-	questionnaire, err := s.questionnaireRepo.GetByID(ctx, cq.QuestionnaireID)
+	questionnaire, err := s.resolveQuestionnaire(ctx, cq)
 	if err != nil {
 		return nil, fmt.Errorf("questionnaire not found: %w", err)
 	}
@@ -103,10 +145,16 @@ func (s *AssignmentService) AssignToUsers(
 			continue
 		}
 
-		// Create assignment
+		// Create assignment, stored together with its assignment.created event
 		assignment := models.NewUserQuestionnaireAssignment(companyQuestionnaireID, userID, assignedBy)
 
-		if err := s.assignmentRepo.Create(ctx, assignment); err != nil {
+		err = s.outboxService.InTransaction(ctx, func(ctx context.Context) error {
+			if err := s.assignmentRepo.Create(ctx, assignment); err != nil {
+				return err
+			}
+			return s.recordAssignmentEvent(ctx, models.WebhookEventAssignmentCreated, cq, assignment)
+		})
+		if err != nil {
 			return nil, fmt.Errorf("failed to create assignment for user %s: %w", userID, err)
 		}
 
@@ -114,7 +162,12 @@ func (s *AssignmentService) AssignToUsers(
 	}
 
 	if len(assignments) == 0 {
-		return nil, fmt.Errorf("no new assignments created (all users already assigned)")
+		return assignments, nil
+	}
+
+	// The assignments stand even if their notifications cannot be queued
+	if err := s.notificationService.NotifyAssigned(ctx, cq, assignments); err != nil {
+		log.Printf("Failed to queue assigned notifications for company questionnaire %s: %v", cq.ID.Hex(), err)
 	}
 
 	return assignments, nil
@@ -130,9 +183,47 @@ func (s *AssignmentService) GetUserAssignments(ctx context.Context, userID strin
 	return s.assignmentRepo.GetByUserID(ctx, userID, status)
 }
 
-// GetCompanyQuestionnaireAssignments retrieves all assignments for a company questionnaire
+// GetCompanyQuestionnaireAssignments retrieves all assignments for a company questionnaire.
+// Anonymous questionnaires only show the status of each assignment.
 func (s *AssignmentService) GetCompanyQuestionnaireAssignments(ctx context.Context, cqID primitive.ObjectID) ([]*models.UserQuestionnaireAssignment, error) {
-	return s.assignmentRepo.GetByCompanyQuestionnaireID(ctx, cqID)
+	assignments, err := s.assignmentRepo.GetByCompanyQuestionnaireID(ctx, cqID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.RedactAnonymousAssignments(ctx, assignments); err != nil {
+		return nil, err
+	}
+
+	return assignments, nil
+}
+
+// RedactAnonymousAssignments redacts the assignments that belong to anonymous company
+// questionnaires down to their status. Use it before showing assignments to anyone but their owner.
+func (s *AssignmentService) RedactAnonymousAssignments(ctx context.Context, assignments []*models.UserQuestionnaireAssignment) error {
+	anonymous := make(map[primitive.ObjectID]bool)
+	for _, assignment := range assignments {
+		isAnonymous, checked := anonymous[assignment.CompanyQuestionnaireID]
+		if !checked {
+			cq, err := s.companyQuestionnaireRepo.GetByID(ctx, assignment.CompanyQuestionnaireID)
+			if err != nil {
+				return err
+			}
+			isAnonymous = cq.IsAnonymous
+			anonymous[assignment.CompanyQuestionnaireID] = isAnonymous
+		}
+
+		if isAnonymous {
+			assignment.Redact()
+		}
+	}
+	return nil
+}
+
+// ResponseInput represents a single answer submitted for a question
+type ResponseInput struct {
+	QuestionID    string      `json:"question_id"`
+	ResponseValue interface{} `json:"response_value"`
 }
 
 // SaveResponse saves or updates a response for a question
@@ -143,38 +234,163 @@ func (s *AssignmentService) SaveResponse(
 	questionID string,
 	responseValue interface{},
 ) error {
+	questionnaire, err := s.getEditableQuestionnaire(ctx, assignmentID, userID)
+	if err != nil {
+		return err
+	}
+
+	// Validate answer against the question definition
+	verrs := utils.NewValidationErrors()
+	validateResponse(questionnaire, "", questionID, responseValue, verrs)
+	if verrs.HasErrors() {
+		return verrs
+	}
+
+	// Create response
+	response := models.NewResponse(questionID, responseValue)
+
+	// Add/update response, together with the assignment.started event of the first one
+	return s.outboxService.InTransaction(ctx, func(ctx context.Context) error {
+		started, err := s.assignmentRepo.AddOrUpdateResponse(ctx, assignmentID, *response)
+		if err != nil {
+			return err
+		}
+		if started {
+			return s.recordAssignmentStarted(ctx, assignmentID)
+		}
+		return nil
+	})
+}
+
+// UpdateResponses validates and saves several responses at once.
+// Nothing is saved unless every response is valid.
+func (s *AssignmentService) UpdateResponses(
+	ctx context.Context,
+	assignmentID primitive.ObjectID,
+	userID string,
+	responses []ResponseInput,
+) error {
+	return s.saveResponses(ctx, assignmentID, userID, "", responses)
+}
+
+// SaveSectionResponses validates and saves the responses of one section (page).
+// Every response must belong to a question of that section; nothing is saved otherwise.
+func (s *AssignmentService) SaveSectionResponses(
+	ctx context.Context,
+	assignmentID primitive.ObjectID,
+	userID string,
+	sectionID string,
+	responses []ResponseInput,
+) error {
+	if sectionID == "" {
+		return fmt.Errorf("section_id is required")
+	}
+	return s.saveResponses(ctx, assignmentID, userID, sectionID, responses)
+}
+
+// saveResponses validates and saves several responses, restricted to a section when sectionID is set
+func (s *AssignmentService) saveResponses(
+	ctx context.Context,
+	assignmentID primitive.ObjectID,
+	userID string,
+	sectionID string,
+	responses []ResponseInput,
+) error {
+	if len(responses) == 0 {
+		return fmt.Errorf("responses cannot be empty")
+	}
+
+	questionnaire, err := s.getEditableQuestionnaire(ctx, assignmentID, userID)
+	if err != nil {
+		return err
+	}
+	if sectionID != "" && questionnaire.GetSectionByID(sectionID) == nil {
+		return fmt.Errorf("section not found")
+	}
+
+	verrs := utils.NewValidationErrors()
+	for i, input := range responses {
+		prefix := fmt.Sprintf("responses[%d].", i)
+		if sectionID != "" {
+			if q := questionnaire.GetQuestionByID(input.QuestionID); q != nil && q.SectionID != sectionID {
+				verrs.Add(prefix+"question_id", "question is not in this section")
+				continue
+			}
+		}
+		validateResponse(questionnaire, prefix, input.QuestionID, input.ResponseValue, verrs)
+	}
+	if verrs.HasErrors() {
+		return verrs
+	}
+
+	return s.outboxService.InTransaction(ctx, func(ctx context.Context) error {
+		for _, input := range responses {
+			response := models.NewResponse(input.QuestionID, input.ResponseValue)
+			started, err := s.assignmentRepo.AddOrUpdateResponse(ctx, assignmentID, *response)
+			if err != nil {
+				return err
+			}
+			if started {
+				if err := s.recordAssignmentStarted(ctx, assignmentID); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// getEditableQuestionnaire verifies the assignment can receive responses and returns its questionnaire
+func (s *AssignmentService) getEditableQuestionnaire(ctx context.Context, assignmentID primitive.ObjectID, userID string) (*models.Questionnaire, error) {
 	// Get assignment
 	assignment, err := s.assignmentRepo.GetByID(ctx, assignmentID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Verify ownership
 	if assignment.UserID != userID {
-		return fmt.Errorf("unauthorized: assignment does not belong to user")
+		return nil, fmt.Errorf("unauthorized: assignment does not belong to user")
 	}
 
 	// Verify assignment is not completed
 	if assignment.Status == models.AssignmentStatusCompleted {
-		return fmt.Errorf("cannot modify completed assignment")
+		return nil, fmt.Errorf("cannot modify completed assignment")
+	}
+	if assignment.Status == models.AssignmentStatusExpired {
+		return nil, fmt.Errorf("conflict: assignment expired when its period ended")
 	}
 
 	// Get company questionnaire to check period
 	cq, err := s.companyQuestionnaireRepo.GetByID(ctx, assignment.CompanyQuestionnaireID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Verify period is active
 	if !cq.IsWithinPeriod() {
-		return fmt.Errorf("questionnaire period has expired")
+		return nil, fmt.Errorf("questionnaire period has expired")
 	}
 
-	// Create response
-	response := models.NewResponse(questionID, responseValue)
+	return s.resolveQuestionnaire(ctx, cq)
+}
+
+// validateResponse records field errors for an answer that does not fit its question
+func validateResponse(questionnaire *models.Questionnaire, prefix, questionID string, value interface{}, verrs *utils.ValidationErrors) {
+	if questionID == "" {
+		verrs.Add(prefix+"question_id", "is required")
+		return
+	}
 
-	// Add/update response
-	return s.assignmentRepo.AddOrUpdateResponse(ctx, assignmentID, *response)
+	question := questionnaire.GetQuestionByID(questionID)
+	if question == nil {
+		verrs.Add(prefix+"question_id", "question does not exist in this questionnaire")
+		return
+	}
+
+	if err := question.ValidateAnswer(value); err != nil {
+		verrs.Add(prefix+"response_value", err.Error())
+	}
 }
 
 // SubmitAssignment marks an assignment as completed
@@ -194,6 +410,9 @@ func (s *AssignmentService) SubmitAssignment(ctx context.Context, assignmentID p
 	if assignment.Status == models.AssignmentStatusCompleted {
 		return fmt.Errorf("assignment already completed")
 	}
+	if assignment.Status == models.AssignmentStatusExpired {
+		return fmt.Errorf("conflict: assignment expired when its period ended")
+	}
 
 	// Get questionnaire to validate all required questions are answered
 	cq, err := s.companyQuestionnaireRepo.GetByID(ctx, assignment.CompanyQuestionnaireID)
@@ -201,36 +420,222 @@ func (s *AssignmentService) SubmitAssignment(ctx context.Context, assignmentID p
 		return err
 	}
 
-	questionnaire, err := s.questionnaireRepo.GetByID(ctx, cq.QuestionnaireID)
+	questionnaire, err := s.resolveQuestionnaire(ctx, cq)
 	if err != nil {
 		return err
 	}
 
-	// Count required questions
-	requiredCount := 0
-	for _, q := range questionnaire.Questions {
-		if q.IsRequired {
-			requiredCount++
+	// Only required questions visible under the respondent's answers block submission
+	missing := assignment.GetMissingRequiredQuestions(questionnaire)
+	if len(missing) > 0 {
+		verrs := utils.NewValidationErrors()
+		for _, questionID := range missing {
+			verrs.Add("responses."+questionID, "required question not answered")
 		}
+		return verrs
 	}
 
-	// Count answered required questions
-	answeredRequired := 0
-	for _, response := range assignment.Responses {
-		for _, q := range questionnaire.Questions {
-			if q.QuestionID == response.QuestionID && q.IsRequired {
-				answeredRequired++
-				break
-			}
+	// Answers to questions that other answers later hid are neither kept nor scored
+	assignment.DropHiddenResponses(questionnaire)
+
+	// Score the answers when the questionnaire defines scoring
+	score := questionnaire.ComputeScore(assignment.Responses)
+
+	// Mark as completed, together with the assignment.completed event
+	err = s.outboxService.InTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if cq.IsAnonymous {
+			err = s.submitAnonymously(ctx, assignment, score)
+		} else {
+			err = s.assignmentRepo.Complete(ctx, assignmentID, assignment.Responses, score)
+		}
+		if err != nil {
+			return err
+		}
+
+		completedAt := time.Now()
+		assignment.Status = models.AssignmentStatusCompleted
+		assignment.CompletedAt = &completedAt
+		assignment.Score = score
+		return s.recordAssignmentEvent(ctx, models.WebhookEventAssignmentCompleted, cq, assignment)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.notificationService.NotifyCompleted(ctx, cq, assignment); err != nil {
+		log.Printf("Failed to queue completed notification for assignment %s: %v", assignment.ID.Hex(), err)
+	}
+	return nil
+}
+
+// submitAnonymously moves the responses and score to the anonymous store and completes the assignment.
+// Only the respondent's department and team are kept with the responses, for segmented reports.
+func (s *AssignmentService) submitAnonymously(ctx context.Context, assignment *models.UserQuestionnaireAssignment, score *models.AssignmentScore) error {
+	userMeta, err := s.userMetadataRepo.GetByID(ctx, assignment.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get respondent metadata: %w", err)
+	}
+
+	set := models.NewAnonymousResponseSet(assignment.CompanyQuestionnaireID, userMeta.Department, userMeta.SupervisorID, assignment.Responses, score)
+	if err := s.anonymousResponseRepo.Create(ctx, set); err != nil {
+		return err
+	}
+
+	if err := s.assignmentRepo.CompleteDetached(ctx, assignment.ID); err != nil {
+		// Do not count the answers twice if the assignment was submitted concurrently. Inside a
+		// transaction the abort already undoes the set; without one this delete is all there is.
+		if deleteErr := s.anonymousResponseRepo.Delete(ctx, set.ID); deleteErr != nil {
+			log.Printf("Failed to remove anonymous responses %s of assignment %s, they may be counted twice: %v", set.ID.Hex(), assignment.ID.Hex(), deleteErr)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// AssignmentProgress summarises how far a respondent is through an assignment
+type AssignmentProgress struct {
+	AssignmentID       primitive.ObjectID       `json:"assignment_id"`
+	Status             models.AssignmentStatus  `json:"status"`
+	Answered           int                      `json:"answered"`
+	Total              int                      `json:"total"`
+	Percentage         float64                  `json:"percentage"`
+	VisibleQuestionIDs []string                 `json:"visible_question_ids"`
+	MissingRequired    []string                 `json:"missing_required"`
+	Sections           []models.SectionProgress `json:"sections,omitempty"` // Only for questionnaires with sections
+}
+
+// GetAssignmentProgress computes progress over the questions visible to the respondent
+func (s *AssignmentService) GetAssignmentProgress(ctx context.Context, assignmentID primitive.ObjectID, userID string, isSuperAdmin bool) (*AssignmentProgress, error) {
+	assignment, err := s.assignmentRepo.GetByID(ctx, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isSuperAdmin && assignment.UserID != userID {
+		return nil, fmt.Errorf("unauthorized: assignment does not belong to user")
+	}
+
+	cq, err := s.companyQuestionnaireRepo.GetByID(ctx, assignment.CompanyQuestionnaireID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Progress and pages reveal answers, so only the respondent may see them on anonymous questionnaires
+	if cq.IsAnonymous && assignment.UserID != userID {
+		return nil, fmt.Errorf("unauthorized: assignment belongs to an anonymous questionnaire")
+	}
+
+	questionnaire, err := s.resolveQuestionnaire(ctx, cq)
+	if err != nil {
+		return nil, err
+	}
+
+	answered, total, percentage := assignment.GetProgress(questionnaire)
+
+	var sections []models.SectionProgress
+	if len(questionnaire.Sections) > 0 {
+		sections = assignment.GetSectionProgress(questionnaire)
+	}
+
+	visibleIDs := []string{}
+	for _, q := range questionnaire.VisibleQuestions(assignment.Responses) {
+		visibleIDs = append(visibleIDs, q.QuestionID)
+	}
+
+	return &AssignmentProgress{
+		AssignmentID:       assignment.ID,
+		Status:             assignment.Status,
+		Answered:           answered,
+		Total:              total,
+		Percentage:         percentage,
+		VisibleQuestionIDs: visibleIDs,
+		MissingRequired:    assignment.GetMissingRequiredQuestions(questionnaire),
+		Sections:           sections,
+	}, nil
+}
+
+// AssignmentPage is one section of an assignment as shown to the respondent
+type AssignmentPage struct {
+	AssignmentID      primitive.ObjectID     `json:"assignment_id"`
+	Section           models.Section         `json:"section"`
+	Questions         []models.Question      `json:"questions"` // Visible questions of the section, in order
+	Responses         []models.Response      `json:"responses"` // Existing answers to those questions
+	Progress          models.SectionProgress `json:"progress"`
+	PreviousSectionID string                 `json:"previous_section_id,omitempty"`
+	NextSectionID     string                 `json:"next_section_id,omitempty"`
+}
+
+// GetAssignmentPage retrieves one section of an assignment with its visible questions and saved answers
+func (s *AssignmentService) GetAssignmentPage(ctx context.Context, assignmentID primitive.ObjectID, sectionID, userID string, isSuperAdmin bool) (*AssignmentPage, error) {
+	assignment, err := s.assignmentRepo.GetByID(ctx, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isSuperAdmin && assignment.UserID != userID {
+		return nil, fmt.Errorf("unauthorized: assignment does not belong to user")
+	}
+
+	cq, err := s.companyQuestionnaireRepo.GetByID(ctx, assignment.CompanyQuestionnaireID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Progress and pages reveal answers, so only the respondent may see them on anonymous questionnaires
+	if cq.IsAnonymous && assignment.UserID != userID {
+		return nil, fmt.Errorf("unauthorized: assignment belongs to an anonymous questionnaire")
+	}
+
+	questionnaire, err := s.resolveQuestionnaire(ctx, cq)
+	if err != nil {
+		return nil, err
+	}
+
+	sections := questionnaire.SortedSections()
+	index := -1
+	for i, section := range sections {
+		if section.SectionID == sectionID {
+			index = i
+			break
 		}
 	}
+	if index < 0 {
+		return nil, fmt.Errorf("section not found")
+	}
 
-	if answeredRequired < requiredCount {
-		return fmt.Errorf("not all required questions answered (%d/%d)", answeredRequired, requiredCount)
+	page := &AssignmentPage{
+		AssignmentID: assignment.ID,
+		Section:      sections[index],
+		Questions:    []models.Question{},
+		Responses:    []models.Response{},
+	}
+	if index > 0 {
+		page.PreviousSectionID = sections[index-1].SectionID
+	}
+	if index < len(sections)-1 {
+		page.NextSectionID = sections[index+1].SectionID
 	}
 
-	// Mark as completed
-	return s.assignmentRepo.UpdateStatus(ctx, assignmentID, models.AssignmentStatusCompleted)
+	for _, q := range questionnaire.VisibleQuestions(assignment.Responses) {
+		if q.SectionID != sectionID {
+			continue
+		}
+		page.Questions = append(page.Questions, q)
+		if response := assignment.GetResponse(q.QuestionID); response != nil {
+			page.Responses = append(page.Responses, *response)
+		}
+	}
+
+	for _, p := range assignment.GetSectionProgress(questionnaire) {
+		if p.SectionID == sectionID {
+			page.Progress = p
+			break
+		}
+	}
+
+	return page, nil
 }
 
 // DeleteAssignment deletes an assignment
@@ -238,7 +643,8 @@ func (s *AssignmentService) DeleteAssignment(ctx context.Context, assignmentID p
 	return s.assignmentRepo.Delete(ctx, assignmentID)
 }
 
-// GetMyTeamAssignments retrieves assignments for users supervised by the given supervisor
+// GetMyTeamAssignments retrieves assignments for users supervised by the given supervisor.
+// Anonymous questionnaires only show the status of each assignment.
 func (s *AssignmentService) GetMyTeamAssignments(ctx context.Context, supervisorID string) ([]*models.UserQuestionnaireAssignment, error) {
 	// Get all users supervised by this supervisor
 	users, err := s.userMetadataRepo.GetBySupervisorID(ctx, supervisorID)
@@ -260,6 +666,10 @@ func (s *AssignmentService) GetMyTeamAssignments(ctx context.Context, supervisor
 		allAssignments = append(allAssignments, assignments...)
 	}
 
+	if err := s.RedactAnonymousAssignments(ctx, allAssignments); err != nil {
+		return nil, err
+	}
+
 	return allAssignments, nil
 }
 
@@ -274,3 +684,27 @@ func (s *AssignmentService) GetMyCompanyQuestionnaires(ctx context.Context, user
 	// Get company questionnaires
 	return s.companyQuestionnaireRepo.GetByCompanyID(ctx, userMeta.CompanyID, true)
 }
+
+// recordAssignmentStarted records the assignment.started event of an assignment that has just
+// received its first response
+func (s *AssignmentService) recordAssignmentStarted(ctx context.Context, assignmentID primitive.ObjectID) error {
+	assignment, err := s.assignmentRepo.GetByID(ctx, assignmentID)
+	if err != nil {
+		return err
+	}
+	cq, err := s.companyQuestionnaireRepo.GetByID(ctx, assignment.CompanyQuestionnaireID)
+	if err != nil {
+		return err
+	}
+	return s.recordAssignmentEvent(ctx, models.WebhookEventAssignmentStarted, cq, assignment)
+}
+
+// recordAssignmentEvent records a domain event about an assignment in the outbox
+func (s *AssignmentService) recordAssignmentEvent(ctx context.Context, eventType models.WebhookEventType, cq *models.CompanyQuestionnaire, assignment *models.UserQuestionnaireAssignment) error {
+	return s.outboxService.Record(ctx, cq.CompanyID, assignment.ID, eventType, models.NewAssignmentEventData(cq, assignment))
+}
+
+// resolveQuestionnaire returns the questionnaire version a company questionnaire runs on
+func (s *AssignmentService) resolveQuestionnaire(ctx context.Context, cq *models.CompanyQuestionnaire) (*models.Questionnaire, error) {
+	return resolveQuestionnaire(ctx, s.questionnaireRepo, s.versionRepo, cq)
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"questionarie-service/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CampaignTrend follows completion and scores across the periods of a campaign
+type CampaignTrend struct {
+	CampaignID      primitive.ObjectID     `json:"campaign_id"`
+	CampaignName    string                 `json:"campaign_name"`
+	QuestionnaireID primitive.ObjectID     `json:"questionnaire_id"`
+	Periods         []CampaignPeriodReport `json:"periods"` // Oldest first
+}
+
+// CampaignPeriodReport is the completion, and score distribution when scored, of one campaign period
+type CampaignPeriodReport struct {
+	CompanyQuestionnaireID primitive.ObjectID `json:"company_questionnaire_id"`
+	Run                    int                `json:"run"`
+	PeriodStart            string             `json:"period_start"`
+	PeriodEnd              string             `json:"period_end"`
+	Assigned               int64              `json:"assigned"`
+	Completed              int64              `json:"completed"`
+	CompletionPercentage   float64            `json:"completion_percentage"`
+	Score                  *ScoreDistribution `json:"score,omitempty"`
+}
+
+// GetCampaignTrend computes the completion of every period of a campaign, and the overall score
+// distribution of the periods whose questionnaire version is scored
+func (s *ReportService) GetCampaignTrend(ctx context.Context, campaignID primitive.ObjectID, userID string, isSuperAdmin bool) (*CampaignTrend, error) {
+	campaign, err := s.campaignRepo.GetByID(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCompanyAccess(ctx, campaign.CompanyID, userID, isSuperAdmin); err != nil {
+		return nil, err
+	}
+
+	company, err := s.companyRepo.GetByID(ctx, campaign.CompanyID)
+	if err != nil {
+		return nil, fmt.Errorf("company not found: %w", err)
+	}
+
+	companyQuestionnaires, err := s.companyQuestionnaireRepo.GetByCampaignID(ctx, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign periods: %w", err)
+	}
+
+	trend := &CampaignTrend{
+		CampaignID:      campaign.ID,
+		CampaignName:    campaign.Name,
+		QuestionnaireID: campaign.QuestionnaireID,
+		Periods:         []CampaignPeriodReport{},
+	}
+	for _, cq := range companyQuestionnaires {
+		stats, err := s.assignmentRepo.GetCompletionStats(ctx, cq.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get completion stats: %w", err)
+		}
+
+		period := CampaignPeriodReport{
+			CompanyQuestionnaireID: cq.ID,
+			Run:                    cq.CampaignRun,
+			PeriodStart:            cq.PeriodStart.Format("2006-01-02"),
+			PeriodEnd:              cq.PeriodEnd.Format("2006-01-02"),
+			Completed:              stats["completed"],
+		}
+		for _, count := range stats {
+			period.Assigned += count
+		}
+		if period.Assigned > 0 {
+			period.CompletionPercentage = float64(period.Completed) / float64(period.Assigned) * 100
+		}
+
+		questionnaire, err := s.resolveQuestionnaire(ctx, cq)
+		if err == nil && questionnaire.HasScoring() {
+			report, err := s.buildScoreReport(ctx, cq, questionnaire, models.ResolveMinGroupSize(company, cq))
+			if err != nil {
+				return nil, err
+			}
+			period.Score = &report.Overall
+		}
+
+		trend.Periods = append(trend.Periods, period)
+	}
+
+	return trend, nil
+}
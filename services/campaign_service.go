@@ -0,0 +1,379 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"questionarie-service/models"
+	"questionarie-service/repository"
+	"questionarie-service/utils"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CampaignService handles business logic for recurring campaigns
+type CampaignService struct {
+	campaignRepo             *repository.CampaignRepository
+	companyRepo              *repository.CompanyRepository
+	questionnaireRepo        *repository.QuestionnaireRepository
+	companyQuestionnaireRepo *repository.CompanyQuestionnaireRepository
+	assignmentRepo           *repository.AssignmentRepository
+	userMetadataRepo         *repository.UserMetadataRepository
+	companyService           *CompanyService
+	assignmentService        *AssignmentService
+	transactor               *repository.Transactor
+}
+
+// NewCampaignService creates a new CampaignService
+func NewCampaignService(
+	campaignRepo *repository.CampaignRepository,
+	companyRepo *repository.CompanyRepository,
+	questionnaireRepo *repository.QuestionnaireRepository,
+	companyQuestionnaireRepo *repository.CompanyQuestionnaireRepository,
+	assignmentRepo *repository.AssignmentRepository,
+	userMetadataRepo *repository.UserMetadataRepository,
+	companyService *CompanyService,
+	assignmentService *AssignmentService,
+	transactor *repository.Transactor,
+) *CampaignService {
+	return &CampaignService{
+		campaignRepo:             campaignRepo,
+		companyRepo:              companyRepo,
+		questionnaireRepo:        questionnaireRepo,
+		companyQuestionnaireRepo: companyQuestionnaireRepo,
+		assignmentRepo:           assignmentRepo,
+		userMetadataRepo:         userMetadataRepo,
+		companyService:           companyService,
+		assignmentService:        assignmentService,
+		transactor:               transactor,
+	}
+}
+
+// CampaignInput holds the settings of a new campaign
+type CampaignInput struct {
+	QuestionnaireID              primitive.ObjectID
+	SourceCompanyQuestionnaireID primitive.ObjectID // Copies questionnaire, anonymity, cohort size and audience from an existing period
+	Name                         string
+	Schedule                     models.CampaignSchedule
+	Audience                     *models.CampaignAudience
+	StartsAt                     time.Time
+	EndsAt                       *time.Time
+	IsAnonymous                  bool
+	MinGroupSize                 int
+}
+
+// CampaignUpdate holds the settings to change on a campaign; nil fields are left as they are
+type CampaignUpdate struct {
+	Name         *string
+	Schedule     *models.CampaignSchedule
+	Audience     *models.CampaignAudience
+	EndsAt       *time.Time
+	IsAnonymous  *bool
+	MinGroupSize *int
+	IsActive     *bool
+}
+
+// CreateCampaign creates a recurring campaign for a company (Super Admin only). With a source
+// company questionnaire the campaign continues it: same questionnaire, anonymity and cohort size,
+// and, unless an audience is given, the users it was assigned to.
+func (s *CampaignService) CreateCampaign(ctx context.Context, companyID primitive.ObjectID, input CampaignInput, createdBy string) (*models.Campaign, error) {
+	if _, err := s.companyRepo.GetByID(ctx, companyID); err != nil {
+		return nil, fmt.Errorf("company not found: %w", err)
+	}
+
+	if !input.SourceCompanyQuestionnaireID.IsZero() {
+		source, err := s.companyQuestionnaireRepo.GetByID(ctx, input.SourceCompanyQuestionnaireID)
+		if err != nil {
+			return nil, err
+		}
+		if source.CompanyID != companyID {
+			return nil, fmt.Errorf("invalid source: company questionnaire belongs to another company")
+		}
+		input.QuestionnaireID = source.QuestionnaireID
+		input.IsAnonymous = source.IsAnonymous
+		input.MinGroupSize = source.MinGroupSize
+		if input.Audience == nil {
+			assignments, err := s.assignmentRepo.GetByCompanyQuestionnaireID(ctx, source.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get source assignments: %w", err)
+			}
+			audience := &models.CampaignAudience{Type: models.CampaignAudienceUsers}
+			for _, assignment := range assignments {
+				audience.UserIDs = append(audience.UserIDs, assignment.UserID)
+			}
+			input.Audience = audience
+		}
+	}
+	if input.Audience == nil {
+		input.Audience = &models.CampaignAudience{Type: models.CampaignAudienceCompany}
+	}
+
+	questionnaire, err := s.questionnaireRepo.GetByID(ctx, input.QuestionnaireID)
+	if err != nil {
+		return nil, fmt.Errorf("questionnaire not found: %w", err)
+	}
+	if !questionnaire.IsAssignable() {
+		return nil, fmt.Errorf("conflict: only questionnaires with a published version can be assigned (current status: %s)", questionnaire.CurrentStatus())
+	}
+
+	campaign := models.NewCampaign(companyID, input.QuestionnaireID, input.Name, input.Schedule, *input.Audience, input.StartsAt, createdBy)
+	campaign.EndsAt = input.EndsAt
+	campaign.IsAnonymous = input.IsAnonymous
+	campaign.MinGroupSize = input.MinGroupSize
+	if err := s.validateCampaign(ctx, campaign); err != nil {
+		return nil, err
+	}
+	campaign.ScheduleFirstRun()
+
+	if err := s.campaignRepo.Create(ctx, campaign); err != nil {
+		return nil, fmt.Errorf("failed to create campaign: %w", err)
+	}
+
+	return campaign, nil
+}
+
+// GetCampaign retrieves a campaign by ID
+func (s *CampaignService) GetCampaign(ctx context.Context, id primitive.ObjectID) (*models.Campaign, error) {
+	return s.campaignRepo.GetByID(ctx, id)
+}
+
+// GetCompanyCampaigns retrieves the campaigns of a company
+func (s *CampaignService) GetCompanyCampaigns(ctx context.Context, companyID primitive.ObjectID) ([]*models.Campaign, error) {
+	return s.campaignRepo.GetByCompanyID(ctx, companyID)
+}
+
+// GetCampaignPeriods retrieves the company questionnaires generated by a campaign, oldest first
+func (s *CampaignService) GetCampaignPeriods(ctx context.Context, id primitive.ObjectID) ([]*models.CompanyQuestionnaire, error) {
+	if _, err := s.campaignRepo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.companyQuestionnaireRepo.GetByCampaignID(ctx, id)
+}
+
+// UpdateCampaign changes the settings of a campaign. Changes apply to periods generated from now
+// on. A new schedule or end date, or resuming a paused campaign, moves the next run to the first
+// period start after now.
+func (s *CampaignService) UpdateCampaign(ctx context.Context, id primitive.ObjectID, update CampaignUpdate) (*models.Campaign, error) {
+	campaign, err := s.campaignRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	reschedule := false
+	if update.Name != nil {
+		campaign.Name = *update.Name
+	}
+	if update.Schedule != nil {
+		campaign.Schedule = *update.Schedule
+		reschedule = true
+	}
+	if update.Audience != nil {
+		campaign.Audience = *update.Audience
+	}
+	if update.EndsAt != nil {
+		campaign.EndsAt = update.EndsAt
+		reschedule = true
+	}
+	if update.IsAnonymous != nil {
+		campaign.IsAnonymous = *update.IsAnonymous
+	}
+	if update.MinGroupSize != nil {
+		campaign.MinGroupSize = *update.MinGroupSize
+	}
+	if update.IsActive != nil {
+		reschedule = reschedule || (*update.IsActive && !campaign.IsActive)
+		campaign.IsActive = *update.IsActive
+	}
+
+	if err := s.validateCampaign(ctx, campaign); err != nil {
+		return nil, err
+	}
+	if reschedule {
+		campaign.Reschedule(time.Now())
+	}
+
+	if err := s.campaignRepo.Update(ctx, id, campaign); err != nil {
+		return nil, err
+	}
+
+	return campaign, nil
+}
+
+// validateCampaign checks the settings of a campaign and that its listed users are in the company
+func (s *CampaignService) validateCampaign(ctx context.Context, campaign *models.Campaign) error {
+	verrs := utils.NewValidationErrors()
+	if err := campaign.Validate(); err != nil {
+		addOptionsError(verrs, "campaign", err)
+		return verrs
+	}
+
+	if campaign.Audience.Type == models.CampaignAudienceUsers {
+		users, err := s.userMetadataRepo.GetByIDs(ctx, campaign.Audience.UserIDs)
+		if err != nil {
+			return err
+		}
+		inCompany := make(map[string]bool, len(users))
+		for _, user := range users {
+			inCompany[user.ID] = user.BelongsToCompany(campaign.CompanyID)
+		}
+		for _, userID := range campaign.Audience.UserIDs {
+			if !inCompany[userID] {
+				verrs.Add("audience.user_ids", fmt.Sprintf("user %s is not in the company", userID))
+			}
+		}
+	}
+
+	if verrs.HasErrors() {
+		return verrs
+	}
+	return nil
+}
+
+// RunDueCampaigns generates the period of every campaign whose next run is due and assigns it to
+// the campaign's audience. Periods whose assignment failed on an earlier run are assigned again
+// first. A failing campaign does not stop the others; its error is kept in LastError. It returns
+// the company questionnaires created.
+func (s *CampaignService) RunDueCampaigns(ctx context.Context, now time.Time) ([]*models.CompanyQuestionnaire, error) {
+	pending, err := s.campaignRepo.GetPendingAssignment(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, campaign := range pending {
+		if err := s.assignPeriod(ctx, campaign, *campaign.PendingPeriodID); err != nil {
+			if recordErr := s.campaignRepo.SetLastError(ctx, campaign.ID, err.Error()); recordErr != nil {
+				return nil, recordErr
+			}
+		}
+	}
+
+	campaigns, err := s.campaignRepo.GetDue(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	created := []*models.CompanyQuestionnaire{}
+	for _, campaign := range campaigns {
+		cq, err := s.runCampaign(ctx, campaign, now)
+		if err != nil {
+			if recordErr := s.campaignRepo.SetLastError(ctx, campaign.ID, err.Error()); recordErr != nil {
+				return created, recordErr
+			}
+		}
+		if cq != nil {
+			created = append(created, cq)
+		}
+	}
+
+	return created, nil
+}
+
+// runCampaign generates the due period of a campaign. Periods that ended before they could be
+// generated are skipped rather than created late. The run is claimed in the transaction that
+// creates the period, so a period is generated once even when several instances run campaigns,
+// and a run whose period cannot be created stays due. A campaign whose last period is still to be
+// assigned waits for it.
+func (s *CampaignService) runCampaign(ctx context.Context, campaign *models.Campaign, now time.Time) (*models.CompanyQuestionnaire, error) {
+	if campaign.PendingPeriodID != nil {
+		return nil, nil
+	}
+
+	scheduled := *campaign.NextRunAt
+	start, next := scheduled, campaign.NextRunAfter(scheduled)
+	skipped := 0
+	for !campaign.PeriodEnd(start, next).After(now) {
+		skipped++
+		if next == nil {
+			_, err := s.campaignRepo.ClaimRun(ctx, campaign.ID, scheduled, nil, false)
+			return nil, err
+		}
+		start, next = *next, campaign.NextRunAfter(*next)
+	}
+
+	if start.After(now) {
+		claimed, err := s.campaignRepo.ClaimRun(ctx, campaign.ID, scheduled, &start, false)
+		if err != nil || !claimed {
+			return nil, err
+		}
+		return nil, fmt.Errorf("skipped %d periods that ended before they could be generated", skipped)
+	}
+
+	var cq *models.CompanyQuestionnaire
+	claimed := false
+	err := s.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		claimed, err = s.campaignRepo.ClaimRun(ctx, campaign.ID, scheduled, next, true)
+		if err != nil || !claimed {
+			return err
+		}
+
+		cq, err = s.companyService.AssignCampaignPeriod(ctx, campaign, campaign.RunCount+1, start, campaign.PeriodEnd(start, next))
+		if err != nil {
+			return fmt.Errorf("failed to create period starting %s: %w", start.Format(time.RFC3339), err)
+		}
+		return s.campaignRepo.SetPendingPeriod(ctx, campaign.ID, &cq.ID)
+	})
+	if err != nil || !claimed {
+		return nil, err
+	}
+
+	if err := s.assignPeriod(ctx, campaign, cq.ID); err != nil {
+		return cq, err
+	}
+
+	if skipped > 0 {
+		return cq, fmt.Errorf("skipped %d periods that ended before they could be generated", skipped)
+	}
+	return cq, nil
+}
+
+// assignPeriod assigns a period of a campaign to its audience and clears it as pending. Users
+// assigned by an earlier, failed attempt are kept.
+func (s *CampaignService) assignPeriod(ctx context.Context, campaign *models.Campaign, periodID primitive.ObjectID) error {
+	userIDs, err := s.resolveAudience(ctx, campaign)
+	if err != nil {
+		return fmt.Errorf("failed to resolve audience: %w", err)
+	}
+	if len(userIDs) > 0 {
+		if _, err := s.assignmentService.AssignCampaignAudience(ctx, campaign.CreatedBy, periodID, userIDs); err != nil {
+			return fmt.Errorf("failed to assign period %s: %w", periodID.Hex(), err)
+		}
+	}
+	return s.campaignRepo.SetPendingPeriod(ctx, campaign.ID, nil)
+}
+
+// resolveAudience returns the users a campaign period is assigned to, as of now
+func (s *CampaignService) resolveAudience(ctx context.Context, campaign *models.Campaign) ([]string, error) {
+	var users []*models.UserMetadata
+	switch campaign.Audience.Type {
+	case models.CampaignAudienceCompany:
+		all, err := s.userMetadataRepo.GetByCompanyID(ctx, campaign.CompanyID)
+		if err != nil {
+			return nil, err
+		}
+		users = all
+	case models.CampaignAudienceDepartments:
+		for _, department := range campaign.Audience.Departments {
+			members, err := s.userMetadataRepo.GetByCompanyAndDepartment(ctx, campaign.CompanyID, department)
+			if err != nil {
+				return nil, err
+			}
+			users = append(users, members...)
+		}
+	case models.CampaignAudienceUsers:
+		listed, err := s.userMetadataRepo.GetByIDs(ctx, campaign.Audience.UserIDs)
+		if err != nil {
+			return nil, err
+		}
+		users = listed
+	}
+
+	seen := make(map[string]bool, len(users))
+	userIDs := make([]string, 0, len(users))
+	for _, user := range users {
+		if user.BelongsToCompany(campaign.CompanyID) && !seen[user.ID] {
+			seen[user.ID] = true
+			userIDs = append(userIDs, user.ID)
+		}
+	}
+	return userIDs, nil
+}
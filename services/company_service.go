@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"questionarie-service/models"
 	"questionarie-service/repository"
+	"questionarie-service/utils"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -15,6 +16,8 @@ type CompanyService struct {
 	companyRepo              *repository.CompanyRepository
 	companyQuestionnaireRepo *repository.CompanyQuestionnaireRepository
 	questionnaireRepo        *repository.QuestionnaireRepository
+	assignmentRepo           *repository.AssignmentRepository
+	outboxService            *OutboxService
 }
 
 // NewCompanyService creates a new CompanyService
@@ -22,11 +25,15 @@ func NewCompanyService(
 	companyRepo *repository.CompanyRepository,
 	companyQuestionnaireRepo *repository.CompanyQuestionnaireRepository,
 	questionnaireRepo *repository.QuestionnaireRepository,
+	assignmentRepo *repository.AssignmentRepository,
+	outboxService *OutboxService,
 ) *CompanyService {
 	return &CompanyService{
 		companyRepo:              companyRepo,
 		companyQuestionnaireRepo: companyQuestionnaireRepo,
 		questionnaireRepo:        questionnaireRepo,
+		assignmentRepo:           assignmentRepo,
+		outboxService:            outboxService,
 	}
 }
 
@@ -69,7 +76,7 @@ func (s *CompanyService) GetAllCompanies(ctx context.Context, page, pageSize int
 }
 
 // UpdateCompany updates a company
-func (s *CompanyService) UpdateCompany(ctx context.Context, id primitive.ObjectID, name string) error {
+func (s *CompanyService) UpdateCompany(ctx context.Context, id primitive.ObjectID, name string, minGroupSize *int, reminderPolicy *models.ReminderPolicy) error {
 	company, err := s.companyRepo.GetByID(ctx, id)
 	if err != nil {
 		return err
@@ -78,6 +85,20 @@ func (s *CompanyService) UpdateCompany(ctx context.Context, id primitive.ObjectI
 	if name != "" {
 		company.Name = name
 	}
+	if minGroupSize != nil {
+		if err := models.ValidateMinGroupSize(*minGroupSize); err != nil {
+			return err
+		}
+		company.MinGroupSize = *minGroupSize
+	}
+	if reminderPolicy != nil {
+		if err := reminderPolicy.Validate(); err != nil {
+			verrs := utils.NewValidationErrors()
+			addOptionsError(verrs, "reminder_policy", err)
+			return verrs
+		}
+		company.ReminderPolicy = reminderPolicy
+	}
 	company.UpdatedAt = time.Now()
 
 	return s.companyRepo.Update(ctx, id, company)
@@ -103,46 +124,87 @@ func (s *CompanyService) AssignQuestionnaireToCompany(
 	companyID, questionnaireID primitive.ObjectID,
 	assignedBy string,
 	periodStart, periodEnd time.Time,
+	isAnonymous bool,
+	minGroupSize int,
 ) (*models.CompanyQuestionnaire, error) {
+	cq := models.NewCompanyQuestionnaire(companyID, questionnaireID, 0, assignedBy, periodStart, periodEnd)
+	cq.IsAnonymous = isAnonymous
+	cq.MinGroupSize = minGroupSize
+
+	if err := s.createCompanyQuestionnaire(ctx, cq); err != nil {
+		return nil, err
+	}
+
+	return cq, nil
+}
+
+// AssignCampaignPeriod creates the company questionnaire of a campaign run, with the same checks
+// as AssignQuestionnaireToCompany
+func (s *CompanyService) AssignCampaignPeriod(ctx context.Context, campaign *models.Campaign, run int, periodStart, periodEnd time.Time) (*models.CompanyQuestionnaire, error) {
+	cq := models.NewCompanyQuestionnaire(campaign.CompanyID, campaign.QuestionnaireID, 0, campaign.CreatedBy, periodStart, periodEnd)
+	cq.IsAnonymous = campaign.IsAnonymous
+	cq.MinGroupSize = campaign.MinGroupSize
+	cq.CampaignID = &campaign.ID
+	cq.CampaignRun = run
+
+	if err := s.createCompanyQuestionnaire(ctx, cq); err != nil {
+		return nil, err
+	}
+
+	return cq, nil
+}
+
+// createCompanyQuestionnaire validates and stores a company questionnaire, pinned to the latest
+// published version of its questionnaire
+func (s *CompanyService) createCompanyQuestionnaire(ctx context.Context, cq *models.CompanyQuestionnaire) error {
 	// Validate company exists
-	if _, err := s.companyRepo.GetByID(ctx, companyID); err != nil {
-		return nil, fmt.Errorf("company not found: %w", err)
+	if _, err := s.companyRepo.GetByID(ctx, cq.CompanyID); err != nil {
+		return fmt.Errorf("company not found: %w", err)
 	}
 
-	// Validate questionnaire exists and is active
-	questionnaire, err := s.questionnaireRepo.GetByID(ctx, questionnaireID)
+	// Validate questionnaire exists and has a published version
+	questionnaire, err := s.questionnaireRepo.GetByID(ctx, cq.QuestionnaireID)
 	if err != nil {
-		return nil, fmt.Errorf("questionnaire not found: %w", err)
+		return fmt.Errorf("questionnaire not found: %w", err)
 	}
-	if !questionnaire.IsActive {
-		return nil, fmt.Errorf("questionnaire is not active")
-	}
-	if len(questionnaire.Questions) == 0 {
-		return nil, fmt.Errorf("questionnaire has no questions")
+	if !questionnaire.IsAssignable() {
+		return fmt.Errorf("conflict: only questionnaires with a published version can be assigned (current status: %s)", questionnaire.CurrentStatus())
 	}
 
 	// Validate period
-	if periodStart.After(periodEnd) || periodStart.Equal(periodEnd) {
-		return nil, fmt.Errorf("period start must be before period end")
+	if cq.PeriodStart.After(cq.PeriodEnd) || cq.PeriodStart.Equal(cq.PeriodEnd) {
+		return fmt.Errorf("period start must be before period end")
+	}
+
+	if err := models.ValidateMinGroupSize(cq.MinGroupSize); err != nil {
+		return err
 	}
 
 	// Check for duplicate assignment in overlapping period
-	isDuplicate, err := s.companyQuestionnaireRepo.CheckDuplicate(ctx, companyID, questionnaireID, periodStart, periodEnd)
+	isDuplicate, err := s.companyQuestionnaireRepo.CheckDuplicate(ctx, cq.CompanyID, cq.QuestionnaireID, cq.PeriodStart, cq.PeriodEnd)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check duplicate: %w", err)
+		return fmt.Errorf("failed to check duplicate: %w", err)
 	}
 	if isDuplicate {
-		return nil, fmt.Errorf("questionnaire already assigned to this company for overlapping period")
+		return fmt.Errorf("questionnaire already assigned to this company for overlapping period")
 	}
 
-	// Create assignment
-	cq := models.NewCompanyQuestionnaire(companyID, questionnaireID, assignedBy, periodStart, periodEnd)
+	// Pin the assignment to the latest published version
+	cq.QuestionnaireVersion = questionnaire.PublishedVersion
+	cq.ScheduleActivation(time.Now())
 
-	if err := s.companyQuestionnaireRepo.Create(ctx, cq); err != nil {
-		return nil, fmt.Errorf("failed to assign questionnaire: %w", err)
+	// The company questionnaire is stored together with its company_questionnaire.assigned event
+	err = s.outboxService.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.companyQuestionnaireRepo.Create(ctx, cq); err != nil {
+			return err
+		}
+		return s.recordCompanyQuestionnaireEvent(ctx, models.WebhookEventCompanyQuestionnaireAssigned, cq)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to assign questionnaire: %w", err)
 	}
 
-	return cq, nil
+	return nil
 }
 
 // GetCompanyQuestionnaires retrieves all questionnaires assigned to a company
@@ -155,8 +217,10 @@ func (s *CompanyService) GetActiveCompanyQuestionnaires(ctx context.Context, com
 	return s.companyQuestionnaireRepo.GetActiveByCompanyAndPeriod(ctx, companyID)
 }
 
-// UpdateCompanyQuestionnaire updates a company questionnaire assignment
-func (s *CompanyService) UpdateCompanyQuestionnaire(ctx context.Context, id primitive.ObjectID, periodStart, periodEnd time.Time, isActive bool) error {
+// UpdateCompanyQuestionnaire updates a company questionnaire assignment. Setting isActive takes
+// over from the scheduler; extending the period of a closed company questionnaire reopens it and
+// its expired assignments.
+func (s *CompanyService) UpdateCompanyQuestionnaire(ctx context.Context, id primitive.ObjectID, periodStart, periodEnd time.Time, isActive *bool, isAnonymous *bool, minGroupSize *int) error {
 	cq, err := s.companyQuestionnaireRepo.GetByID(ctx, id)
 	if err != nil {
 		return err
@@ -171,9 +235,108 @@ func (s *CompanyService) UpdateCompanyQuestionnaire(ctx context.Context, id prim
 		cq.PeriodEnd = periodEnd
 	}
 
-	cq.IsActive = isActive
+	now := time.Now()
+	reopen := cq.IsClosed() && cq.PeriodEnd.After(now)
+	if reopen {
+		cq.ClosedAt = nil
+		cq.IsActive = true
+	}
+
+	if isActive != nil {
+		if *isActive && cq.IsClosed() {
+			return fmt.Errorf("conflict: the period has ended, extend period_end to reopen it")
+		}
+		cq.IsActive = *isActive
+		cq.PendingActivation = false
+	}
+
+	if minGroupSize != nil {
+		if err := models.ValidateMinGroupSize(*minGroupSize); err != nil {
+			return err
+		}
+		cq.MinGroupSize = *minGroupSize
+	}
+
+	// Anonymity can only change before anyone is assigned, so no answers are ever stored in both modes
+	if isAnonymous != nil && *isAnonymous != cq.IsAnonymous {
+		count, err := s.assignmentRepo.CountByCompanyQuestionnaireID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("conflict: anonymity cannot be changed once users have been assigned")
+		}
+		cq.IsAnonymous = *isAnonymous
+	}
+
+	if err := s.companyQuestionnaireRepo.Update(ctx, id, cq); err != nil {
+		return err
+	}
+
+	if reopen {
+		if _, err := s.assignmentRepo.ReopenExpired(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PeriodTransitions counts the changes made by one run of the period lifecycle job
+type PeriodTransitions struct {
+	Activated          int64 `json:"activated"`
+	Closed             int   `json:"closed"`
+	ExpiredAssignments int64 `json:"expired_assignments"`
+}
+
+// RunPeriodTransitions activates the company questionnaires whose period has started and closes
+// those whose period has ended, expiring their unfinished assignments. Each step can be repeated
+// safely, so a run interrupted halfway is finished by the next one.
+func (s *CompanyService) RunPeriodTransitions(ctx context.Context, now time.Time) (*PeriodTransitions, error) {
+	result := &PeriodTransitions{}
+
+	activated, err := s.companyQuestionnaireRepo.ActivateDue(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	result.Activated = activated
+
+	due, err := s.companyQuestionnaireRepo.GetDueForClosing(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	for _, cq := range due {
+		// Assignments expire first, so the company questionnaire is only closed once they have
+		expired, err := s.assignmentRepo.ExpireOpen(ctx, cq.ID, now)
+		if err != nil {
+			return result, err
+		}
+		result.ExpiredAssignments += expired
+
+		// The company questionnaire is closed together with its company_questionnaire.closed event
+		var closed bool
+		err = s.outboxService.InTransaction(ctx, func(ctx context.Context) error {
+			var err error
+			if closed, err = s.companyQuestionnaireRepo.Close(ctx, cq.ID, now); err != nil || !closed {
+				return err
+			}
+			cq.IsActive, cq.PendingActivation, cq.ClosedAt = false, false, &now
+			return s.recordCompanyQuestionnaireEvent(ctx, models.WebhookEventCompanyQuestionnaireClosed, cq)
+		})
+		if err != nil {
+			return result, err
+		}
+		if closed {
+			result.Closed++
+		}
+	}
+
+	return result, nil
+}
 
-	return s.companyQuestionnaireRepo.Update(ctx, id, cq)
+// recordCompanyQuestionnaireEvent records a domain event about a company questionnaire in the outbox
+func (s *CompanyService) recordCompanyQuestionnaireEvent(ctx context.Context, eventType models.WebhookEventType, cq *models.CompanyQuestionnaire) error {
+	return s.outboxService.Record(ctx, cq.CompanyID, cq.ID, eventType, models.NewCompanyQuestionnaireEventData(cq))
 }
 
 // DeactivateCompanyQuestionnaire deactivates a company questionnaire
@@ -0,0 +1,378 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"questionarie-service/models"
+	"questionarie-service/notifications"
+	"questionarie-service/repository"
+	"questionarie-service/templates"
+	"strings"
+	"text/template"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// notificationTemplates are the notification templates, one per event, parsed once at startup
+var notificationTemplates = template.Must(template.New("notifications").Funcs(template.FuncMap{
+	"date": func(t time.Time) string { return t.Format("02/01/2006") },
+}).ParseFS(templates.Notifications, "notifications/*.tmpl"))
+
+const (
+	// overdueWindow is how long after a period closes its overdue notifications can still be queued
+	overdueWindow = 7 * 24 * time.Hour
+	// deliveryLock is how long a claimed notification waits before another delivery attempt if the
+	// instance sending it stops
+	deliveryLock = 5 * time.Minute
+	// deliveryBatchSize is the maximum number of notifications delivered per run
+	deliveryBatchSize = 200
+	// maxNotificationsListed is the maximum number of notifications returned by a listing
+	maxNotificationsListed = 500
+)
+
+// NotificationService queues templated notifications about assignments in the notification outbox
+// and delivers them through the configured transport
+type NotificationService struct {
+	notificationRepo         *repository.NotificationRepository
+	companyRepo              *repository.CompanyRepository
+	companyQuestionnaireRepo *repository.CompanyQuestionnaireRepository
+	assignmentRepo           *repository.AssignmentRepository
+	userMetadataRepo         *repository.UserMetadataRepository
+	questionnaireRepo        *repository.QuestionnaireRepository
+	versionRepo              *repository.QuestionnaireVersionRepository
+	transport                notifications.Transport
+	appURL                   string
+}
+
+// NewNotificationService creates a new NotificationService. appURL is the base URL of the web
+// application linked from notifications, or empty to leave links out.
+func NewNotificationService(
+	notificationRepo *repository.NotificationRepository,
+	companyRepo *repository.CompanyRepository,
+	companyQuestionnaireRepo *repository.CompanyQuestionnaireRepository,
+	assignmentRepo *repository.AssignmentRepository,
+	userMetadataRepo *repository.UserMetadataRepository,
+	questionnaireRepo *repository.QuestionnaireRepository,
+	versionRepo *repository.QuestionnaireVersionRepository,
+	transport notifications.Transport,
+	appURL string,
+) *NotificationService {
+	return &NotificationService{
+		notificationRepo:         notificationRepo,
+		companyRepo:              companyRepo,
+		companyQuestionnaireRepo: companyQuestionnaireRepo,
+		assignmentRepo:           assignmentRepo,
+		userMetadataRepo:         userMetadataRepo,
+		questionnaireRepo:        questionnaireRepo,
+		versionRepo:              versionRepo,
+		transport:                transport,
+		appURL:                   strings.TrimRight(appURL, "/"),
+	}
+}
+
+// NotificationRun counts the notifications queued and delivered by one run of the notification job
+type NotificationRun struct {
+	Reminders int `json:"reminders"`
+	Overdue   int `json:"overdue"`
+	Sent      int `json:"sent"`
+	Failed    int `json:"failed"`
+}
+
+// notificationData is what the notification templates are rendered with
+type notificationData struct {
+	UserID             string
+	CompanyName        string
+	QuestionnaireTitle string
+	PeriodStart        time.Time
+	PeriodEnd          time.Time
+	DaysLeft           int
+	AssignmentID       string
+	AppURL             string
+}
+
+// periodContext is what the notifications about the assignments of one period share
+type periodContext struct {
+	cq      *models.CompanyQuestionnaire
+	company *models.Company
+	title   string
+}
+
+// NotifyAssigned queues the assigned notification of new assignments of a company questionnaire
+func (s *NotificationService) NotifyAssigned(ctx context.Context, cq *models.CompanyQuestionnaire, assignments []*models.UserQuestionnaireAssignment) error {
+	period, err := s.loadPeriod(ctx, cq, nil)
+	if err != nil {
+		return err
+	}
+	for _, assignment := range assignments {
+		key := models.NotificationDedupKey(models.NotificationEventAssigned, assignment.ID)
+		if err := s.enqueue(ctx, models.NotificationEventAssigned, key, period, assignment, time.Now()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NotifyCompleted queues the completed notification of a submitted assignment
+func (s *NotificationService) NotifyCompleted(ctx context.Context, cq *models.CompanyQuestionnaire, assignment *models.UserQuestionnaireAssignment) error {
+	period, err := s.loadPeriod(ctx, cq, nil)
+	if err != nil {
+		return err
+	}
+	key := models.NotificationDedupKey(models.NotificationEventCompleted, assignment.ID)
+	return s.enqueue(ctx, models.NotificationEventCompleted, key, period, assignment, time.Now())
+}
+
+// RunNotifications queues the reminders and overdue notifications due at now and then delivers
+// pending notifications. Queueing is deduplicated, so runs can repeat or overlap safely.
+func (s *NotificationService) RunNotifications(ctx context.Context, now time.Time) (*NotificationRun, error) {
+	result := &NotificationRun{}
+	var errs []string
+
+	reminders, err := s.QueueReminders(ctx, now)
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	result.Reminders = reminders
+
+	overdue, err := s.QueueOverdue(ctx, now)
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	result.Overdue = overdue
+
+	sent, failed, err := s.DeliverPending(ctx, now)
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	result.Sent, result.Failed = sent, failed
+
+	if len(errs) > 0 {
+		return result, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return result, nil
+}
+
+// QueueReminders queues a reminder for each unfinished assignment of an active period that has
+// reached one of its company's reminder days. It returns how many reminders were queued.
+func (s *NotificationService) QueueReminders(ctx context.Context, now time.Time) (int, error) {
+	cqs, err := s.companyQuestionnaireRepo.GetEndingBetween(ctx, now, now.AddDate(0, 0, models.MaxReminderDays))
+	if err != nil {
+		return 0, err
+	}
+
+	companies := make(map[primitive.ObjectID]*models.Company)
+	queued := 0
+	for _, cq := range cqs {
+		period, err := s.loadPeriod(ctx, cq, companies)
+		if err != nil {
+			return queued, err
+		}
+		policy := period.company.EffectiveReminderPolicy()
+		if !policy.Enabled || cq.PeriodEnd.After(now.AddDate(0, 0, policy.MaxDaysBefore())) {
+			continue
+		}
+
+		assignments, err := s.assignmentRepo.GetByStatusWithoutResponses(ctx, cq.ID, models.AssignmentStatusPending, models.AssignmentStatusInProgress)
+		if err != nil {
+			return queued, err
+		}
+		keys := make(map[primitive.ObjectID]string, len(assignments))
+		for _, assignment := range assignments {
+			if days, ok := policy.DueReminder(assignment.AssignedAt, cq.PeriodEnd, now); ok {
+				keys[assignment.ID] = models.ReminderDedupKey(assignment.ID, days)
+			}
+		}
+
+		n, err := s.enqueueNew(ctx, models.NotificationEventReminder, keys, period, assignments, now)
+		queued += n
+		if err != nil {
+			return queued, err
+		}
+	}
+	return queued, nil
+}
+
+// QueueOverdue queues an overdue notification for each assignment that expired when its period
+// closed, for periods closed in the last week. It returns how many notifications were queued.
+func (s *NotificationService) QueueOverdue(ctx context.Context, now time.Time) (int, error) {
+	cqs, err := s.companyQuestionnaireRepo.GetClosedSince(ctx, now.Add(-overdueWindow))
+	if err != nil {
+		return 0, err
+	}
+
+	companies := make(map[primitive.ObjectID]*models.Company)
+	queued := 0
+	for _, cq := range cqs {
+		period, err := s.loadPeriod(ctx, cq, companies)
+		if err != nil {
+			return queued, err
+		}
+		policy := period.company.EffectiveReminderPolicy()
+		if !policy.Enabled || !policy.Overdue {
+			continue
+		}
+
+		assignments, err := s.assignmentRepo.GetByStatusWithoutResponses(ctx, cq.ID, models.AssignmentStatusExpired)
+		if err != nil {
+			return queued, err
+		}
+		keys := make(map[primitive.ObjectID]string, len(assignments))
+		for _, assignment := range assignments {
+			keys[assignment.ID] = models.NotificationDedupKey(models.NotificationEventOverdue, assignment.ID)
+		}
+
+		n, err := s.enqueueNew(ctx, models.NotificationEventOverdue, keys, period, assignments, now)
+		queued += n
+		if err != nil {
+			return queued, err
+		}
+	}
+	return queued, nil
+}
+
+// DeliverPending sends the notifications due at now through the transport. Failed attempts are
+// retried with a growing delay until MaxNotificationAttempts. It returns how many notifications
+// were sent and how many failed for good.
+func (s *NotificationService) DeliverPending(ctx context.Context, now time.Time) (int, int, error) {
+	sent, failed := 0, 0
+	for i := 0; i < deliveryBatchSize; i++ {
+		notification, err := s.notificationRepo.ClaimNext(ctx, now, now.Add(deliveryLock))
+		if err != nil {
+			return sent, failed, err
+		}
+		if notification == nil {
+			break
+		}
+
+		sendErr := s.transport.Send(ctx, notification)
+		switch {
+		case sendErr == nil:
+			err = s.notificationRepo.MarkSent(ctx, notification.ID, s.transport.Name(), time.Now())
+			sent++
+		case errors.Is(sendErr, notifications.ErrNoRecipient) || notification.Attempts >= models.MaxNotificationAttempts:
+			err = s.notificationRepo.MarkFailed(ctx, notification.ID, sendErr.Error())
+			failed++
+		default:
+			err = s.notificationRepo.MarkRetry(ctx, notification.ID, sendErr.Error(), time.Now().Add(models.NotificationRetryDelay(notification.Attempts)))
+		}
+		if err != nil {
+			return sent, failed, err
+		}
+	}
+	return sent, failed, nil
+}
+
+// GetCompanyNotifications retrieves the latest notifications of a company, optionally filtered by status and user
+func (s *NotificationService) GetCompanyNotifications(ctx context.Context, companyID primitive.ObjectID, status *models.NotificationStatus, userID string) ([]*models.Notification, error) {
+	if _, err := s.companyRepo.GetByID(ctx, companyID); err != nil {
+		return nil, err
+	}
+	return s.notificationRepo.GetByCompanyID(ctx, companyID, status, userID, maxNotificationsListed)
+}
+
+// RetryNotification queues a failed notification for delivery again
+func (s *NotificationService) RetryNotification(ctx context.Context, id primitive.ObjectID) (*models.Notification, error) {
+	if _, err := s.notificationRepo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := s.notificationRepo.Retry(ctx, id, time.Now()); err != nil {
+		return nil, err
+	}
+	return s.notificationRepo.GetByID(ctx, id)
+}
+
+// enqueueNew queues the notifications of the assignments with a dedup key in keys that are not in
+// the outbox yet, and returns how many were queued
+func (s *NotificationService) enqueueNew(ctx context.Context, event models.NotificationEvent, keys map[primitive.ObjectID]string, period *periodContext, assignments []*models.UserQuestionnaireAssignment, now time.Time) (int, error) {
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	list := make([]string, 0, len(keys))
+	for _, key := range keys {
+		list = append(list, key)
+	}
+	existing, err := s.notificationRepo.GetExistingDedupKeys(ctx, list)
+	if err != nil {
+		return 0, err
+	}
+
+	queued := 0
+	for _, assignment := range assignments {
+		key, ok := keys[assignment.ID]
+		if !ok || existing[key] {
+			continue
+		}
+		if err := s.enqueue(ctx, event, key, period, assignment, now); err != nil {
+			return queued, err
+		}
+		queued++
+	}
+	return queued, nil
+}
+
+// enqueue renders the notification of an event about an assignment and adds it to the outbox,
+// unless its dedup key is already there
+func (s *NotificationService) enqueue(ctx context.Context, event models.NotificationEvent, dedupKey string, period *periodContext, assignment *models.UserQuestionnaireAssignment, now time.Time) error {
+	notification := models.NewNotification(event, dedupKey, period.cq, assignment)
+	if user, err := s.userMetadataRepo.GetByID(ctx, assignment.UserID); err == nil {
+		notification.Recipient = user.Email
+	}
+
+	data := notificationData{
+		UserID:             assignment.UserID,
+		CompanyName:        period.company.Name,
+		QuestionnaireTitle: period.title,
+		PeriodStart:        period.cq.PeriodStart,
+		PeriodEnd:          period.cq.PeriodEnd,
+		DaysLeft:           int(math.Ceil(period.cq.PeriodEnd.Sub(now).Hours() / 24)),
+		AssignmentID:       assignment.ID.Hex(),
+		AppURL:             s.appURL,
+	}
+	subject, body, err := renderNotification(event, data)
+	if err != nil {
+		return err
+	}
+	notification.Subject, notification.Body = subject, body
+
+	_, err = s.notificationRepo.Enqueue(ctx, notification)
+	return err
+}
+
+// loadPeriod loads the company and questionnaire title of a company questionnaire, reusing the
+// companies already loaded in cache when one is given
+func (s *NotificationService) loadPeriod(ctx context.Context, cq *models.CompanyQuestionnaire, cache map[primitive.ObjectID]*models.Company) (*periodContext, error) {
+	company := cache[cq.CompanyID]
+	if company == nil {
+		loaded, err := s.companyRepo.GetByID(ctx, cq.CompanyID)
+		if err != nil {
+			return nil, err
+		}
+		company = loaded
+		if cache != nil {
+			cache[cq.CompanyID] = company
+		}
+	}
+
+	questionnaire, err := resolveQuestionnaire(ctx, s.questionnaireRepo, s.versionRepo, cq)
+	if err != nil {
+		return nil, err
+	}
+
+	return &periodContext{cq: cq, company: company, title: questionnaire.Title}, nil
+}
+
+// renderNotification executes the template of an event. The first line of its output is the
+// subject and the rest the body.
+func renderNotification(event models.NotificationEvent, data notificationData) (string, string, error) {
+	var out bytes.Buffer
+	if err := notificationTemplates.ExecuteTemplate(&out, string(event)+".tmpl", data); err != nil {
+		return "", "", fmt.Errorf("failed to render notification template %s: %w", event, err)
+	}
+
+	subject, body, _ := strings.Cut(out.String(), "\n")
+	return strings.TrimSpace(subject), strings.TrimSpace(body) + "\n", nil
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"questionarie-service/models"
+	"questionarie-service/outbox"
+	"questionarie-service/repository"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// outboxLock is how long a claimed event waits before another attempt if the instance relaying
+	// it stops
+	outboxLock = time.Minute
+	// outboxBatchSize is the maximum number of events relayed per run
+	outboxBatchSize = 500
+)
+
+// OutboxService records domain events in the transaction of the change they report and relays
+// them to the configured sink
+type OutboxService struct {
+	outboxRepo *repository.OutboxRepository
+	transactor *repository.Transactor
+	sink       outbox.Sink
+}
+
+// NewOutboxService creates a new OutboxService
+func NewOutboxService(
+	outboxRepo *repository.OutboxRepository,
+	transactor *repository.Transactor,
+	sink outbox.Sink,
+) *OutboxService {
+	return &OutboxService{
+		outboxRepo: outboxRepo,
+		transactor: transactor,
+		sink:       sink,
+	}
+}
+
+// OutboxRun counts the events handled by one run of the outbox relay
+type OutboxRun struct {
+	Published int `json:"published"`
+	Retrying  int `json:"retrying"`
+}
+
+// InTransaction runs fn in a transaction. Changes and the events recorded with Record, both made
+// with the context passed to fn, are committed together or not at all.
+func (s *OutboxService) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return s.transactor.WithTransaction(ctx, fn)
+}
+
+// Record stores a domain event about an aggregate of a company in the outbox
+func (s *OutboxService) Record(ctx context.Context, companyID, aggregateID primitive.ObjectID, eventType models.WebhookEventType, data interface{}) error {
+	event, err := models.NewOutboxEvent(companyID, aggregateID, eventType, data)
+	if err != nil {
+		return err
+	}
+	return s.outboxRepo.Create(ctx, event)
+}
+
+// RelayPending publishes the events due at now to the sink. Events the sink rejects are retried
+// with a growing delay for as long as it takes.
+func (s *OutboxService) RelayPending(ctx context.Context, now time.Time) (*OutboxRun, error) {
+	result := &OutboxRun{}
+
+	for i := 0; i < outboxBatchSize; i++ {
+		event, err := s.outboxRepo.ClaimNext(ctx, now, now.Add(outboxLock))
+		if err != nil {
+			return result, err
+		}
+		if event == nil {
+			break
+		}
+
+		if publishErr := s.sink.Publish(ctx, event); publishErr != nil {
+			err = s.outboxRepo.MarkRetry(ctx, event.ID, publishErr.Error(), time.Now().Add(models.OutboxRetryDelay(event.Attempts)))
+			result.Retrying++
+		} else {
+			err = s.outboxRepo.MarkPublished(ctx, event.ID, time.Now())
+			result.Published++
+		}
+		if err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"questionarie-service/models"
+	"questionarie-service/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ExportQuestionnaire exports a questionnaire as a portable document. When version is set the
+// document is taken from that published version, otherwise from the current draft.
+func (s *QuestionnaireService) ExportQuestionnaire(ctx context.Context, id primitive.ObjectID, version int) (*models.QuestionnaireDocument, error) {
+	questionnaire, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if version > 0 {
+		v, err := s.versionRepo.GetByQuestionnaireAndVersion(ctx, id, version)
+		if err != nil {
+			return nil, err
+		}
+		questionnaire = v.ToQuestionnaire(questionnaire)
+	}
+
+	return models.NewQuestionnaireDocument(questionnaire), nil
+}
+
+// ImportQuestionnaire creates a draft questionnaire from a document. The whole document is
+// validated before anything is written; with dryRun nothing is written at all.
+func (s *QuestionnaireService) ImportQuestionnaire(ctx context.Context, doc *models.QuestionnaireDocument, createdBy string, dryRun bool) (*models.Questionnaire, error) {
+	questionnaire, err := questionnaireFromDocument(doc, createdBy)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return questionnaire, nil
+	}
+
+	if err := s.repo.Create(ctx, questionnaire); err != nil {
+		return nil, fmt.Errorf("failed to import questionnaire: %w", err)
+	}
+
+	return questionnaire, nil
+}
+
+// ReplaceQuestionnaire replaces the title, description, sections, questions and score bands of a
+// draft with those of a document, so a definition kept elsewhere can be promoted onto an existing
+// questionnaire. The document is validated in full first; with dryRun nothing is written.
+func (s *QuestionnaireService) ReplaceQuestionnaire(ctx context.Context, id primitive.ObjectID, doc *models.QuestionnaireDocument, dryRun bool) (*models.Questionnaire, error) {
+	current, err := s.getEditable(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	imported, err := questionnaireFromDocument(doc, current.CreatedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	questionnaire := *current
+	questionnaire.Title = imported.Title
+	questionnaire.Description = imported.Description
+	questionnaire.Sections = imported.Sections
+	questionnaire.Questions = imported.Questions
+	questionnaire.ScoreBands = imported.ScoreBands
+	questionnaire.HasUnpublishedChanges = true
+	if dryRun {
+		return &questionnaire, nil
+	}
+
+	if err := s.repo.ReplaceContent(ctx, id, &questionnaire, current.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	return &questionnaire, nil
+}
+
+// questionnaireFromDocument builds the questionnaire a document describes and validates all of it,
+// with the same rules as the individual endpoints. Every problem found is reported, under field
+// paths such as "questions[2].options.max".
+func questionnaireFromDocument(doc *models.QuestionnaireDocument, createdBy string) (*models.Questionnaire, error) {
+	verrs := utils.NewValidationErrors()
+
+	if doc.FormatVersion < 0 || doc.FormatVersion > models.QuestionnaireDocumentVersion {
+		verrs.Add("format_version", fmt.Sprintf("unsupported version %d (current is %d)", doc.FormatVersion, models.QuestionnaireDocumentVersion))
+		return nil, verrs
+	}
+
+	if doc.Title == "" {
+		verrs.Add("title", "is required")
+	} else if len(doc.Title) < 5 || len(doc.Title) > 200 {
+		verrs.Add("title", "must be between 5 and 200 characters")
+	}
+
+	sectionKeys := make(map[string]bool, len(doc.Sections))
+	for i, section := range doc.Sections {
+		field := fmt.Sprintf("sections[%d]", i)
+		if section.Key == "" {
+			verrs.Add(field+".key", "is required")
+		} else if sectionKeys[section.Key] {
+			verrs.Add(field+".key", fmt.Sprintf("duplicate key %q", section.Key))
+		}
+		sectionKeys[section.Key] = true
+	}
+
+	// Conditions can only be checked when every question key and type resolves
+	resolved := true
+
+	questionKeys := make(map[string]bool, len(doc.Questions))
+	for i, question := range doc.Questions {
+		if question.Key == "" {
+			continue
+		}
+		if questionKeys[question.Key] {
+			verrs.Add(fmt.Sprintf("questions[%d].key", i), fmt.Sprintf("duplicate key %q", question.Key))
+			resolved = false
+		}
+		questionKeys[question.Key] = true
+	}
+
+	for i, question := range doc.Questions {
+		field := fmt.Sprintf("questions[%d]", i)
+		if question.Section != "" && !sectionKeys[question.Section] {
+			verrs.Add(field+".section", fmt.Sprintf("unknown section key %q", question.Section))
+		}
+		if err := utils.ValidateQuestionType(question.QuestionType); err != nil {
+			verrs.Add(field+".question_type", err.Error())
+			resolved = false
+		}
+		for j, c := range question.DisplayConditions {
+			if !questionKeys[c.Question] {
+				verrs.Add(fmt.Sprintf("%s.display_conditions[%d].question", field, j), fmt.Sprintf("unknown question key %q", c.Question))
+				resolved = false
+			}
+		}
+		for j, c := range question.SkipConditions {
+			if !questionKeys[c.Question] {
+				verrs.Add(fmt.Sprintf("%s.skip_conditions[%d].question", field, j), fmt.Sprintf("unknown question key %q", c.Question))
+				resolved = false
+			}
+		}
+	}
+
+	questionnaire := doc.ToQuestionnaire(createdBy)
+
+	for i := range questionnaire.Sections {
+		addFieldErrors(verrs, fmt.Sprintf("sections[%d]", i), validateSection(&questionnaire.Sections[i]))
+	}
+
+	orderIndexes := make(map[int]int, len(questionnaire.Questions))
+	for i := range questionnaire.Questions {
+		question := &questionnaire.Questions[i]
+		field := fmt.Sprintf("questions[%d]", i)
+		if models.IsValidQuestionType(question.QuestionType) {
+			addFieldErrors(verrs, field, validateQuestion(question))
+		}
+		if question.OrderIndex < 0 {
+			verrs.Add(field+".order_index", "must be zero or greater")
+		} else if other, taken := orderIndexes[question.OrderIndex]; taken {
+			verrs.Add(field+".order_index", fmt.Sprintf("already used by questions[%d]", other))
+		} else {
+			orderIndexes[question.OrderIndex] = i
+		}
+	}
+
+	// A cycle is reported once for the questionnaire rather than on each question in it
+	if resolved {
+		for i := range questionnaire.Questions {
+			question := &questionnaire.Questions[i]
+			addFieldErrors(verrs, fmt.Sprintf("questions[%d]", i), conditionFieldErrors(questionnaire.ValidateConditionReferences(question)))
+		}
+		if questionnaire.HasConditionCycle() {
+			verrs.Add("questions", models.ErrConditionCycle.Error())
+		}
+	}
+
+	if err := models.ValidateScoreBands(questionnaire.ScoreBands); err != nil {
+		addOptionsError(verrs, "score_bands", err)
+	}
+
+	if verrs.HasErrors() {
+		return nil, verrs
+	}
+	questionnaire.SortQuestions()
+	return questionnaire, nil
+}
+
+// addFieldErrors copies the field errors of a nested validation under prefix
+func addFieldErrors(verrs *utils.ValidationErrors, prefix string, err error) {
+	if err == nil {
+		return
+	}
+	var nested *utils.ValidationErrors
+	if errors.As(err, &nested) {
+		for _, f := range nested.Fields {
+			verrs.Add(prefix+"."+f.Field, f.Message)
+		}
+		return
+	}
+	verrs.Add(prefix, err.Error())
+}
@@ -2,22 +2,38 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"questionarie-service/models"
 	"questionarie-service/repository"
+	"questionarie-service/utils"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // QuestionnaireService handles business logic for questionnaires
 type QuestionnaireService struct {
-	repo *repository.QuestionnaireRepository
+	repo                     *repository.QuestionnaireRepository
+	versionRepo              *repository.QuestionnaireVersionRepository
+	companyQuestionnaireRepo *repository.CompanyQuestionnaireRepository
+	campaignRepo             *repository.CampaignRepository
+	transactor               *repository.Transactor
 }
 
 // NewQuestionnaireService creates a new QuestionnaireService
-func NewQuestionnaireService(repo *repository.QuestionnaireRepository) *QuestionnaireService {
+func NewQuestionnaireService(
+	repo *repository.QuestionnaireRepository,
+	versionRepo *repository.QuestionnaireVersionRepository,
+	companyQuestionnaireRepo *repository.CompanyQuestionnaireRepository,
+	campaignRepo *repository.CampaignRepository,
+	transactor *repository.Transactor,
+) *QuestionnaireService {
 	return &QuestionnaireService{
-		repo: repo,
+		repo:                     repo,
+		versionRepo:              versionRepo,
+		companyQuestionnaireRepo: companyQuestionnaireRepo,
+		campaignRepo:             campaignRepo,
+		transactor:               transactor,
 	}
 }
 
@@ -39,13 +55,56 @@ func (s *QuestionnaireService) CreateQuestionnaire(ctx context.Context, title, d
 	return questionnaire, nil
 }
 
+// CloneQuestionnaire creates a draft copy of a questionnaire with fresh question IDs.
+// When version is set the copy is taken from that published version, otherwise from the current draft.
+// An empty title defaults to the source title with a "(copy)" suffix.
+func (s *QuestionnaireService) CloneQuestionnaire(ctx context.Context, id primitive.ObjectID, title string, version int, createdBy string) (*models.Questionnaire, error) {
+	source, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if version > 0 {
+		v, err := s.versionRepo.GetByQuestionnaireAndVersion(ctx, id, version)
+		if err != nil {
+			return nil, err
+		}
+		source = v.ToQuestionnaire(source)
+	}
+
+	if title == "" {
+		title = source.Title + " (copy)"
+	}
+	if len(title) < 5 || len(title) > 200 {
+		verrs := utils.NewValidationErrors()
+		verrs.Add("title", "must be between 5 and 200 characters")
+		return nil, verrs
+	}
+
+	clone := source.Clone(title, createdBy, version)
+
+	if err := s.repo.Create(ctx, clone); err != nil {
+		return nil, fmt.Errorf("failed to clone questionnaire: %w", err)
+	}
+
+	return clone, nil
+}
+
+// GetQuestionnaireClones retrieves the questionnaires cloned from a questionnaire
+func (s *QuestionnaireService) GetQuestionnaireClones(ctx context.Context, id primitive.ObjectID) ([]*models.Questionnaire, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetBySourceID(ctx, id)
+}
+
 // GetQuestionnaireByID retrieves a questionnaire by ID
 func (s *QuestionnaireService) GetQuestionnaireByID(ctx context.Context, id primitive.ObjectID) (*models.Questionnaire, error) {
 	return s.repo.GetByID(ctx, id)
 }
 
-// GetAllQuestionnaires retrieves all questionnaires with pagination
-func (s *QuestionnaireService) GetAllQuestionnaires(ctx context.Context, page, pageSize int64, activeOnly bool) ([]*models.Questionnaire, error) {
+// GetAllQuestionnaires retrieves all questionnaires with pagination, optionally filtered by lifecycle status
+func (s *QuestionnaireService) GetAllQuestionnaires(ctx context.Context, page, pageSize int64, activeOnly bool, status models.QuestionnaireStatus) ([]*models.Questionnaire, error) {
 	if page <= 0 {
 		page = 1
 	}
@@ -56,6 +115,9 @@ func (s *QuestionnaireService) GetAllQuestionnaires(ctx context.Context, page, p
 		pageSize = 100
 	}
 
+	if status != "" {
+		return s.repo.GetAllByStatus(ctx, page, pageSize, status)
+	}
 	return s.repo.GetAll(ctx, page, pageSize, activeOnly)
 }
 
@@ -64,8 +126,9 @@ func (s *QuestionnaireService) GetQuestionnairesByCreator(ctx context.Context, c
 	return s.repo.GetByCreator(ctx, creatorID)
 }
 
-// UpdateQuestionnaire updates a questionnaire
-func (s *QuestionnaireService) UpdateQuestionnaire(ctx context.Context, id primitive.ObjectID, title, description string, isActive bool) error {
+// UpdateQuestionnaire updates a questionnaire's title and description.
+// Lifecycle status changes go through the explicit transition methods.
+func (s *QuestionnaireService) UpdateQuestionnaire(ctx context.Context, id primitive.ObjectID, title, description string) error {
 	questionnaire, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return err
@@ -77,54 +140,469 @@ func (s *QuestionnaireService) UpdateQuestionnaire(ctx context.Context, id primi
 	if description != "" {
 		questionnaire.Description = description
 	}
-	questionnaire.IsActive = isActive
 
 	return s.repo.Update(ctx, id, questionnaire)
 }
 
-// DeactivateQuestionnaire deactivates a questionnaire
-func (s *QuestionnaireService) DeactivateQuestionnaire(ctx context.Context, id primitive.ObjectID) error {
-	return s.repo.Deactivate(ctx, id)
+// ReviseQuestionnaire moves a published questionnaire back to draft so a new version can be prepared.
+// Company questionnaires already assigned keep running on their pinned version.
+func (s *QuestionnaireService) ReviseQuestionnaire(ctx context.Context, id primitive.ObjectID, changedBy string) (*models.Questionnaire, error) {
+	return s.transition(ctx, id, models.QuestionnaireStatusDraft, changedBy, func(q *models.Questionnaire) error {
+		if q.CurrentStatus() != models.QuestionnaireStatusPublished {
+			return fmt.Errorf("conflict: only published questionnaires can be revised")
+		}
+		return nil
+	})
 }
 
-// AddQuestion adds a question to a questionnaire
-func (s *QuestionnaireService) AddQuestion(ctx context.Context, questionnaireID primitive.ObjectID, question models.Question) error {
-	// Validate question
-	if question.QuestionText == "" {
-		return fmt.Errorf("question text is required")
+// RestoreQuestionnaire moves an archived questionnaire back to draft
+func (s *QuestionnaireService) RestoreQuestionnaire(ctx context.Context, id primitive.ObjectID, changedBy string) (*models.Questionnaire, error) {
+	return s.transition(ctx, id, models.QuestionnaireStatusDraft, changedBy, func(q *models.Questionnaire) error {
+		if q.CurrentStatus() != models.QuestionnaireStatusArchived {
+			return fmt.Errorf("conflict: only archived questionnaires can be restored")
+		}
+		return nil
+	})
+}
+
+// ArchiveQuestionnaire archives a questionnaire. While active company questionnaires or campaigns
+// exist archiving is refused, unless cascade is set, in which case the company questionnaires are
+// deactivated and the campaigns paused in the same transaction, after the status is written.
+func (s *QuestionnaireService) ArchiveQuestionnaire(ctx context.Context, id primitive.ObjectID, changedBy string, cascade bool) (*models.Questionnaire, error) {
+	var archived *models.Questionnaire
+	err := s.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		var active []*models.CompanyQuestionnaire
+		var err error
+		archived, err = s.transition(ctx, id, models.QuestionnaireStatusArchived, changedBy, func(q *models.Questionnaire) error {
+			cqs, err := s.companyQuestionnaireRepo.GetByQuestionnaireID(ctx, id)
+			if err != nil {
+				return err
+			}
+
+			for _, cq := range cqs {
+				if cq.IsActive || cq.PendingActivation {
+					active = append(active, cq)
+				}
+			}
+			if cascade {
+				return nil
+			}
+			if len(active) > 0 {
+				return fmt.Errorf("conflict: questionnaire has %d active company assignments (use cascade to deactivate them)", len(active))
+			}
+			campaigns, err := s.campaignRepo.GetActiveByQuestionnaireID(ctx, id)
+			if err != nil {
+				return err
+			}
+			if len(campaigns) > 0 {
+				return fmt.Errorf("conflict: questionnaire has %d active campaigns (use cascade to pause them)", len(campaigns))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if cascade {
+			if err := s.campaignRepo.PauseByQuestionnaireID(ctx, id); err != nil {
+				return err
+			}
+		}
+
+		for _, cq := range active {
+			if err := s.companyQuestionnaireRepo.Deactivate(ctx, cq.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return archived, nil
+}
+
+// DeactivateQuestionnaire archives a questionnaire without cascading to company assignments
+func (s *QuestionnaireService) DeactivateQuestionnaire(ctx context.Context, id primitive.ObjectID, changedBy string) error {
+	_, err := s.ArchiveQuestionnaire(ctx, id, changedBy, false)
+	return err
+}
+
+// transition validates and applies a lifecycle change, running check before the status is written
+func (s *QuestionnaireService) transition(
+	ctx context.Context,
+	id primitive.ObjectID,
+	to models.QuestionnaireStatus,
+	changedBy string,
+	check func(q *models.Questionnaire) error,
+) (*models.Questionnaire, error) {
+	questionnaire, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !questionnaire.CanTransitionTo(to) {
+		return nil, fmt.Errorf("conflict: cannot move questionnaire from %s to %s", questionnaire.CurrentStatus(), to)
+	}
+	if check != nil {
+		if err := check(questionnaire); err != nil {
+			return nil, err
+		}
+	}
+
+	change := questionnaire.NewStatusChange(to, changedBy)
+	if err := s.repo.UpdateStatus(ctx, id, change); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByID(ctx, id)
+}
+
+// getEditable retrieves a questionnaire and verifies that its questions may be changed
+func (s *QuestionnaireService) getEditable(ctx context.Context, id primitive.ObjectID) (*models.Questionnaire, error) {
+	questionnaire, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !questionnaire.IsEditable() {
+		return nil, fmt.Errorf("conflict: questionnaire is %s; revise it to a draft before changing questions", questionnaire.CurrentStatus())
+	}
+	return questionnaire, nil
+}
+
+// AddQuestion validates a question, normalises its options and adds it to a questionnaire
+func (s *QuestionnaireService) AddQuestion(ctx context.Context, questionnaireID primitive.ObjectID, question *models.Question) error {
+	if err := validateQuestion(question); err != nil {
+		return err
 	}
-	if len(question.QuestionText) < 5 {
-		return fmt.Errorf("question text must be at least 5 characters")
+	questionnaire, err := s.getEditable(ctx, questionnaireID)
+	if err != nil {
+		return err
 	}
 
-	// Validate question type
-	validTypes := map[models.QuestionType]bool{
-		models.QuestionTypeMultipleChoice: true,
-		models.QuestionTypeLikertScale:    true,
-		models.QuestionTypeFreeText:       true,
-		models.QuestionTypeYesNo:          true,
+	if err := validateQuestionSection(questionnaire, question); err != nil {
+		return err
+	}
+	if err := validateOrderIndex(questionnaire, question, ""); err != nil {
+		return err
 	}
-	if !validTypes[question.QuestionType] {
-		return fmt.Errorf("invalid question type")
+
+	// Validate conditions against the questionnaire as it will look after the change
+	questionnaire.AddQuestion(*question)
+	if err := validateConditions(questionnaire, question); err != nil {
+		return err
 	}
 
-	return s.repo.AddQuestion(ctx, questionnaireID, question)
+	return s.repo.AddQuestion(ctx, questionnaireID, *question)
 }
 
-// UpdateQuestion updates a specific question
-func (s *QuestionnaireService) UpdateQuestion(ctx context.Context, questionnaireID primitive.ObjectID, questionID string, question models.Question) error {
+// UpdateQuestion validates and updates a specific question
+func (s *QuestionnaireService) UpdateQuestion(ctx context.Context, questionnaireID primitive.ObjectID, questionID string, question *models.Question) error {
+	if err := validateQuestion(question); err != nil {
+		return err
+	}
+	questionnaire, err := s.getEditable(ctx, questionnaireID)
+	if err != nil {
+		return err
+	}
+
+	if err := validateQuestionSection(questionnaire, question); err != nil {
+		return err
+	}
+	if err := validateOrderIndex(questionnaire, question, questionID); err != nil {
+		return err
+	}
+	if !questionnaire.UpdateQuestion(questionID, *question) {
+		return fmt.Errorf("question not found")
+	}
+	if err := validateConditions(questionnaire, question); err != nil {
+		return err
+	}
+
+	return s.repo.UpdateQuestion(ctx, questionnaireID, questionID, *question)
+}
+
+// validateQuestionSection checks that a question placed in a section refers to an existing one
+func validateQuestionSection(questionnaire *models.Questionnaire, question *models.Question) error {
+	if question.SectionID == "" || questionnaire.GetSectionByID(question.SectionID) != nil {
+		return nil
+	}
+	verrs := utils.NewValidationErrors()
+	verrs.Add("section_id", "section does not exist in this questionnaire")
+	return verrs
+}
+
+// validateOrderIndex rejects an order index already taken by another question.
+// When updating, keeping the question's current index is always accepted.
+func validateOrderIndex(questionnaire *models.Questionnaire, question *models.Question, questionID string) error {
+	if current := questionnaire.GetQuestionByID(questionID); current != nil && current.OrderIndex == question.OrderIndex {
+		return nil
+	}
+	for _, q := range questionnaire.Questions {
+		if q.QuestionID != questionID && q.OrderIndex == question.OrderIndex {
+			verrs := utils.NewValidationErrors()
+			verrs.Add("order_index", fmt.Sprintf("already used by question %s; use the reorder endpoint to move questions", q.QuestionID))
+			return verrs
+		}
+	}
+	return nil
+}
+
+// validateConditions checks a question's display/skip conditions within its questionnaire
+func validateConditions(questionnaire *models.Questionnaire, question *models.Question) error {
+	return conditionFieldErrors(questionnaire.ValidateConditions(question))
+}
+
+// conditionFieldErrors reports a condition validation error as field errors
+func conditionFieldErrors(err error) error {
+	if err != nil {
+		verrs := utils.NewValidationErrors()
+		var optErr *models.OptionsError
+		if errors.As(err, &optErr) {
+			verrs.Add(optErr.Field, optErr.Message)
+		} else {
+			verrs.Add("conditions", err.Error())
+		}
+		return verrs
+	}
+	return nil
+}
+
+// validateQuestion checks text and type and rewrites options into their canonical shape
+func validateQuestion(question *models.Question) error {
+	verrs := utils.NewValidationErrors()
+
 	if question.QuestionText == "" {
-		return fmt.Errorf("question text is required")
+		verrs.Add("question_text", "is required")
+	} else if len(question.QuestionText) < 5 {
+		verrs.Add("question_text", "must be at least 5 characters")
+	}
+
+	if err := question.NormalizeOptions(); err != nil {
+		addOptionsError(verrs, "options", err)
+	} else {
+		if err := question.ValidateScoring(); err != nil {
+			addOptionsError(verrs, "scoring", err)
+		}
+		if err := question.ValidateDimensions(); err != nil {
+			addOptionsError(verrs, "dimensions", err)
+		}
 	}
 
-	return s.repo.UpdateQuestion(ctx, questionnaireID, questionID, question)
+	if verrs.HasErrors() {
+		return verrs
+	}
+	return nil
+}
+
+// addOptionsError records an options, scoring or dimensions error under its field, or under field when it has none
+func addOptionsError(verrs *utils.ValidationErrors, field string, err error) {
+	var optErr *models.OptionsError
+	if errors.As(err, &optErr) {
+		verrs.Add(optErr.Field, optErr.Message)
+		return
+	}
+	verrs.Add(field, err.Error())
+}
+
+// ReorderQuestions sets the order of all questions of a draft questionnaire in one atomic update.
+// questionIDs must list every question exactly once; OrderIndex becomes the position in the list.
+func (s *QuestionnaireService) ReorderQuestions(ctx context.Context, questionnaireID primitive.ObjectID, questionIDs []string) (*models.Questionnaire, error) {
+	questionnaire, err := s.getEditable(ctx, questionnaireID)
+	if err != nil {
+		return nil, err
+	}
+
+	expectedUpdatedAt := questionnaire.UpdatedAt
+	if err := questionnaire.ReorderQuestions(questionIDs); err != nil {
+		verrs := utils.NewValidationErrors()
+		verrs.Add("question_ids", err.Error())
+		return nil, verrs
+	}
+
+	if err := s.repo.ReorderQuestions(ctx, questionnaireID, questionnaire.Questions, expectedUpdatedAt); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByID(ctx, questionnaireID)
 }
 
 // RemoveQuestion removes a question from a questionnaire
 func (s *QuestionnaireService) RemoveQuestion(ctx context.Context, questionnaireID primitive.ObjectID, questionID string) error {
+	questionnaire, err := s.getEditable(ctx, questionnaireID)
+	if err != nil {
+		return err
+	}
+
+	// Refuse to orphan conditions that depend on this question
+	for _, q := range questionnaire.Questions {
+		if q.QuestionID != questionID && q.DependsOn(questionID) {
+			return fmt.Errorf("conflict: question is referenced by the conditions of question %s", q.QuestionID)
+		}
+	}
+
 	return s.repo.RemoveQuestion(ctx, questionnaireID, questionID)
 }
 
+// UpdateScoreBands replaces the score bands of a draft questionnaire and returns them ordered by score
+func (s *QuestionnaireService) UpdateScoreBands(ctx context.Context, questionnaireID primitive.ObjectID, bands []models.ScoreBand) ([]models.ScoreBand, error) {
+	if bands == nil {
+		bands = []models.ScoreBand{}
+	}
+	if err := models.ValidateScoreBands(bands); err != nil {
+		verrs := utils.NewValidationErrors()
+		addOptionsError(verrs, "score_bands", err)
+		return nil, verrs
+	}
+
+	if _, err := s.getEditable(ctx, questionnaireID); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateScoreBands(ctx, questionnaireID, bands); err != nil {
+		return nil, err
+	}
+	return bands, nil
+}
+
+// GetSections retrieves the sections of a questionnaire ordered by OrderIndex
+func (s *QuestionnaireService) GetSections(ctx context.Context, questionnaireID primitive.ObjectID) ([]models.Section, error) {
+	questionnaire, err := s.repo.GetByID(ctx, questionnaireID)
+	if err != nil {
+		return nil, err
+	}
+	return questionnaire.SortedSections(), nil
+}
+
+// AddSection validates and adds a section to a questionnaire
+func (s *QuestionnaireService) AddSection(ctx context.Context, questionnaireID primitive.ObjectID, section *models.Section) error {
+	if err := validateSection(section); err != nil {
+		return err
+	}
+	if _, err := s.getEditable(ctx, questionnaireID); err != nil {
+		return err
+	}
+
+	return s.repo.AddSection(ctx, questionnaireID, *section)
+}
+
+// UpdateSection validates and updates a specific section
+func (s *QuestionnaireService) UpdateSection(ctx context.Context, questionnaireID primitive.ObjectID, sectionID string, section *models.Section) error {
+	if err := validateSection(section); err != nil {
+		return err
+	}
+	questionnaire, err := s.getEditable(ctx, questionnaireID)
+	if err != nil {
+		return err
+	}
+	if questionnaire.GetSectionByID(sectionID) == nil {
+		return fmt.Errorf("section not found")
+	}
+
+	section.SectionID = sectionID
+	return s.repo.UpdateSection(ctx, questionnaireID, sectionID, *section)
+}
+
+// RemoveSection removes an empty section from a questionnaire
+func (s *QuestionnaireService) RemoveSection(ctx context.Context, questionnaireID primitive.ObjectID, sectionID string) error {
+	questionnaire, err := s.getEditable(ctx, questionnaireID)
+	if err != nil {
+		return err
+	}
+	if questionnaire.GetSectionByID(sectionID) == nil {
+		return fmt.Errorf("section not found")
+	}
+	if n := len(questionnaire.GetSectionQuestions(sectionID)); n > 0 {
+		return fmt.Errorf("conflict: section still contains %d questions; move or remove them first", n)
+	}
+
+	return s.repo.RemoveSection(ctx, questionnaireID, sectionID)
+}
+
+// validateSection checks the section's title and ordering
+func validateSection(section *models.Section) error {
+	verrs := utils.NewValidationErrors()
+
+	if section.Title == "" {
+		verrs.Add("title", "is required")
+	} else if len(section.Title) < 3 {
+		verrs.Add("title", "must be at least 3 characters")
+	}
+	if section.OrderIndex < 0 {
+		verrs.Add("order_index", "must be zero or greater")
+	}
+
+	if verrs.HasErrors() {
+		return verrs
+	}
+	return nil
+}
+
+// PublishQuestionnaire moves a draft to published, freezing its questions as a new immutable version.
+// A revised draft with no changes since the last version is republished without a new snapshot.
+// The snapshot and the status are written in one transaction.
+func (s *QuestionnaireService) PublishQuestionnaire(ctx context.Context, id primitive.ObjectID, publishedBy string) (*models.QuestionnaireVersion, error) {
+	var published *models.QuestionnaireVersion
+	err := s.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		published, err = s.publish(ctx, id, publishedBy)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return published, nil
+}
+
+// publish freezes the questions of a questionnaire as a new version and marks it published
+func (s *QuestionnaireService) publish(ctx context.Context, id primitive.ObjectID, publishedBy string) (*models.QuestionnaireVersion, error) {
+	questionnaire, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !questionnaire.CanTransitionTo(models.QuestionnaireStatusPublished) {
+		return nil, fmt.Errorf("conflict: cannot publish a %s questionnaire", questionnaire.CurrentStatus())
+	}
+	if len(questionnaire.Questions) == 0 {
+		return nil, fmt.Errorf("invalid questionnaire: at least one question is required to publish")
+	}
+
+	change := questionnaire.NewStatusChange(models.QuestionnaireStatusPublished, publishedBy)
+
+	if questionnaire.IsPublished() && !questionnaire.HasUnpublishedChanges {
+		if err := s.repo.UpdateStatus(ctx, id, change); err != nil {
+			return nil, err
+		}
+		return s.versionRepo.GetByQuestionnaireAndVersion(ctx, id, questionnaire.PublishedVersion)
+	}
+
+	version := models.NewQuestionnaireVersion(questionnaire, questionnaire.PublishedVersion+1, publishedBy)
+
+	if err := s.versionRepo.Create(ctx, version); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.MarkPublished(ctx, id, version.Version, change, questionnaire.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	return version, nil
+}
+
+// GetQuestionnaireVersions retrieves all published versions of a questionnaire
+func (s *QuestionnaireService) GetQuestionnaireVersions(ctx context.Context, id primitive.ObjectID) ([]*models.QuestionnaireVersion, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.versionRepo.GetByQuestionnaireID(ctx, id)
+}
+
+// GetQuestionnaireVersion retrieves a specific published version of a questionnaire
+func (s *QuestionnaireService) GetQuestionnaireVersion(ctx context.Context, id primitive.ObjectID, version int) (*models.QuestionnaireVersion, error) {
+	return s.versionRepo.GetByQuestionnaireAndVersion(ctx, id, version)
+}
+
 // GetQuestionnaireStats returns statistics about questionnaires
 func (s *QuestionnaireService) GetQuestionnaireStats(ctx context.Context) (map[string]interface{}, error) {
 	total, err := s.repo.Count(ctx, false)
@@ -151,8 +629,8 @@ func (s *QuestionnaireService) ValidateQuestionnaire(ctx context.Context, id pri
 		return err
 	}
 
-	if !questionnaire.IsActive {
-		return fmt.Errorf("questionnaire is not active")
+	if questionnaire.CurrentStatus() != models.QuestionnaireStatusPublished {
+		return fmt.Errorf("questionnaire is not published")
 	}
 
 	if len(questionnaire.Questions) == 0 {
@@ -161,3 +639,28 @@ func (s *QuestionnaireService) ValidateQuestionnaire(ctx context.Context, id pri
 
 	return nil
 }
+
+// resolveQuestionnaire returns the questionnaire as it was at the version a company
+// questionnaire runs on. Legacy assignments made before versioning use the live document.
+func resolveQuestionnaire(
+	ctx context.Context,
+	questionnaireRepo *repository.QuestionnaireRepository,
+	versionRepo *repository.QuestionnaireVersionRepository,
+	cq *models.CompanyQuestionnaire,
+) (*models.Questionnaire, error) {
+	questionnaire, err := questionnaireRepo.GetByID(ctx, cq.QuestionnaireID)
+	if err != nil {
+		return nil, err
+	}
+
+	if cq.QuestionnaireVersion == 0 {
+		return questionnaire, nil
+	}
+
+	version, err := versionRepo.GetByQuestionnaireAndVersion(ctx, cq.QuestionnaireID, cq.QuestionnaireVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return version.ToQuestionnaire(questionnaire), nil
+}
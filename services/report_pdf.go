@@ -0,0 +1,160 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"questionarie-service/templates"
+	"questionarie-service/utils"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// reportTemplates are the PDF report templates, parsed once at startup
+var reportTemplates = template.Must(template.New("reports").Funcs(template.FuncMap{
+	"clean":  cleanLayoutText,
+	"num":    func(v float64) string { return strconv.FormatFloat(v, 'f', 1, 64) },
+	"pct":    func(v float64) string { return strconv.FormatFloat(v, 'f', 1, 64) + "%" },
+	"counts": sortedCounts,
+	"ranks":  sortedRanks,
+}).ParseFS(templates.Reports, "reports/*.tmpl"))
+
+// PDFReport is a rendered PDF report
+type PDFReport struct {
+	FileName string
+	Content  []byte
+}
+
+// labelCount is a labelled count in a report template
+type labelCount struct {
+	Label string
+	Count int
+}
+
+// labelValue is a labelled value in a report template
+type labelValue struct {
+	Label string
+	Value float64
+}
+
+// GetCompletionReportPDF renders the completion metrics and answer summaries of a company
+// questionnaire as a PDF. Both parts apply the same access checks and cohort thresholds as their
+// JSON reports.
+func (s *ReportService) GetCompletionReportPDF(ctx context.Context, companyQuestionnaireID primitive.ObjectID, userID string, isSuperAdmin bool) (*PDFReport, error) {
+	metrics, err := s.GetCompletionMetrics(ctx, companyQuestionnaireID, userID, isSuperAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	answers, err := s.GetAnswerReport(ctx, companyQuestionnaireID, userID, isSuperAdmin, false)
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]interface{}{
+		"Metrics": metrics,
+		"Answers": answers,
+	}
+	content, err := renderPDFReport("completion.tmpl", data, metrics.CompanyName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PDFReport{
+		FileName: fmt.Sprintf("completion-%s-%s.pdf", companyQuestionnaireID.Hex(), metrics.PeriodStart),
+		Content:  content,
+	}, nil
+}
+
+// GetCompanyOverviewPDF renders the company overview as a PDF
+func (s *ReportService) GetCompanyOverviewPDF(ctx context.Context, companyID primitive.ObjectID, userID string, isSuperAdmin bool) (*PDFReport, error) {
+	overview, err := s.GetCompanyOverview(ctx, companyID, userID, isSuperAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]interface{}{
+		"Overview": overview,
+	}
+	content, err := renderPDFReport("overview.tmpl", data, overview.CompanyName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PDFReport{
+		FileName: fmt.Sprintf("overview-%s.pdf", companyID.Hex()),
+		Content:  content,
+	}, nil
+}
+
+// renderPDFReport executes a report template and lays out its output as a PDF
+func renderPDFReport(name string, data interface{}, companyName string) ([]byte, error) {
+	var layout bytes.Buffer
+	if err := reportTemplates.ExecuteTemplate(&layout, name, data); err != nil {
+		return nil, fmt.Errorf("failed to render report template %s: %w", name, err)
+	}
+
+	doc := utils.NewPDFDocumentFromLayout(layout.String())
+	doc.Footer = fmt.Sprintf("%s · Generado %s", cleanLayoutText(companyName), time.Now().UTC().Format("2006-01-02 15:04 UTC"))
+
+	var out bytes.Buffer
+	if _, err := doc.WriteTo(&out); err != nil {
+		return nil, fmt.Errorf("failed to write PDF: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+// cleanLayoutText keeps text on a single layout line and out of the table syntax
+func cleanLayoutText(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	return strings.ReplaceAll(text, "|", "/")
+}
+
+// sortedCounts orders counts by label, numerically when every label is a number
+func sortedCounts(counts map[string]int) []labelCount {
+	result := make([]labelCount, 0, len(counts))
+	labels := make([]string, 0, len(counts))
+	for label, count := range counts {
+		result = append(result, labelCount{Label: label, Count: count})
+		labels = append(labels, label)
+	}
+	less := labelLess(labels)
+	sort.Slice(result, func(i, j int) bool {
+		return less(result[i].Label, result[j].Label)
+	})
+	return result
+}
+
+// sortedRanks orders average ranks from best (lowest) to worst
+func sortedRanks(ranks map[string]float64) []labelValue {
+	result := make([]labelValue, 0, len(ranks))
+	for label, value := range ranks {
+		result = append(result, labelValue{Label: label, Value: value})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Value != result[j].Value {
+			return result[i].Value < result[j].Value
+		}
+		return result[i].Label < result[j].Label
+	})
+	return result
+}
+
+// labelLess compares labels numerically when all of them are numbers, otherwise alphabetically
+func labelLess(labels []string) func(a, b string) bool {
+	for _, label := range labels {
+		if _, err := strconv.ParseFloat(label, 64); err != nil {
+			return func(a, b string) bool { return a < b }
+		}
+	}
+	return func(a, b string) bool {
+		x, _ := strconv.ParseFloat(a, 64)
+		y, _ := strconv.ParseFloat(b, 64)
+		return x < y
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"questionarie-service/models"
 	"questionarie-service/repository"
+	"sort"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -15,7 +16,10 @@ type ReportService struct {
 	companyQuestionnaireRepo *repository.CompanyQuestionnaireRepository
 	userMetadataRepo         *repository.UserMetadataRepository
 	questionnaireRepo        *repository.QuestionnaireRepository
+	versionRepo              *repository.QuestionnaireVersionRepository
 	companyRepo              *repository.CompanyRepository
+	anonymousResponseRepo    *repository.AnonymousResponseRepository
+	campaignRepo             *repository.CampaignRepository
 }
 
 // NewReportService creates a new ReportService
@@ -24,14 +28,20 @@ func NewReportService(
 	companyQuestionnaireRepo *repository.CompanyQuestionnaireRepository,
 	userMetadataRepo *repository.UserMetadataRepository,
 	questionnaireRepo *repository.QuestionnaireRepository,
+	versionRepo *repository.QuestionnaireVersionRepository,
 	companyRepo *repository.CompanyRepository,
+	anonymousResponseRepo *repository.AnonymousResponseRepository,
+	campaignRepo *repository.CampaignRepository,
 ) *ReportService {
 	return &ReportService{
 		assignmentRepo:           assignmentRepo,
 		companyQuestionnaireRepo: companyQuestionnaireRepo,
 		userMetadataRepo:         userMetadataRepo,
 		questionnaireRepo:        questionnaireRepo,
+		versionRepo:              versionRepo,
 		companyRepo:              companyRepo,
+		anonymousResponseRepo:    anonymousResponseRepo,
+		campaignRepo:             campaignRepo,
 	}
 }
 
@@ -47,9 +57,11 @@ type CompletionMetrics struct {
 	Pending                int64                      `json:"pending"`
 	InProgress             int64                      `json:"in_progress"`
 	Completed              int64                      `json:"completed"`
+	Expired                int64                      `json:"expired"` // Not completed when the period ended
 	NotStarted             int64                      `json:"not_started"`
 	CompletionPercentage   float64                    `json:"completion_percentage"`
 	AvgTimeToComplete      float64                    `json:"average_time_to_complete_minutes"`
+	MinGroupSize           int                        `json:"min_group_size"` // Departments smaller than this are merged into "Other"
 	CompletionByDepartment []DepartmentCompletionStat `json:"completion_by_department,omitempty"`
 }
 
@@ -88,7 +100,7 @@ func (s *ReportService) GetCompletionMetrics(ctx context.Context, companyQuestio
 	}
 
 	// Get questionnaire info
-	questionnaire, err := s.questionnaireRepo.GetByID(ctx, cq.QuestionnaireID)
+	questionnaire, err := s.resolveQuestionnaire(ctx, cq)
 	if err != nil {
 		return nil, fmt.Errorf("questionnaire not found: %w", err)
 	}
@@ -139,13 +151,15 @@ func (s *ReportService) GetCompletionMetrics(ctx context.Context, companyQuestio
 		Pending:                pending,
 		InProgress:             inProgress,
 		Completed:              completed,
+		Expired:                stats["expired"],
 		NotStarted:             notStarted,
 		CompletionPercentage:   completionPercentage,
 		AvgTimeToComplete:      avgTime,
+		MinGroupSize:           models.ResolveMinGroupSize(company, cq),
 	}
 
 	// Get completion by department
-	deptStats, err := s.getCompletionByDepartment(ctx, cq.CompanyID, assignments)
+	deptStats, err := s.getCompletionByDepartment(ctx, cq.CompanyID, assignments, metrics.MinGroupSize)
 	if err == nil {
 		metrics.CompletionByDepartment = deptStats
 	}
@@ -153,8 +167,9 @@ func (s *ReportService) GetCompletionMetrics(ctx context.Context, companyQuestio
 	return metrics, nil
 }
 
-// getCompletionByDepartment calculates completion statistics by department
-func (s *ReportService) getCompletionByDepartment(ctx context.Context, companyID primitive.ObjectID, assignments []*models.UserQuestionnaireAssignment) ([]DepartmentCompletionStat, error) {
+// getCompletionByDepartment calculates completion statistics by department.
+// Departments with fewer than minGroupSize assignees are merged into "Other" or suppressed.
+func (s *ReportService) getCompletionByDepartment(ctx context.Context, companyID primitive.ObjectID, assignments []*models.UserQuestionnaireAssignment, minGroupSize int) ([]DepartmentCompletionStat, error) {
 	// Get all users in company
 	users, err := s.userMetadataRepo.GetByCompanyID(ctx, companyID)
 	if err != nil {
@@ -190,14 +205,36 @@ func (s *ReportService) getCompletionByDepartment(ctx context.Context, companyID
 		}
 	}
 
+	// Merge departments too small to be shown on their own
+	sizes := make(map[string]int, len(deptStats))
+	for dept, stat := range deptStats {
+		sizes[dept] = int(stat.Total)
+	}
+	merged := make(map[string]*DepartmentCompletionStat)
+	for dept, group := range models.GroupSegments(sizes, minGroupSize) {
+		if group == "" {
+			continue
+		}
+		if _, exists := merged[group]; !exists {
+			merged[group] = &DepartmentCompletionStat{
+				Department: group,
+			}
+		}
+		merged[group].Total += deptStats[dept].Total
+		merged[group].Completed += deptStats[dept].Completed
+	}
+
 	// Calculate percentages
-	result := make([]DepartmentCompletionStat, 0, len(deptStats))
-	for _, stat := range deptStats {
+	result := make([]DepartmentCompletionStat, 0, len(merged))
+	for _, stat := range merged {
 		if stat.Total > 0 {
 			stat.Percentage = (float64(stat.Completed) / float64(stat.Total)) * 100
 		}
 		result = append(result, *stat)
 	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Department < result[j].Department
+	})
 
 	return result, nil
 }
@@ -219,6 +256,8 @@ type CompanyOverview struct {
 type QuestionnaireBreakdownStat struct {
 	QuestionnaireID      string  `json:"questionnaire_id"`
 	QuestionnaireTitle   string  `json:"questionnaire_title"`
+	PeriodStart          string  `json:"period_start"`
+	PeriodEnd            string  `json:"period_end"`
 	Assigned             int     `json:"assigned"`
 	Completed            int     `json:"completed"`
 	CompletionPercentage float64 `json:"completion_percentage"`
@@ -269,7 +308,7 @@ func (s *ReportService) GetCompanyOverview(ctx context.Context, companyID primit
 	totalCompleted := 0
 
 	for _, cq := range companyQuestionnaires {
-		questionnaire, err := s.questionnaireRepo.GetByID(ctx, cq.QuestionnaireID)
+		questionnaire, err := s.resolveQuestionnaire(ctx, cq)
 		if err != nil {
 			continue
 		}
@@ -298,6 +337,8 @@ func (s *ReportService) GetCompanyOverview(ctx context.Context, companyID primit
 		breakdown = append(breakdown, QuestionnaireBreakdownStat{
 			QuestionnaireID:      cq.QuestionnaireID.Hex(),
 			QuestionnaireTitle:   questionnaire.Title,
+			PeriodStart:          cq.PeriodStart.Format("2006-01-02"),
+			PeriodEnd:            cq.PeriodEnd.Format("2006-01-02"),
 			Assigned:             assigned,
 			Completed:            completed,
 			CompletionPercentage: completionPct,
@@ -355,6 +396,7 @@ func (s *ReportService) GetEmployeeProgress(ctx context.Context, companyID primi
 		completed := 0
 		inProgress := 0
 		pending := 0
+		expired := 0
 
 		for _, a := range assignments {
 			switch a.Status {
@@ -364,6 +406,8 @@ func (s *ReportService) GetEmployeeProgress(ctx context.Context, companyID primi
 				inProgress++
 			case models.AssignmentStatusPending:
 				pending++
+			case models.AssignmentStatusExpired:
+				expired++
 			}
 		}
 
@@ -379,9 +423,739 @@ func (s *ReportService) GetEmployeeProgress(ctx context.Context, companyID primi
 			"completed":        completed,
 			"in_progress":      inProgress,
 			"pending":          pending,
+			"expired":          expired,
 			"completion_rate":  completionRate,
 		})
 	}
 
 	return progress, nil
 }
+
+// AnswerReport aggregates the answers of every question of a company questionnaire
+type AnswerReport struct {
+	CompanyQuestionnaireID primitive.ObjectID      `json:"company_questionnaire_id"`
+	QuestionnaireTitle     string                  `json:"questionnaire_title"`
+	IncludesIncomplete     bool                    `json:"includes_incomplete"`
+	Assignments            int64                   `json:"assignments"`    // Assignments whose answers are counted
+	MinGroupSize           int                     `json:"min_group_size"` // Questions with fewer answers are suppressed
+	Questions              []*models.AnswerSummary `json:"questions"`      // In questionnaire order
+}
+
+// GetAnswerReport aggregates the answers per question using each question type's aggregation.
+// Only completed assignments are counted unless includeIncomplete is set. Anonymous questionnaires
+// only report submitted answers, since drafts are still linked to their respondent. Questions
+// answered by fewer people than the minimum cohort size are suppressed.
+func (s *ReportService) GetAnswerReport(ctx context.Context, companyQuestionnaireID primitive.ObjectID, userID string, isSuperAdmin bool, includeIncomplete bool) (*AnswerReport, error) {
+	cq, err := s.companyQuestionnaireRepo.GetByID(ctx, companyQuestionnaireID)
+	if err != nil {
+		return nil, fmt.Errorf("company questionnaire not found: %w", err)
+	}
+
+	if err := s.checkCompanyAccess(ctx, cq.CompanyID, userID, isSuperAdmin); err != nil {
+		return nil, err
+	}
+
+	questionnaire, err := s.resolveQuestionnaire(ctx, cq)
+	if err != nil {
+		return nil, fmt.Errorf("questionnaire not found: %w", err)
+	}
+
+	minGroupSize, err := s.getMinGroupSize(ctx, cq)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := s.assignmentRepo.GetCompletionStats(ctx, companyQuestionnaireID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get completion stats: %w", err)
+	}
+
+	var grouped []repository.QuestionResponseValues
+	if cq.IsAnonymous {
+		includeIncomplete = false
+		grouped, err = s.anonymousResponseRepo.AggregateResponsesByQuestion(ctx, companyQuestionnaireID)
+	} else {
+		grouped, err = s.assignmentRepo.AggregateResponsesByQuestion(ctx, companyQuestionnaireID, includeIncomplete)
+	}
+	if err != nil {
+		return nil, err
+	}
+	valuesByQuestion := make(map[string][]interface{}, len(grouped))
+	for _, g := range grouped {
+		valuesByQuestion[g.QuestionID] = g.Values
+	}
+
+	report := &AnswerReport{
+		CompanyQuestionnaireID: companyQuestionnaireID,
+		QuestionnaireTitle:     questionnaire.Title,
+		IncludesIncomplete:     includeIncomplete,
+		Assignments:            stats[string(models.AssignmentStatusCompleted)],
+		MinGroupSize:           minGroupSize,
+		Questions:              make([]*models.AnswerSummary, 0, len(questionnaire.Questions)),
+	}
+	if includeIncomplete {
+		report.Assignments = 0
+		for _, count := range stats {
+			report.Assignments += count
+		}
+	}
+
+	for i := range questionnaire.Questions {
+		question := &questionnaire.Questions[i]
+		summary := question.Summarize(valuesByQuestion[question.QuestionID])
+		summary.SuppressBelow(minGroupSize)
+		report.Questions = append(report.Questions, summary)
+	}
+
+	return report, nil
+}
+
+// NPSReport is the Net Promoter Score of every NPS question of a company questionnaire
+type NPSReport struct {
+	CompanyQuestionnaireID primitive.ObjectID  `json:"company_questionnaire_id"`
+	QuestionnaireTitle     string              `json:"questionnaire_title"`
+	CompletedAssignments   int                 `json:"completed_assignments"`
+	MinGroupSize           int                 `json:"min_group_size"` // Smaller segments are merged into "Other"
+	Questions              []NPSQuestionReport `json:"questions"`
+}
+
+// NPSQuestionReport is the NPS of one question overall and per segment
+type NPSQuestionReport struct {
+	QuestionID   string           `json:"question_id"`
+	QuestionText string           `json:"question_text"`
+	Suppressed   bool             `json:"suppressed,omitempty"` // Too few answers to report
+	Overall      models.NPSResult `json:"overall"`
+	ByDepartment []NPSSegment     `json:"by_department"`
+	BySupervisor []NPSSegment     `json:"by_supervisor"` // Keyed by the supervisor's user ID (team)
+}
+
+// NPSSegment is the NPS of a group of respondents
+type NPSSegment struct {
+	Segment string `json:"segment"`
+	models.NPSResult
+}
+
+// GetNPSReport computes promoters, passives, detractors and the NPS of each NPS question
+// over completed assignments, overall, per department and per supervisor team.
+// Segments below the minimum cohort size are merged into "Other" or suppressed.
+func (s *ReportService) GetNPSReport(ctx context.Context, companyQuestionnaireID primitive.ObjectID, userID string, isSuperAdmin bool) (*NPSReport, error) {
+	cq, err := s.companyQuestionnaireRepo.GetByID(ctx, companyQuestionnaireID)
+	if err != nil {
+		return nil, fmt.Errorf("company questionnaire not found: %w", err)
+	}
+
+	if err := s.checkCompanyAccess(ctx, cq.CompanyID, userID, isSuperAdmin); err != nil {
+		return nil, err
+	}
+
+	questionnaire, err := s.resolveQuestionnaire(ctx, cq)
+	if err != nil {
+		return nil, fmt.Errorf("questionnaire not found: %w", err)
+	}
+
+	minGroupSize, err := s.getMinGroupSize(ctx, cq)
+	if err != nil {
+		return nil, err
+	}
+
+	respondents, err := s.getCompletedAnswers(ctx, cq)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &NPSReport{
+		CompanyQuestionnaireID: companyQuestionnaireID,
+		QuestionnaireTitle:     questionnaire.Title,
+		CompletedAssignments:   len(respondents),
+		MinGroupSize:           minGroupSize,
+		Questions:              []NPSQuestionReport{},
+	}
+
+	for _, question := range questionnaire.Questions {
+		if question.QuestionType != models.QuestionTypeNPS {
+			continue
+		}
+
+		overall := models.NPSResult{}
+		byDepartment := make(map[string]*models.NPSResult)
+		bySupervisor := make(map[string]*models.NPSResult)
+
+		for _, respondent := range respondents {
+			score, ok := models.ToInt(respondent.value(question.QuestionID))
+			if !ok {
+				continue
+			}
+
+			overall.Add(score)
+			addNPSScore(byDepartment, respondent.department, score)
+			addNPSScore(bySupervisor, respondent.supervisor, score)
+		}
+
+		if overall.Responses < minGroupSize {
+			report.Questions = append(report.Questions, NPSQuestionReport{
+				QuestionID:   question.QuestionID,
+				QuestionText: question.QuestionText,
+				Suppressed:   true,
+				ByDepartment: []NPSSegment{},
+				BySupervisor: []NPSSegment{},
+			})
+			continue
+		}
+
+		report.Questions = append(report.Questions, NPSQuestionReport{
+			QuestionID:   question.QuestionID,
+			QuestionText: question.QuestionText,
+			Overall:      overall,
+			ByDepartment: npsSegments(byDepartment, minGroupSize),
+			BySupervisor: npsSegments(bySupervisor, minGroupSize),
+		})
+	}
+
+	return report, nil
+}
+
+// ScoreReport is the distribution of the scores of a company questionnaire, for the whole company
+// and per department
+type ScoreReport struct {
+	CompanyQuestionnaireID primitive.ObjectID  `json:"company_questionnaire_id"`
+	QuestionnaireTitle     string              `json:"questionnaire_title"`
+	PeriodStart            string              `json:"period_start"`
+	PeriodEnd              string              `json:"period_end"`
+	MinGroupSize           int                 `json:"min_group_size"` // Smaller departments are merged into "Other"
+	ScoreBands             []models.ScoreBand  `json:"score_bands"`
+	Overall                ScoreDistribution   `json:"overall"`
+	ByDepartment           []ScoreDistribution `json:"by_department"`
+}
+
+// ScoreDistribution summarises the scores of a group of respondents
+type ScoreDistribution struct {
+	Segment    string               `json:"segment,omitempty"`
+	Count      int                  `json:"count"`
+	Suppressed bool                 `json:"suppressed,omitempty"` // Too few scores to report
+	Stats      *models.NumericStats `json:"stats,omitempty"`
+	Bands      []ScoreBandCount     `json:"bands,omitempty"` // In score band order
+}
+
+// ScoreBandCount is the number of scores that fall within a score band
+type ScoreBandCount struct {
+	Label      string  `json:"label"`
+	Count      int     `json:"count"`
+	Percentage float64 `json:"percentage"`
+}
+
+// GetScoreReport computes the score distribution of a scored company questionnaire over its
+// completed assignments. Departments below the minimum cohort size are merged into "Other" or suppressed.
+func (s *ReportService) GetScoreReport(ctx context.Context, companyQuestionnaireID primitive.ObjectID, userID string, isSuperAdmin bool) (*ScoreReport, error) {
+	cq, err := s.companyQuestionnaireRepo.GetByID(ctx, companyQuestionnaireID)
+	if err != nil {
+		return nil, fmt.Errorf("company questionnaire not found: %w", err)
+	}
+
+	if err := s.checkCompanyAccess(ctx, cq.CompanyID, userID, isSuperAdmin); err != nil {
+		return nil, err
+	}
+
+	company, err := s.companyRepo.GetByID(ctx, cq.CompanyID)
+	if err != nil {
+		return nil, fmt.Errorf("company not found: %w", err)
+	}
+
+	questionnaire, err := s.resolveQuestionnaire(ctx, cq)
+	if err != nil {
+		return nil, fmt.Errorf("questionnaire not found: %w", err)
+	}
+	if !questionnaire.HasScoring() {
+		return nil, fmt.Errorf("invalid request: questionnaire has no scoring")
+	}
+
+	return s.buildScoreReport(ctx, cq, questionnaire, models.ResolveMinGroupSize(company, cq))
+}
+
+// GetCompanyScoreReports computes the score distribution of every scored questionnaire assigned to a
+// company. Scores of different questionnaires are not comparable, so each gets its own report.
+func (s *ReportService) GetCompanyScoreReports(ctx context.Context, companyID primitive.ObjectID, userID string, isSuperAdmin bool) ([]*ScoreReport, error) {
+	if err := s.checkCompanyAccess(ctx, companyID, userID, isSuperAdmin); err != nil {
+		return nil, err
+	}
+
+	company, err := s.companyRepo.GetByID(ctx, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("company not found: %w", err)
+	}
+
+	companyQuestionnaires, err := s.companyQuestionnaireRepo.GetByCompanyID(ctx, companyID, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get company questionnaires: %w", err)
+	}
+
+	reports := []*ScoreReport{}
+	for _, cq := range companyQuestionnaires {
+		questionnaire, err := s.resolveQuestionnaire(ctx, cq)
+		if err != nil || !questionnaire.HasScoring() {
+			continue
+		}
+
+		report, err := s.buildScoreReport(ctx, cq, questionnaire, models.ResolveMinGroupSize(company, cq))
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+// buildScoreReport distributes the scores of the completed assignments of a company questionnaire
+func (s *ReportService) buildScoreReport(ctx context.Context, cq *models.CompanyQuestionnaire, questionnaire *models.Questionnaire, minGroupSize int) (*ScoreReport, error) {
+	respondents, err := s.getCompletedAnswers(ctx, cq)
+	if err != nil {
+		return nil, err
+	}
+
+	all := []float64{}
+	byDepartment := make(map[string][]float64)
+	for _, respondent := range respondents {
+		if respondent.score == nil {
+			continue
+		}
+		all = append(all, respondent.score.Total)
+		byDepartment[respondent.department] = append(byDepartment[respondent.department], respondent.score.Total)
+	}
+
+	report := &ScoreReport{
+		CompanyQuestionnaireID: cq.ID,
+		QuestionnaireTitle:     questionnaire.Title,
+		PeriodStart:            cq.PeriodStart.Format("2006-01-02"),
+		PeriodEnd:              cq.PeriodEnd.Format("2006-01-02"),
+		MinGroupSize:           minGroupSize,
+		ScoreBands:             questionnaire.ScoreBands,
+		Overall:                newScoreDistribution("", all, questionnaire, minGroupSize),
+		ByDepartment:           []ScoreDistribution{},
+	}
+	if report.ScoreBands == nil {
+		report.ScoreBands = []models.ScoreBand{}
+	}
+	if report.Overall.Suppressed {
+		return report, nil
+	}
+
+	sizes := make(map[string]int, len(byDepartment))
+	for department, scores := range byDepartment {
+		sizes[department] = len(scores)
+	}
+	merged := make(map[string][]float64)
+	for department, group := range models.GroupSegments(sizes, minGroupSize) {
+		if group != "" {
+			merged[group] = append(merged[group], byDepartment[department]...)
+		}
+	}
+	for department, scores := range merged {
+		report.ByDepartment = append(report.ByDepartment, newScoreDistribution(department, scores, questionnaire, minGroupSize))
+	}
+	sort.Slice(report.ByDepartment, func(i, j int) bool {
+		return report.ByDepartment[i].Segment < report.ByDepartment[j].Segment
+	})
+
+	return report, nil
+}
+
+// newScoreDistribution summarises scores and counts them per score band, or suppresses them
+// when there are fewer than minGroupSize
+func newScoreDistribution(segment string, scores []float64, questionnaire *models.Questionnaire, minGroupSize int) ScoreDistribution {
+	distribution := ScoreDistribution{Segment: segment, Count: len(scores)}
+	if len(scores) < minGroupSize {
+		distribution.Suppressed = true
+		return distribution
+	}
+
+	distribution.Stats = models.NewNumericStats(scores)
+
+	if len(questionnaire.ScoreBands) > 0 {
+		counts := make(map[string]int, len(questionnaire.ScoreBands))
+		for _, score := range scores {
+			counts[questionnaire.FindScoreBand(score)]++
+		}
+		for _, band := range questionnaire.ScoreBands {
+			bandCount := ScoreBandCount{Label: band.Label, Count: counts[band.Label]}
+			if len(scores) > 0 {
+				bandCount.Percentage = float64(bandCount.Count) / float64(len(scores)) * 100
+			}
+			distribution.Bands = append(distribution.Bands, bandCount)
+		}
+	}
+
+	return distribution
+}
+
+// DimensionReport averages the dimension scores of a company questionnaire for the whole company,
+// per department and per supervisor team
+type DimensionReport struct {
+	CompanyQuestionnaireID primitive.ObjectID `json:"company_questionnaire_id"`
+	QuestionnaireTitle     string             `json:"questionnaire_title"`
+	PeriodStart            string             `json:"period_start"`
+	PeriodEnd              string             `json:"period_end"`
+	MinGroupSize           int                `json:"min_group_size"` // Smaller segments are merged into "Other"
+	Dimensions             []string           `json:"dimensions"`
+	Overall                DimensionSegment   `json:"overall"`
+	ByDepartment           []DimensionSegment `json:"by_department"`
+	BySupervisor           []DimensionSegment `json:"by_supervisor"` // Segment is the supervisor's user ID
+}
+
+// DimensionSegment holds the dimension averages of a group of respondents
+type DimensionSegment struct {
+	Segment    string             `json:"segment,omitempty"`
+	Count      int                `json:"count"`
+	Suppressed bool               `json:"suppressed,omitempty"` // Too few respondents to report
+	Dimensions []DimensionAverage `json:"dimensions,omitempty"` // In dimension name order
+}
+
+// DimensionAverage summarises the mean item score of one dimension over a group of respondents
+type DimensionAverage struct {
+	Dimension  string               `json:"dimension"`
+	Count      int                  `json:"count"`                // Respondents who answered at least one question of the dimension
+	Suppressed bool                 `json:"suppressed,omitempty"` // Too few respondents answered the dimension
+	Stats      *models.NumericStats `json:"stats,omitempty"`
+}
+
+// DimensionTrend follows the dimension averages of one questionnaire across the periods it ran in a company
+type DimensionTrend struct {
+	CompanyID       primitive.ObjectID `json:"company_id"`
+	QuestionnaireID primitive.ObjectID `json:"questionnaire_id"`
+	Dimensions      []string           `json:"dimensions"`
+	Periods         []*DimensionReport `json:"periods"` // Ordered by period start
+}
+
+// GetDimensionReport computes the dimension averages of a company questionnaire over its completed
+// assignments. Segments below the minimum cohort size are merged into "Other" or suppressed.
+func (s *ReportService) GetDimensionReport(ctx context.Context, companyQuestionnaireID primitive.ObjectID, userID string, isSuperAdmin bool) (*DimensionReport, error) {
+	cq, err := s.companyQuestionnaireRepo.GetByID(ctx, companyQuestionnaireID)
+	if err != nil {
+		return nil, fmt.Errorf("company questionnaire not found: %w", err)
+	}
+
+	if err := s.checkCompanyAccess(ctx, cq.CompanyID, userID, isSuperAdmin); err != nil {
+		return nil, err
+	}
+
+	company, err := s.companyRepo.GetByID(ctx, cq.CompanyID)
+	if err != nil {
+		return nil, fmt.Errorf("company not found: %w", err)
+	}
+
+	questionnaire, err := s.resolveQuestionnaire(ctx, cq)
+	if err != nil {
+		return nil, fmt.Errorf("questionnaire not found: %w", err)
+	}
+	if len(questionnaire.DimensionNames()) == 0 {
+		return nil, fmt.Errorf("invalid request: questionnaire has no dimensions")
+	}
+
+	return s.buildDimensionReport(ctx, cq, questionnaire, models.ResolveMinGroupSize(company, cq))
+}
+
+// GetDimensionTrend computes the dimension report of every period in which a questionnaire was
+// assigned to a company, so changes between periods can be followed
+func (s *ReportService) GetDimensionTrend(ctx context.Context, companyID, questionnaireID primitive.ObjectID, userID string, isSuperAdmin bool) (*DimensionTrend, error) {
+	if err := s.checkCompanyAccess(ctx, companyID, userID, isSuperAdmin); err != nil {
+		return nil, err
+	}
+
+	company, err := s.companyRepo.GetByID(ctx, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("company not found: %w", err)
+	}
+
+	companyQuestionnaires, err := s.companyQuestionnaireRepo.GetByCompanyID(ctx, companyID, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get company questionnaires: %w", err)
+	}
+
+	trend := &DimensionTrend{
+		CompanyID:       companyID,
+		QuestionnaireID: questionnaireID,
+		Dimensions:      []string{},
+		Periods:         []*DimensionReport{},
+	}
+	seen := map[string]bool{}
+	for _, cq := range companyQuestionnaires {
+		if cq.QuestionnaireID != questionnaireID {
+			continue
+		}
+		questionnaire, err := s.resolveQuestionnaire(ctx, cq)
+		if err != nil || len(questionnaire.DimensionNames()) == 0 {
+			continue
+		}
+
+		report, err := s.buildDimensionReport(ctx, cq, questionnaire, models.ResolveMinGroupSize(company, cq))
+		if err != nil {
+			return nil, err
+		}
+		trend.Periods = append(trend.Periods, report)
+		for _, name := range report.Dimensions {
+			if !seen[name] {
+				seen[name] = true
+				trend.Dimensions = append(trend.Dimensions, name)
+			}
+		}
+	}
+
+	sort.Strings(trend.Dimensions)
+	sort.SliceStable(trend.Periods, func(i, j int) bool {
+		return trend.Periods[i].PeriodStart < trend.Periods[j].PeriodStart
+	})
+	return trend, nil
+}
+
+// buildDimensionReport averages the dimension scores of the completed assignments of a company questionnaire
+func (s *ReportService) buildDimensionReport(ctx context.Context, cq *models.CompanyQuestionnaire, questionnaire *models.Questionnaire, minGroupSize int) (*DimensionReport, error) {
+	respondents, err := s.getCompletedAnswers(ctx, cq)
+	if err != nil {
+		return nil, err
+	}
+
+	dimensions := questionnaire.DimensionNames()
+	all := [][]models.DimensionScore{}
+	byDepartment := make(map[string][][]models.DimensionScore)
+	bySupervisor := make(map[string][][]models.DimensionScore)
+	for _, respondent := range respondents {
+		scores := respondentDimensions(respondent, questionnaire)
+		if len(scores) == 0 {
+			continue
+		}
+		all = append(all, scores)
+		byDepartment[respondent.department] = append(byDepartment[respondent.department], scores)
+		bySupervisor[respondent.supervisor] = append(bySupervisor[respondent.supervisor], scores)
+	}
+
+	report := &DimensionReport{
+		CompanyQuestionnaireID: cq.ID,
+		QuestionnaireTitle:     questionnaire.Title,
+		PeriodStart:            cq.PeriodStart.Format("2006-01-02"),
+		PeriodEnd:              cq.PeriodEnd.Format("2006-01-02"),
+		MinGroupSize:           minGroupSize,
+		Dimensions:             dimensions,
+		Overall:                newDimensionSegment("", all, dimensions, minGroupSize),
+		ByDepartment:           []DimensionSegment{},
+		BySupervisor:           []DimensionSegment{},
+	}
+	if report.Overall.Suppressed {
+		return report, nil
+	}
+
+	report.ByDepartment = dimensionSegments(byDepartment, dimensions, minGroupSize)
+	report.BySupervisor = dimensionSegments(bySupervisor, dimensions, minGroupSize)
+	return report, nil
+}
+
+// respondentDimensions returns the dimension scores of a respondent. Submissions scored before the
+// questionnaire had dimensions are scored from their answers.
+func respondentDimensions(respondent respondentAnswers, questionnaire *models.Questionnaire) []models.DimensionScore {
+	if respondent.score != nil && len(respondent.score.Dimensions) > 0 {
+		return respondent.score.Dimensions
+	}
+	if score := questionnaire.ComputeScore(respondent.responses); score != nil {
+		return score.Dimensions
+	}
+	return nil
+}
+
+// dimensionSegments averages dimension scores per segment, ordered by segment name.
+// Segments with fewer than minGroupSize respondents are merged into "Other" or suppressed.
+func dimensionSegments(segments map[string][][]models.DimensionScore, dimensions []string, minGroupSize int) []DimensionSegment {
+	sizes := make(map[string]int, len(segments))
+	for name, scores := range segments {
+		sizes[name] = len(scores)
+	}
+
+	merged := make(map[string][][]models.DimensionScore)
+	for name, group := range models.GroupSegments(sizes, minGroupSize) {
+		if group != "" {
+			merged[group] = append(merged[group], segments[name]...)
+		}
+	}
+
+	result := make([]DimensionSegment, 0, len(merged))
+	for name, scores := range merged {
+		result = append(result, newDimensionSegment(name, scores, dimensions, minGroupSize))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Segment < result[j].Segment
+	})
+	return result
+}
+
+// newDimensionSegment summarises the dimension means of a group of respondents, or suppresses them
+// when there are fewer than minGroupSize respondents
+func newDimensionSegment(segment string, scores [][]models.DimensionScore, dimensions []string, minGroupSize int) DimensionSegment {
+	result := DimensionSegment{Segment: segment, Count: len(scores)}
+	if len(scores) < minGroupSize {
+		result.Suppressed = true
+		return result
+	}
+
+	means := make(map[string][]float64, len(dimensions))
+	for _, respondent := range scores {
+		for _, score := range respondent {
+			means[score.Dimension] = append(means[score.Dimension], score.Mean)
+		}
+	}
+	for _, dimension := range dimensions {
+		average := DimensionAverage{Dimension: dimension, Count: len(means[dimension])}
+		if average.Count < minGroupSize {
+			average.Suppressed = true
+		} else {
+			average.Stats = models.NewNumericStats(means[dimension])
+		}
+		result.Dimensions = append(result.Dimensions, average)
+	}
+	return result
+}
+
+// respondentAnswers are the answers of one completed assignment with the segments of its respondent
+type respondentAnswers struct {
+	department string
+	supervisor string
+	responses  []models.Response
+	score      *models.AssignmentScore
+}
+
+// value returns the answer given to a question, or nil when it was not answered
+func (a respondentAnswers) value(questionID string) interface{} {
+	for _, response := range a.responses {
+		if response.QuestionID == questionID {
+			return response.GetValue()
+		}
+	}
+	return nil
+}
+
+// getCompletedAnswers collects the answers of every completed assignment of a company questionnaire.
+// Anonymous questionnaires are read from the anonymous store with the segments recorded at submit
+// time; otherwise segments come from the respondent's current metadata. Missing segments are "Unassigned".
+func (s *ReportService) getCompletedAnswers(ctx context.Context, cq *models.CompanyQuestionnaire) ([]respondentAnswers, error) {
+	var respondents []respondentAnswers
+
+	if cq.IsAnonymous {
+		sets, err := s.anonymousResponseRepo.GetByCompanyQuestionnaireID(ctx, cq.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, set := range sets {
+			respondents = append(respondents, respondentAnswers{
+				department: segmentName(set.Department),
+				supervisor: segmentName(set.SupervisorID),
+				responses:  set.Responses,
+				score:      set.Score,
+			})
+		}
+		return respondents, nil
+	}
+
+	assignments, err := s.assignmentRepo.GetByCompanyQuestionnaireID(ctx, cq.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assignments: %w", err)
+	}
+
+	users, err := s.userMetadataRepo.GetByCompanyID(ctx, cq.CompanyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get employees: %w", err)
+	}
+	usersByID := make(map[string]*models.UserMetadata, len(users))
+	for _, user := range users {
+		usersByID[user.ID] = user
+	}
+
+	for _, assignment := range assignments {
+		if assignment.Status != models.AssignmentStatusCompleted {
+			continue
+		}
+		respondent := respondentAnswers{
+			department: segmentName(""),
+			supervisor: segmentName(""),
+			responses:  assignment.Responses,
+			score:      assignment.Score,
+		}
+		if user, ok := usersByID[assignment.UserID]; ok {
+			respondent.department = segmentName(user.Department)
+			respondent.supervisor = segmentName(user.SupervisorID)
+		}
+		respondents = append(respondents, respondent)
+	}
+
+	return respondents, nil
+}
+
+// segmentName returns the report segment for a department or supervisor
+func segmentName(name string) string {
+	if name == "" {
+		return "Unassigned"
+	}
+	return name
+}
+
+func addNPSScore(segments map[string]*models.NPSResult, segment string, score int) {
+	if _, exists := segments[segment]; !exists {
+		segments[segment] = &models.NPSResult{}
+	}
+	segments[segment].Add(score)
+}
+
+// npsSegments flattens per-segment results, ordered by segment name.
+// Segments with fewer than minGroupSize responses are merged into "Other" or suppressed.
+func npsSegments(segments map[string]*models.NPSResult, minGroupSize int) []NPSSegment {
+	sizes := make(map[string]int, len(segments))
+	for name, nps := range segments {
+		sizes[name] = nps.Responses
+	}
+
+	merged := make(map[string]*models.NPSResult)
+	for name, group := range models.GroupSegments(sizes, minGroupSize) {
+		if group == "" {
+			continue
+		}
+		if _, exists := merged[group]; !exists {
+			merged[group] = &models.NPSResult{}
+		}
+		merged[group].Merge(*segments[name])
+	}
+
+	result := make([]NPSSegment, 0, len(merged))
+	for name, nps := range merged {
+		result = append(result, NPSSegment{Segment: name, NPSResult: *nps})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Segment < result[j].Segment
+	})
+	return result
+}
+
+// getMinGroupSize returns the minimum cohort size that applies to a company questionnaire's reports
+func (s *ReportService) getMinGroupSize(ctx context.Context, cq *models.CompanyQuestionnaire) (int, error) {
+	company, err := s.companyRepo.GetByID(ctx, cq.CompanyID)
+	if err != nil {
+		return 0, fmt.Errorf("company not found: %w", err)
+	}
+	return models.ResolveMinGroupSize(company, cq), nil
+}
+
+// checkCompanyAccess verifies that a non super admin belongs to the company whose reports are requested
+func (s *ReportService) checkCompanyAccess(ctx context.Context, companyID primitive.ObjectID, userID string, isSuperAdmin bool) error {
+	if isSuperAdmin {
+		return nil
+	}
+
+	userMeta, err := s.userMetadataRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("user metadata not found: %w", err)
+	}
+
+	if companyID != userMeta.CompanyID {
+		return fmt.Errorf("unauthorized: cannot access reports from other companies")
+	}
+	return nil
+}
+
+// resolveQuestionnaire returns the questionnaire version a company questionnaire runs on
+func (s *ReportService) resolveQuestionnaire(ctx context.Context, cq *models.CompanyQuestionnaire) (*models.Questionnaire, error) {
+	return resolveQuestionnaire(ctx, s.questionnaireRepo, s.versionRepo, cq)
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"questionarie-service/models"
+	"questionarie-service/utils"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ResponseExport streams the raw responses of a company questionnaire as a table with one row per
+// assignment, or per anonymous response set when the questionnaire is anonymous
+type ResponseExport struct {
+	service     *ReportService
+	cq          *models.CompanyQuestionnaire
+	questions   []models.Question
+	users       map[string]*models.UserMetadata
+	departments map[string]string // Anonymous only: the segment each department is reported under, "" when suppressed
+}
+
+// NewResponseExport prepares the response export of a company questionnaire. Access and the
+// questionnaire are checked here, before anything is written, so failures can still be reported.
+func (s *ReportService) NewResponseExport(ctx context.Context, companyQuestionnaireID primitive.ObjectID, userID string, isSuperAdmin bool) (*ResponseExport, error) {
+	cq, err := s.companyQuestionnaireRepo.GetByID(ctx, companyQuestionnaireID)
+	if err != nil {
+		return nil, fmt.Errorf("company questionnaire not found: %w", err)
+	}
+
+	if err := s.checkCompanyAccess(ctx, cq.CompanyID, userID, isSuperAdmin); err != nil {
+		return nil, err
+	}
+
+	questionnaire, err := s.resolveQuestionnaire(ctx, cq)
+	if err != nil {
+		return nil, fmt.Errorf("questionnaire not found: %w", err)
+	}
+	questions := make([]models.Question, len(questionnaire.Questions))
+	copy(questions, questionnaire.Questions)
+	models.SortQuestions(questions)
+
+	export := &ResponseExport{service: s, cq: cq, questions: questions}
+	if cq.IsAnonymous {
+		// Departments with fewer respondents than the minimum cohort are merged as in the
+		// reports, so no row can be traced back to a small team
+		minGroupSize, err := s.getMinGroupSize(ctx, cq)
+		if err != nil {
+			return nil, err
+		}
+		sizes, err := s.anonymousResponseRepo.CountByDepartment(ctx, cq.ID)
+		if err != nil {
+			return nil, err
+		}
+		export.departments = models.GroupSegments(sizes, minGroupSize)
+	} else {
+		users, err := s.userMetadataRepo.GetByCompanyID(ctx, cq.CompanyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get employees: %w", err)
+		}
+		export.users = make(map[string]*models.UserMetadata, len(users))
+		for _, user := range users {
+			export.users[user.ID] = user
+		}
+	}
+
+	return export, nil
+}
+
+// FileName returns the name of the exported file for a table format
+func (e *ResponseExport) FileName(format string) string {
+	return fmt.Sprintf("responses-%s-%s.%s", e.cq.ID.Hex(), e.cq.PeriodStart.Format("2006-01-02"), format)
+}
+
+// WriteTo writes the header and streams the rows from the database, then closes the writer
+func (e *ResponseExport) WriteTo(ctx context.Context, tw utils.TableWriter) error {
+	// Anonymous exports carry neither the user nor the supervisor, which would point to a team,
+	// nor any date, which joined with the department would point to whoever submitted that day
+	var header []interface{}
+	if e.cq.IsAnonymous {
+		header = []interface{}{"department", "status"}
+	} else {
+		header = []interface{}{"user_id", "department", "supervisor_id", "status", "started_at", "completed_at"}
+	}
+	for _, question := range e.questions {
+		header = append(header, question.QuestionText)
+	}
+	if err := tw.WriteRow(header); err != nil {
+		return err
+	}
+
+	var err error
+	if e.cq.IsAnonymous {
+		err = e.service.anonymousResponseRepo.StreamByCompanyQuestionnaireID(ctx, e.cq.ID, func(set *models.AnonymousResponseSet) error {
+			row := []interface{}{e.departments[set.Department], string(models.AssignmentStatusCompleted)}
+			return tw.WriteRow(e.appendAnswers(row, set.Responses))
+		})
+	} else {
+		err = e.service.assignmentRepo.StreamByCompanyQuestionnaireID(ctx, e.cq.ID, func(assignment *models.UserQuestionnaireAssignment) error {
+			row := []interface{}{assignment.UserID, "", "", string(assignment.Status), exportTime(assignment.StartedAt), exportTime(assignment.CompletedAt)}
+			if user, ok := e.users[assignment.UserID]; ok {
+				row[1], row[2] = user.Department, user.SupervisorID
+			}
+			return tw.WriteRow(e.appendAnswers(row, assignment.Responses))
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+// appendAnswers appends one cell per question, empty when the question was not answered
+func (e *ResponseExport) appendAnswers(row []interface{}, responses []models.Response) []interface{} {
+	values := make(map[string]interface{}, len(responses))
+	for _, r := range responses {
+		values[r.QuestionID] = r.GetValue()
+	}
+	for _, question := range e.questions {
+		row = append(row, models.ExportValue(values[question.QuestionID]))
+	}
+	return row
+}
+
+// exportTime formats an optional timestamp as RFC 3339 in UTC
+func exportTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"log"
+	"questionarie-service/repository"
+	"sync"
+	"time"
+)
+
+// SchedulerLeaseName is the lease that elects the instance running the scheduled jobs
+const SchedulerLeaseName = "scheduler"
+
+// SchedulerConfig holds the settings of the background scheduler
+type SchedulerConfig struct {
+	Interval   time.Duration // Time between ticks
+	LeaseTTL   time.Duration // How long the leader keeps the lease without renewing it; longer than Interval
+	InstanceID string        // Identifies this instance as lease holder
+}
+
+// SchedulerStatus describes the scheduler of this instance and the last tick it ran as leader
+type SchedulerStatus struct {
+	InstanceID          string              `json:"instance_id"`
+	Running             bool                `json:"running"` // False when the scheduler is disabled on this instance
+	Interval            string              `json:"interval"`
+	IsLeader            bool                `json:"is_leader"`
+	Leader              string              `json:"leader,omitempty"`
+	LeaseExpiresAt      *time.Time          `json:"lease_expires_at,omitempty"`
+	LastRunAt           *time.Time          `json:"last_run_at,omitempty"`
+	LastTransitions     *PeriodTransitions  `json:"last_transitions,omitempty"`
+	LastCampaignPeriods int                 `json:"last_campaign_periods"` // Periods created by campaigns in the last run
+	LastNotifications   *NotificationRun    `json:"last_notifications,omitempty"`
+	LastOutbox          *OutboxRun          `json:"last_outbox,omitempty"`
+	LastWebhooks        *WebhookDeliveryRun `json:"last_webhooks,omitempty"`
+	LastErrors          []string            `json:"last_errors,omitempty"`
+}
+
+// Scheduler runs the period lifecycle, campaign, notification, outbox relay and webhook jobs on a
+// fixed tick. Every instance ticks, but only the one holding the scheduler lease runs the jobs.
+type Scheduler struct {
+	leaseRepo           *repository.LeaseRepository
+	companyService      *CompanyService
+	campaignService     *CampaignService
+	notificationService *NotificationService
+	outboxService       *OutboxService
+	webhookService      *WebhookService
+	config              SchedulerConfig
+
+	mu     sync.Mutex
+	status SchedulerStatus
+}
+
+// NewScheduler creates a new Scheduler
+func NewScheduler(leaseRepo *repository.LeaseRepository, companyService *CompanyService, campaignService *CampaignService, notificationService *NotificationService, outboxService *OutboxService, webhookService *WebhookService, config SchedulerConfig) *Scheduler {
+	return &Scheduler{
+		leaseRepo:           leaseRepo,
+		companyService:      companyService,
+		campaignService:     campaignService,
+		notificationService: notificationService,
+		outboxService:       outboxService,
+		webhookService:      webhookService,
+		config:              config,
+		status:              SchedulerStatus{InstanceID: config.InstanceID, Interval: config.Interval.String()},
+	}
+}
+
+// Run ticks until ctx is cancelled, then releases the lease if this instance holds it
+func (s *Scheduler) Run(ctx context.Context) {
+	log.Printf("Scheduler started (instance %s, every %s)", s.config.InstanceID, s.config.Interval)
+	s.mu.Lock()
+	s.status.Running = true
+	s.mu.Unlock()
+
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	s.Tick(ctx, time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			s.release()
+			s.mu.Lock()
+			s.status.Running = false
+			s.mu.Unlock()
+			log.Println("Scheduler stopped")
+			return
+		case now := <-ticker.C:
+			s.Tick(ctx, now)
+		}
+	}
+}
+
+// Tick renews or takes the lease and, as leader, generates due campaign periods, moves company
+// questionnaires through their period lifecycle, then queues and delivers notifications, relays
+// outbox events and posts pending webhook deliveries. A failing job does not stop the others.
+func (s *Scheduler) Tick(ctx context.Context, now time.Time) {
+	ctx, cancel := context.WithTimeout(ctx, s.config.Interval)
+	defer cancel()
+
+	leader, err := s.leaseRepo.Acquire(ctx, SchedulerLeaseName, s.config.InstanceID, now, s.config.LeaseTTL)
+	if err != nil {
+		log.Printf("Scheduler: %v", err)
+		leader = false
+	}
+	s.setLeader(leader)
+	if !leader {
+		return
+	}
+
+	var errs []string
+
+	created, err := s.campaignService.RunDueCampaigns(ctx, now)
+	if err != nil {
+		errs = append(errs, "campaigns: "+err.Error())
+	}
+	if len(created) > 0 {
+		log.Printf("Scheduler: created %d campaign periods", len(created))
+	}
+
+	transitions, err := s.companyService.RunPeriodTransitions(ctx, now)
+	if err != nil {
+		errs = append(errs, "period transitions: "+err.Error())
+	}
+	if transitions != nil && (transitions.Activated > 0 || transitions.Closed > 0) {
+		log.Printf("Scheduler: activated %d and closed %d company questionnaires, expired %d assignments",
+			transitions.Activated, transitions.Closed, transitions.ExpiredAssignments)
+	}
+
+	notified, err := s.notificationService.RunNotifications(ctx, now)
+	if err != nil {
+		errs = append(errs, "notifications: "+err.Error())
+	}
+	if notified != nil && (notified.Sent > 0 || notified.Failed > 0) {
+		log.Printf("Scheduler: sent %d notifications, %d failed", notified.Sent, notified.Failed)
+	}
+
+	relayed, err := s.outboxService.RelayPending(ctx, now)
+	if err != nil {
+		errs = append(errs, "outbox: "+err.Error())
+	}
+	if relayed != nil && (relayed.Published > 0 || relayed.Retrying > 0) {
+		log.Printf("Scheduler: relayed %d outbox events, %d to retry", relayed.Published, relayed.Retrying)
+	}
+
+	delivered, err := s.webhookService.DeliverPending(ctx, now)
+	if err != nil {
+		errs = append(errs, "webhooks: "+err.Error())
+	}
+	if delivered != nil && (delivered.Delivered > 0 || delivered.Failed > 0) {
+		log.Printf("Scheduler: delivered %d webhook events, %d failed", delivered.Delivered, delivered.Failed)
+	}
+
+	for _, e := range errs {
+		log.Printf("Scheduler: %s", e)
+	}
+
+	s.mu.Lock()
+	s.status.LastRunAt = &now
+	s.status.LastTransitions = transitions
+	s.status.LastCampaignPeriods = len(created)
+	s.status.LastNotifications = notified
+	s.status.LastOutbox = relayed
+	s.status.LastWebhooks = delivered
+	s.status.LastErrors = errs
+	s.mu.Unlock()
+}
+
+// GetStatus returns the state of this instance's scheduler and the current lease holder
+func (s *Scheduler) GetStatus(ctx context.Context) *SchedulerStatus {
+	s.mu.Lock()
+	status := s.status
+	s.mu.Unlock()
+
+	if lease, err := s.leaseRepo.GetByName(ctx, SchedulerLeaseName); err == nil && lease.ExpiresAt.After(time.Now()) {
+		status.Leader = lease.Holder
+		status.LeaseExpiresAt = &lease.ExpiresAt
+	}
+	return &status
+}
+
+// setLeader records whether this instance holds the lease, logging changes
+func (s *Scheduler) setLeader(leader bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if leader != s.status.IsLeader {
+		if leader {
+			log.Printf("Scheduler: instance %s is now the leader", s.config.InstanceID)
+		} else {
+			log.Printf("Scheduler: instance %s is no longer the leader", s.config.InstanceID)
+		}
+	}
+	s.status.IsLeader = leader
+}
+
+// release gives up the lease on shutdown so another instance takes over without waiting for it to expire
+func (s *Scheduler) release() {
+	s.mu.Lock()
+	leader := s.status.IsLeader
+	s.mu.Unlock()
+	if !leader {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.leaseRepo.Release(ctx, SchedulerLeaseName, s.config.InstanceID); err != nil {
+		log.Printf("Scheduler: %v", err)
+	}
+}
@@ -0,0 +1,398 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"questionarie-service/models"
+	"questionarie-service/utils"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaxUserMetadataImportRows is the maximum number of rows in a user metadata import
+const MaxUserMetadataImportRows = 10000
+
+// UserMetadataImportRow is a data row of a user metadata CSV file
+type UserMetadataImportRow struct {
+	Line         int
+	UserID       string
+	Company      string
+	SupervisorID string
+	Department   string
+	Email        string
+}
+
+// UserMetadataImportError is a problem found in a row of a user metadata import
+type UserMetadataImportError struct {
+	Line    int    `json:"line"`
+	UserID  string `json:"user_id,omitempty"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// UserMetadataImportResult summarizes a user metadata import. In a dry run the counts are what
+// applying the file would do.
+type UserMetadataImportResult struct {
+	DryRun    bool                      `json:"dry_run"`
+	Rows      int                       `json:"rows"`
+	Created   int                       `json:"created"`
+	Updated   int                       `json:"updated"`
+	Unchanged int                       `json:"unchanged"`
+	Errors    []UserMetadataImportError `json:"errors"`
+}
+
+// userMetadataImportColumns are the columns of a user metadata CSV file
+var userMetadataImportColumns = []string{"user_id", "company", "supervisor_id", "department", "email"}
+
+// ImportUserMetadata creates or updates users metadata from a CSV file with the columns user_id,
+// company (ID or exact name), supervisor_id, department and email. Supervisors may be existing
+// users or any row of the same file, in any order, and must end up in the same company as the
+// user. Every row is checked before anything is written and the file is only applied when no row
+// has errors; applying the same file again changes nothing. When the supervisor_id, department or
+// email column is left out, existing values are kept. Checks and writes run in one transaction,
+// so the file is applied as a whole or not at all.
+func (s *UserMetadataService) ImportUserMetadata(ctx context.Context, r io.Reader, dryRun bool) (*UserMetadataImportResult, error) {
+	rows, columns, err := parseUserMetadataCSV(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *UserMetadataImportResult
+	err = s.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = s.importUserMetadataRows(ctx, rows, columns, dryRun)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// importUserMetadataRows checks the rows of a user metadata import against the stored users and
+// applies them unless dryRun is set or a row has errors
+func (s *UserMetadataService) importUserMetadataRows(ctx context.Context, rows []UserMetadataImportRow, columns map[string]bool, dryRun bool) (*UserMetadataImportResult, error) {
+	result := &UserMetadataImportResult{DryRun: dryRun, Rows: len(rows), Errors: []UserMetadataImportError{}}
+	addError := func(row UserMetadataImportRow, field, format string, args ...interface{}) {
+		result.Errors = append(result.Errors, UserMetadataImportError{Line: row.Line, UserID: row.UserID, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// The first row of each user wins; later ones are reported as duplicates
+	firstLine := make(map[string]int, len(rows))
+	var unique []UserMetadataImportRow
+	for _, row := range rows {
+		if row.UserID == "" {
+			addError(row, "user_id", "is required")
+			continue
+		}
+		if line, seen := firstLine[row.UserID]; seen {
+			addError(row, "user_id", "duplicate of line %d", line)
+			continue
+		}
+		firstLine[row.UserID] = row.Line
+		unique = append(unique, row)
+	}
+
+	companies, err := s.resolveImportCompanies(ctx, unique)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.loadSupervisorChains(ctx, unique)
+	if err != nil {
+		return nil, err
+	}
+
+	// The supervisor graph and companies as they would be after the import
+	supervisorOf := make(map[string]string, len(existing)+len(unique))
+	companyOf := make(map[string]primitive.ObjectID, len(existing)+len(unique))
+	for id, user := range existing {
+		supervisorOf[id] = user.SupervisorID
+		companyOf[id] = user.CompanyID
+	}
+	users := make(map[string]*models.UserMetadata, len(unique))
+	for _, row := range unique {
+		user := models.NewUserMetadata(row.UserID, primitive.NilObjectID)
+		if company := companies[row.Company]; company != nil {
+			user.CompanyID = company.ID
+		}
+		user.SupervisorID, user.Department, user.Email = row.SupervisorID, row.Department, row.Email
+		if current, ok := existing[row.UserID]; ok {
+			user.CreatedAt = current.CreatedAt
+			if !columns["supervisor_id"] {
+				user.SupervisorID = current.SupervisorID
+			}
+			if !columns["department"] {
+				user.Department = current.Department
+			}
+			if !columns["email"] {
+				user.Email = current.Email
+			}
+		}
+		users[row.UserID] = user
+		supervisorOf[row.UserID] = user.SupervisorID
+		companyOf[row.UserID] = user.CompanyID
+	}
+
+	valid := make([]*models.UserMetadata, 0, len(unique))
+	for _, row := range unique {
+		user := users[row.UserID]
+		ok := true
+
+		if company, found := companies[row.Company]; !found {
+			ok = false
+			if row.Company == "" {
+				addError(row, "company", "is required")
+			} else {
+				addError(row, "company", "unknown company %q", row.Company)
+			}
+		} else if company == nil {
+			ok = false
+			addError(row, "company", "more than one company is named %q, use the company ID", row.Company)
+		} else if current, found := existing[row.UserID]; found && current.CompanyID != company.ID {
+			// Subordinates outside the file keep their company, so they would be left behind
+			subordinates, err := s.userMetadataRepo.GetBySupervisorID(ctx, row.UserID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check supervised users: %w", err)
+			}
+			for _, subordinate := range subordinates {
+				if _, inFile := users[subordinate.ID]; !inFile && subordinate.CompanyID != company.ID {
+					ok = false
+					addError(row, "company", "user supervises %q, who stays in their current company", subordinate.ID)
+				}
+			}
+		}
+
+		if user.SupervisorID != "" {
+			_, known := supervisorOf[user.SupervisorID]
+			if user.SupervisorID == user.ID {
+				ok = false
+				addError(row, "supervisor_id", "user cannot supervise themselves")
+			} else if !known {
+				ok = false
+				addError(row, "supervisor_id", "supervisor %q not found in the file or existing users", user.SupervisorID)
+			} else if cycle := supervisorCycle(supervisorOf, user.ID); cycle != nil {
+				ok = false
+				addError(row, "supervisor_id", "supervisor cycle: %s", strings.Join(cycle, " → "))
+			} else if !user.CompanyID.IsZero() && !companyOf[user.SupervisorID].IsZero() && companyOf[user.SupervisorID] != user.CompanyID {
+				ok = false
+				addError(row, "supervisor_id", "supervisor %q belongs to a different company", user.SupervisorID)
+			}
+		}
+
+		if row.Email != "" {
+			if err := utils.ValidateEmail(row.Email); err != nil {
+				ok = false
+				addError(row, "email", "%s", err.Error())
+			}
+		}
+
+		if ok {
+			valid = append(valid, user)
+		}
+	}
+
+	if len(result.Errors) > 0 {
+		if dryRun {
+			return result, nil
+		}
+		verrs := utils.NewValidationErrors()
+		for _, e := range result.Errors {
+			verrs.Add(fmt.Sprintf("line %d.%s", e.Line, e.Field), e.Message)
+		}
+		return nil, verrs
+	}
+
+	changed := make([]*models.UserMetadata, 0, len(valid))
+	for _, user := range valid {
+		current, ok := existing[user.ID]
+		switch {
+		case !ok:
+			result.Created++
+		case current.CompanyID == user.CompanyID && current.SupervisorID == user.SupervisorID && current.Department == user.Department && current.Email == user.Email:
+			result.Unchanged++
+			continue
+		default:
+			result.Updated++
+		}
+		changed = append(changed, user)
+	}
+
+	if dryRun {
+		return result, nil
+	}
+
+	// Supervisor changes made meanwhile through existing users on the checked chains conflict with the import
+	chainIDs := make([]string, 0, len(existing))
+	for id := range existing {
+		chainIDs = append(chainIDs, id)
+	}
+	if err := s.userMetadataRepo.LockSupervisorChain(ctx, chainIDs); err != nil {
+		return nil, err
+	}
+
+	created, updated, err := s.userMetadataRepo.UpsertMany(ctx, changed)
+	if err != nil {
+		return nil, err
+	}
+	result.Created, result.Updated = int(created), int(updated)
+	result.Unchanged = len(valid) - result.Created - result.Updated
+
+	return result, nil
+}
+
+// resolveImportCompanies looks up each distinct company of the rows, by ID or exact name. Unknown
+// companies are missing from the map; names shared by several companies map to nil.
+func (s *UserMetadataService) resolveImportCompanies(ctx context.Context, rows []UserMetadataImportRow) (map[string]*models.Company, error) {
+	companies := make(map[string]*models.Company)
+	checked := make(map[string]bool)
+	for _, row := range rows {
+		if row.Company == "" || checked[row.Company] {
+			continue
+		}
+		checked[row.Company] = true
+
+		// A value shaped like an ID that matches no company may still be a company name
+		if id, err := primitive.ObjectIDFromHex(row.Company); err == nil {
+			company, err := s.companyRepo.GetByID(ctx, id)
+			if err == nil {
+				companies[row.Company] = company
+				continue
+			}
+			if !strings.Contains(err.Error(), "not found") {
+				return nil, err
+			}
+		}
+
+		found, err := s.companyRepo.FindByName(ctx, row.Company)
+		if err != nil {
+			return nil, err
+		}
+		switch len(found) {
+		case 0:
+		case 1:
+			companies[row.Company] = found[0]
+		default:
+			companies[row.Company] = nil
+		}
+	}
+	return companies, nil
+}
+
+// loadSupervisorChains loads the existing metadata of the imported users, their supervisors, and
+// the supervisors above them, so cycles through existing users can be found
+func (s *UserMetadataService) loadSupervisorChains(ctx context.Context, rows []UserMetadataImportRow) (map[string]*models.UserMetadata, error) {
+	existing := make(map[string]*models.UserMetadata)
+	requested := make(map[string]bool)
+	var pending []string
+	request := func(id string) {
+		if id != "" && !requested[id] {
+			requested[id] = true
+			pending = append(pending, id)
+		}
+	}
+	for _, row := range rows {
+		request(row.UserID)
+		request(row.SupervisorID)
+	}
+
+	for len(pending) > 0 {
+		users, err := s.userMetadataRepo.GetByIDs(ctx, pending)
+		if err != nil {
+			return nil, err
+		}
+		pending = nil
+		for _, user := range users {
+			existing[user.ID] = user
+			request(user.SupervisorID)
+		}
+	}
+	return existing, nil
+}
+
+// supervisorCycle returns the cycle userID is part of in a supervisor graph, starting and ending
+// with userID, or nil if following its supervisors never leads back to it
+func supervisorCycle(supervisorOf map[string]string, userID string) []string {
+	path := []string{userID}
+	visited := map[string]bool{userID: true}
+	for current := supervisorOf[userID]; current != ""; current = supervisorOf[current] {
+		path = append(path, current)
+		if current == userID {
+			return path
+		}
+		if visited[current] {
+			return nil
+		}
+		visited[current] = true
+	}
+	return nil
+}
+
+// parseUserMetadataCSV reads a user metadata CSV file. The header row names the columns, in any
+// order; user_id and company are required. It returns the rows and which columns were present.
+func parseUserMetadataCSV(r io.Reader) ([]UserMetadataImportRow, map[string]bool, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, fmt.Errorf("invalid CSV: the file is empty")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		index[name] = i
+	}
+	columns := make(map[string]bool, len(userMetadataImportColumns))
+	for _, name := range userMetadataImportColumns {
+		_, columns[name] = index[name]
+	}
+	if !columns["user_id"] || !columns["company"] {
+		return nil, nil, fmt.Errorf("invalid CSV: the header must include the user_id and company columns")
+	}
+
+	var rows []UserMetadataImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		if len(rows) == MaxUserMetadataImportRows {
+			return nil, nil, fmt.Errorf("invalid CSV: more than %d rows", MaxUserMetadataImportRows)
+		}
+
+		cell := func(name string) string {
+			if i, ok := index[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		line, _ := reader.FieldPos(0)
+		row := UserMetadataImportRow{
+			Line:         line,
+			UserID:       cell("user_id"),
+			Company:      cell("company"),
+			SupervisorID: cell("supervisor_id"),
+			Department:   cell("department"),
+			Email:        cell("email"),
+		}
+		if row == (UserMetadataImportRow{Line: line}) {
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, columns, nil
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"questionarie-service/models"
 	"questionarie-service/repository"
+	"questionarie-service/utils"
+	"strings"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -13,25 +15,33 @@ import (
 type UserMetadataService struct {
 	userMetadataRepo *repository.UserMetadataRepository
 	companyRepo      *repository.CompanyRepository
+	transactor       *repository.Transactor
 }
 
 // NewUserMetadataService creates a new UserMetadataService
 func NewUserMetadataService(
 	userMetadataRepo *repository.UserMetadataRepository,
 	companyRepo *repository.CompanyRepository,
+	transactor *repository.Transactor,
 ) *UserMetadataService {
 	return &UserMetadataService{
 		userMetadataRepo: userMetadataRepo,
 		companyRepo:      companyRepo,
+		transactor:       transactor,
 	}
 }
 
 // CreateUserMetadata creates user metadata (Super Admin only)
-func (s *UserMetadataService) CreateUserMetadata(ctx context.Context, userID string, companyID primitive.ObjectID, supervisorID, department string) (*models.UserMetadata, error) {
+func (s *UserMetadataService) CreateUserMetadata(ctx context.Context, userID string, companyID primitive.ObjectID, supervisorID, department, email string) (*models.UserMetadata, error) {
 	// Validate user ID
 	if userID == "" {
 		return nil, fmt.Errorf("user ID is required")
 	}
+	if email != "" {
+		if err := utils.ValidateEmail(email); err != nil {
+			return nil, err
+		}
+	}
 
 	// Check if metadata already exists
 	exists, err := s.userMetadataRepo.Exists(ctx, userID)
@@ -47,14 +57,10 @@ func (s *UserMetadataService) CreateUserMetadata(ctx context.Context, userID str
 		return nil, fmt.Errorf("company not found: %w", err)
 	}
 
-	// Validate supervisor exists if provided
+	// Validate supervisor if provided
 	if supervisorID != "" {
-		supervisorExists, err := s.userMetadataRepo.Exists(ctx, supervisorID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to check supervisor existence: %w", err)
-		}
-		if !supervisorExists {
-			return nil, fmt.Errorf("supervisor not found")
+		if err := s.validateSupervisor(ctx, userID, companyID, supervisorID); err != nil {
+			return nil, err
 		}
 	}
 
@@ -66,6 +72,7 @@ func (s *UserMetadataService) CreateUserMetadata(ctx context.Context, userID str
 	if department != "" {
 		metadata.SetDepartment(department)
 	}
+	metadata.Email = email
 
 	if err := s.userMetadataRepo.Create(ctx, metadata); err != nil {
 		return nil, fmt.Errorf("failed to create user metadata: %w", err)
@@ -99,32 +106,48 @@ func (s *UserMetadataService) GetUsersBySupervisor(ctx context.Context, supervis
 	return s.userMetadataRepo.GetBySupervisorID(ctx, supervisorID)
 }
 
-// UpdateUserMetadata updates user metadata
-func (s *UserMetadataService) UpdateUserMetadata(ctx context.Context, userID string, companyID primitive.ObjectID, supervisorID, department string) error {
+// UpdateUserMetadata updates user metadata. Checks and the write run in one transaction.
+func (s *UserMetadataService) UpdateUserMetadata(ctx context.Context, userID string, companyID primitive.ObjectID, supervisorID, department, email string) error {
+	return s.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		return s.updateUserMetadata(ctx, userID, companyID, supervisorID, department, email)
+	})
+}
+
+// updateUserMetadata validates and applies a change to user metadata
+func (s *UserMetadataService) updateUserMetadata(ctx context.Context, userID string, companyID primitive.ObjectID, supervisorID, department, email string) error {
 	// Get existing metadata
 	metadata, err := s.userMetadataRepo.GetByID(ctx, userID)
 	if err != nil {
 		return err
 	}
 
-	// Validate company if being changed
+	// Validate company if being changed; the user cannot leave their subordinates behind
+	companyChanged := false
 	if !companyID.IsZero() && companyID != metadata.CompanyID {
 		if _, err := s.companyRepo.GetByID(ctx, companyID); err != nil {
 			return fmt.Errorf("company not found: %w", err)
 		}
+		subordinates, err := s.userMetadataRepo.GetBySupervisorID(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to check supervised users: %w", err)
+		}
+		if len(subordinates) > 0 {
+			return fmt.Errorf("invalid company: user supervises %d users in their current company, reassign them first", len(subordinates))
+		}
 		metadata.CompanyID = companyID
+		companyChanged = true
 	}
 
-	// Validate supervisor if being changed
+	// Validate supervisor if being changed, or the current one if the company changed
 	if supervisorID != "" && supervisorID != metadata.SupervisorID {
-		supervisorExists, err := s.userMetadataRepo.Exists(ctx, supervisorID)
-		if err != nil {
-			return fmt.Errorf("failed to check supervisor existence: %w", err)
-		}
-		if !supervisorExists {
-			return fmt.Errorf("supervisor not found")
+		if err := s.validateSupervisor(ctx, userID, metadata.CompanyID, supervisorID); err != nil {
+			return err
 		}
 		metadata.SetSupervisor(supervisorID)
+	} else if companyChanged && metadata.HasSupervisor() {
+		if err := s.validateSupervisor(ctx, userID, metadata.CompanyID, metadata.SupervisorID); err != nil {
+			return fmt.Errorf("%w, assign a supervisor of the new company", err)
+		}
 	}
 
 	// Update department if provided
@@ -132,6 +155,14 @@ func (s *UserMetadataService) UpdateUserMetadata(ctx context.Context, userID str
 		metadata.SetDepartment(department)
 	}
 
+	// Update email if provided
+	if email != "" {
+		if err := utils.ValidateEmail(email); err != nil {
+			return err
+		}
+		metadata.Email = email
+	}
+
 	return s.userMetadataRepo.Update(ctx, userID, metadata)
 }
 
@@ -150,25 +181,80 @@ func (s *UserMetadataService) DeleteUserMetadata(ctx context.Context, userID str
 	return s.userMetadataRepo.Delete(ctx, userID)
 }
 
-// AssignSupervisor assigns or updates a supervisor for a user
+// AssignSupervisor assigns or updates a supervisor for a user. An empty supervisor ID removes it.
+// The check and the write run in one transaction.
 func (s *UserMetadataService) AssignSupervisor(ctx context.Context, userID, supervisorID string) error {
-	// Validate supervisor exists
-	if supervisorID != "" {
-		supervisorExists, err := s.userMetadataRepo.Exists(ctx, supervisorID)
+	return s.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		metadata, err := s.userMetadataRepo.GetByID(ctx, userID)
 		if err != nil {
-			return fmt.Errorf("failed to check supervisor existence: %w", err)
+			return err
+		}
+
+		if supervisorID != "" {
+			if err := s.validateSupervisor(ctx, userID, metadata.CompanyID, supervisorID); err != nil {
+				return err
+			}
+		}
+
+		return s.userMetadataRepo.UpdateSupervisor(ctx, userID, supervisorID)
+	})
+}
+
+// validateSupervisor checks that supervisorID can supervise userID in a company, so the supervisor
+// graph of each company stays a tree: the supervisor must exist, belong to the same company, and
+// not be the user or one of the users below them. The users walked through are locked, so call it
+// in the transaction that writes the change.
+func (s *UserMetadataService) validateSupervisor(ctx context.Context, userID string, companyID primitive.ObjectID, supervisorID string) error {
+	if supervisorID == userID {
+		return fmt.Errorf("invalid supervisor: user cannot supervise themselves")
+	}
+
+	supervisor, err := s.userMetadataRepo.GetByID(ctx, supervisorID)
+	if err != nil {
+		return fmt.Errorf("supervisor not found: %w", err)
+	}
+	if !supervisor.BelongsToCompany(companyID) {
+		return fmt.Errorf("invalid supervisor: supervisor belongs to a different company")
+	}
+
+	// Walk up from the supervisor; reaching the user means the user is above their new supervisor
+	chain := []string{userID, supervisorID}
+	visited := map[string]bool{supervisorID: true}
+	for current := supervisor; current.HasSupervisor(); {
+		next := current.SupervisorID
+		chain = append(chain, next)
+		if next == userID {
+			return fmt.Errorf("invalid supervisor: would create a cycle (%s)", strings.Join(chain, " → "))
 		}
-		if !supervisorExists {
-			return fmt.Errorf("supervisor not found")
+		if visited[next] {
+			break
 		}
+		visited[next] = true
 
-		// Prevent self-supervision
-		if userID == supervisorID {
-			return fmt.Errorf("user cannot supervise themselves")
+		current, err = s.userMetadataRepo.GetByID(ctx, next)
+		if err != nil {
+			if strings.Contains(err.Error(), "not found") {
+				break
+			}
+			return fmt.Errorf("failed to check supervisor chain: %w", err)
 		}
 	}
 
-	return s.userMetadataRepo.UpdateSupervisor(ctx, userID, supervisorID)
+	return s.userMetadataRepo.LockSupervisorChain(ctx, chain)
+}
+
+// GetOrgChart builds the supervisor tree of a company
+func (s *UserMetadataService) GetOrgChart(ctx context.Context, companyID primitive.ObjectID) (*models.OrgChart, error) {
+	if _, err := s.companyRepo.GetByID(ctx, companyID); err != nil {
+		return nil, fmt.Errorf("company not found: %w", err)
+	}
+
+	users, err := s.userMetadataRepo.GetByCompanyID(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewOrgChart(companyID, users), nil
 }
 
 // GetCompanyDepartments retrieves all departments for a company
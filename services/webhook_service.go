@@ -0,0 +1,262 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"questionarie-service/models"
+	"questionarie-service/repository"
+	"questionarie-service/utils"
+	"questionarie-service/webhooks"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// webhookTimeout is how long a subscription has to answer a delivery
+	webhookTimeout = 10 * time.Second
+	// webhookDeliveryLock is how long a claimed delivery waits before another attempt if the
+	// instance sending it stops
+	webhookDeliveryLock = 2 * time.Minute
+	// webhookBatchSize is the maximum number of deliveries attempted per run
+	webhookBatchSize = 200
+	// maxWebhookDeliveriesListed is the maximum number of deliveries returned by a listing
+	maxWebhookDeliveriesListed = 500
+)
+
+// WebhookService manages webhook subscriptions and delivers company events to them
+type WebhookService struct {
+	subscriptionRepo *repository.WebhookSubscriptionRepository
+	deliveryRepo     *repository.WebhookDeliveryRepository
+	companyRepo      *repository.CompanyRepository
+	sender           *webhooks.Sender
+}
+
+// NewWebhookService creates a new WebhookService
+func NewWebhookService(
+	subscriptionRepo *repository.WebhookSubscriptionRepository,
+	deliveryRepo *repository.WebhookDeliveryRepository,
+	companyRepo *repository.CompanyRepository,
+) *WebhookService {
+	return &WebhookService{
+		subscriptionRepo: subscriptionRepo,
+		deliveryRepo:     deliveryRepo,
+		companyRepo:      companyRepo,
+		sender:           webhooks.NewSender(webhookTimeout),
+	}
+}
+
+// WebhookSubscriptionInput holds the settings of a new webhook subscription
+type WebhookSubscriptionInput struct {
+	URL         string
+	Secret      string // Generated when empty
+	EventTypes  []models.WebhookEventType
+	Description string
+}
+
+// WebhookSubscriptionUpdate holds the settings to change on a subscription; nil fields are left as they are
+type WebhookSubscriptionUpdate struct {
+	URL         *string
+	Secret      *string
+	EventTypes  []models.WebhookEventType
+	Description *string
+	IsActive    *bool
+}
+
+// CreatedWebhookSubscription is a new subscription together with its secret, which is not
+// returned again
+type CreatedWebhookSubscription struct {
+	*models.WebhookSubscription
+	Secret string `json:"secret"`
+}
+
+// WebhookDeliveryRun counts the deliveries attempted by one run of the webhook job
+type WebhookDeliveryRun struct {
+	Delivered int `json:"delivered"`
+	Retrying  int `json:"retrying"`
+	Failed    int `json:"failed"`
+}
+
+// CreateSubscription creates a webhook subscription for a company (Super Admin only)
+func (s *WebhookService) CreateSubscription(ctx context.Context, companyID primitive.ObjectID, input WebhookSubscriptionInput, createdBy string) (*CreatedWebhookSubscription, error) {
+	if _, err := s.companyRepo.GetByID(ctx, companyID); err != nil {
+		return nil, fmt.Errorf("company not found: %w", err)
+	}
+
+	count, err := s.subscriptionRepo.CountByCompanyID(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= models.MaxWebhookSubscriptions {
+		return nil, fmt.Errorf("conflict: company already has %d webhook subscriptions", models.MaxWebhookSubscriptions)
+	}
+
+	secret := input.Secret
+	if secret == "" {
+		if secret, err = webhooks.GenerateSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	subscription := models.NewWebhookSubscription(companyID, input.URL, secret, input.EventTypes, input.Description, createdBy)
+	if err := validateWebhookSubscription(subscription); err != nil {
+		return nil, err
+	}
+
+	if err := s.subscriptionRepo.Create(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	return &CreatedWebhookSubscription{WebhookSubscription: subscription, Secret: secret}, nil
+}
+
+// GetSubscription retrieves a webhook subscription by ID
+func (s *WebhookService) GetSubscription(ctx context.Context, id primitive.ObjectID) (*models.WebhookSubscription, error) {
+	return s.subscriptionRepo.GetByID(ctx, id)
+}
+
+// GetCompanySubscriptions retrieves the webhook subscriptions of a company
+func (s *WebhookService) GetCompanySubscriptions(ctx context.Context, companyID primitive.ObjectID) ([]*models.WebhookSubscription, error) {
+	if _, err := s.companyRepo.GetByID(ctx, companyID); err != nil {
+		return nil, err
+	}
+	return s.subscriptionRepo.GetByCompanyID(ctx, companyID)
+}
+
+// UpdateSubscription changes the settings of a webhook subscription. Events already queued are
+// delivered with the new URL and secret.
+func (s *WebhookService) UpdateSubscription(ctx context.Context, id primitive.ObjectID, update WebhookSubscriptionUpdate) (*models.WebhookSubscription, error) {
+	subscription, err := s.subscriptionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.URL != nil {
+		subscription.URL = *update.URL
+	}
+	if update.Secret != nil {
+		subscription.Secret = *update.Secret
+	}
+	if update.EventTypes != nil {
+		subscription.EventTypes = update.EventTypes
+	}
+	if update.Description != nil {
+		subscription.Description = *update.Description
+	}
+	if update.IsActive != nil {
+		subscription.IsActive = *update.IsActive
+	}
+
+	if err := validateWebhookSubscription(subscription); err != nil {
+		return nil, err
+	}
+
+	if err := s.subscriptionRepo.Update(ctx, id, subscription); err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+// DeleteSubscription deletes a webhook subscription and its delivery log
+func (s *WebhookService) DeleteSubscription(ctx context.Context, id primitive.ObjectID) error {
+	if err := s.subscriptionRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	return s.deliveryRepo.DeleteBySubscriptionID(ctx, id)
+}
+
+// GetDeliveries retrieves the latest deliveries of a subscription, optionally filtered by status
+func (s *WebhookService) GetDeliveries(ctx context.Context, subscriptionID primitive.ObjectID, status *models.WebhookDeliveryStatus) ([]*models.WebhookDelivery, error) {
+	if _, err := s.subscriptionRepo.GetByID(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+	return s.deliveryRepo.GetBySubscriptionID(ctx, subscriptionID, status, maxWebhookDeliveriesListed)
+}
+
+// Name implements outbox.Sink
+func (s *WebhookService) Name() string {
+	return "webhook"
+}
+
+// Publish implements outbox.Sink, queuing a domain event for delivery to the active subscriptions
+// of its company that receive its type. Publishing an event again queues nothing new.
+func (s *WebhookService) Publish(ctx context.Context, event *models.OutboxEvent) error {
+	subscriptions, err := s.subscriptionRepo.GetActiveForEvent(ctx, event.CompanyID, event.Type)
+	if err != nil || len(subscriptions) == 0 {
+		return err
+	}
+
+	deliveries := make([]*models.WebhookDelivery, len(subscriptions))
+	for i, subscription := range subscriptions {
+		deliveries[i] = models.NewWebhookDelivery(subscription, event.ID.Hex(), event.Type, event.Payload)
+	}
+	return s.deliveryRepo.CreateMany(ctx, deliveries)
+}
+
+// DeliverPending posts the deliveries due at now. Failed attempts are retried with a growing delay
+// until MaxWebhookAttempts; deliveries of deleted or inactive subscriptions fail right away.
+func (s *WebhookService) DeliverPending(ctx context.Context, now time.Time) (*WebhookDeliveryRun, error) {
+	result := &WebhookDeliveryRun{}
+	subscriptions := make(map[primitive.ObjectID]*models.WebhookSubscription)
+
+	for i := 0; i < webhookBatchSize; i++ {
+		delivery, err := s.deliveryRepo.ClaimNext(ctx, now, now.Add(webhookDeliveryLock))
+		if err != nil {
+			return result, err
+		}
+		if delivery == nil {
+			break
+		}
+
+		subscription, ok := subscriptions[delivery.SubscriptionID]
+		if !ok {
+			subscription, err = s.subscriptionRepo.GetByID(ctx, delivery.SubscriptionID)
+			if err != nil {
+				subscription = nil
+			}
+			subscriptions[delivery.SubscriptionID] = subscription
+		}
+		if subscription == nil || !subscription.IsActive {
+			if err := s.deliveryRepo.MarkFailed(ctx, delivery.ID, 0, "subscription deleted or inactive"); err != nil {
+				return result, err
+			}
+			result.Failed++
+			continue
+		}
+
+		statusCode, sendErr := s.sender.Send(ctx, webhooks.Request{
+			URL:        subscription.URL,
+			Secret:     subscription.Secret,
+			EventType:  string(delivery.EventType),
+			EventID:    delivery.EventID,
+			DeliveryID: delivery.ID.Hex(),
+			Body:       []byte(delivery.Payload),
+		})
+		switch {
+		case sendErr == nil:
+			err = s.deliveryRepo.MarkDelivered(ctx, delivery.ID, statusCode, time.Now())
+			result.Delivered++
+		case delivery.Attempts >= models.MaxWebhookAttempts:
+			err = s.deliveryRepo.MarkFailed(ctx, delivery.ID, statusCode, sendErr.Error())
+			result.Failed++
+		default:
+			err = s.deliveryRepo.MarkRetry(ctx, delivery.ID, statusCode, sendErr.Error(), time.Now().Add(models.WebhookRetryDelay(delivery.Attempts)))
+			result.Retrying++
+		}
+		if err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// validateWebhookSubscription returns the field errors of a subscription's settings
+func validateWebhookSubscription(subscription *models.WebhookSubscription) error {
+	if err := subscription.Validate(); err != nil {
+		verrs := utils.NewValidationErrors()
+		addOptionsError(verrs, "subscription", err)
+		return verrs
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+// Package templates holds the document templates versioned with the service
+package templates
+
+import "embed"
+
+// Reports holds the report templates, one file per report, rendered with text/template into the
+// layout format read by utils.NewPDFDocumentFromLayout
+//
+//go:embed reports/*.tmpl
+var Reports embed.FS
+
+// Notifications holds the notification templates, one file per event, rendered with text/template.
+// The first line of the output is the subject and the rest, after a blank line, the body.
+//
+//go:embed notifications/*.tmpl
+var Notifications embed.FS
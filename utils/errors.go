@@ -2,14 +2,53 @@ package utils
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 )
 
 // ErrorResponse represents a standard error response
 type ErrorResponse struct {
-	Error   string `json:"error"`
+	Error   string       `json:"error"`
+	Message string       `json:"message"`
+	Code    int          `json:"code"`
+	Fields  []FieldError `json:"fields,omitempty"`
+}
+
+// FieldError describes a validation failure on a single request field
+type FieldError struct {
+	Field   string `json:"field"`
 	Message string `json:"message"`
-	Code    int    `json:"code"`
+}
+
+// ValidationErrors is an error carrying one or more field-level validation failures
+type ValidationErrors struct {
+	Fields []FieldError
+}
+
+// NewValidationErrors creates an empty ValidationErrors
+func NewValidationErrors() *ValidationErrors {
+	return &ValidationErrors{Fields: []FieldError{}}
+}
+
+// Add appends a field error
+func (v *ValidationErrors) Add(field, message string) {
+	v.Fields = append(v.Fields, FieldError{Field: field, Message: message})
+}
+
+// HasErrors reports whether any field errors were recorded
+func (v *ValidationErrors) HasErrors() bool {
+	return len(v.Fields) > 0
+}
+
+// Error implements the error interface
+func (v *ValidationErrors) Error() string {
+	parts := make([]string, 0, len(v.Fields))
+	for _, f := range v.Fields {
+		parts = append(parts, fmt.Sprintf("%s: %s", f.Field, f.Message))
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
 }
 
 // SuccessResponse represents a standard success response
@@ -84,19 +123,36 @@ var (
 	}
 )
 
+// RespondWithValidationErrors sends a 422 response listing the failing fields
+func RespondWithValidationErrors(w http.ResponseWriter, verrs *ValidationErrors) {
+	RespondWithJSON(w, http.StatusUnprocessableEntity, ErrorResponse{
+		Error:   http.StatusText(http.StatusUnprocessableEntity),
+		Message: "validation failed",
+		Code:    http.StatusUnprocessableEntity,
+		Fields:  verrs.Fields,
+	})
+}
+
 // HandleRepositoryError converts repository errors to HTTP responses
 func HandleRepositoryError(w http.ResponseWriter, err error) {
 	if err == nil {
 		return
 	}
 
+	// Field-level validation errors carry their own payload
+	var verrs *ValidationErrors
+	if errors.As(err, &verrs) {
+		RespondWithValidationErrors(w, verrs)
+		return
+	}
+
 	errMsg := err.Error()
 
 	// Check for specific error patterns
 	switch {
 	case contains(errMsg, "not found"):
 		NotFound(w, errMsg)
-	case contains(errMsg, "duplicate") || contains(errMsg, "already exists"):
+	case contains(errMsg, "duplicate") || contains(errMsg, "already exists") || contains(errMsg, "conflict"):
 		Conflict(w, errMsg)
 	case contains(errMsg, "unauthorized"):
 		Forbidden(w, errMsg)
@@ -0,0 +1,420 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// PDF page geometry in points (A4)
+const (
+	pdfPageWidth  = 595.0
+	pdfPageHeight = 842.0
+	pdfMargin     = 50.0
+	pdfTextWidth  = pdfPageWidth - 2*pdfMargin
+)
+
+// pdfFont is one of the standard fonts every PDF reader provides, so nothing has to be embedded
+type pdfFont struct {
+	resource string  // Resource name used in content streams
+	baseFont string  // Standard font name
+	size     float64 // Size in points
+	leading  float64 // Line height in points
+	mono     bool    // Courier: every glyph is 600/1000 em wide
+}
+
+var (
+	pdfFontTitle   = pdfFont{"F1", "Helvetica-Bold", 16, 22, false}
+	pdfFontHeading = pdfFont{"F1", "Helvetica-Bold", 11, 16, false}
+	pdfFontBody    = pdfFont{"F2", "Helvetica", 10, 13, false}
+	pdfFontTable   = pdfFont{"F3", "Courier", 8.5, 11, true}
+	pdfFontTableB  = pdfFont{"F4", "Courier-Bold", 8.5, 11, true}
+	pdfFontFooter  = pdfFont{"F2", "Helvetica", 8, 10, false}
+)
+
+// pdfFontResources lists the fonts referenced by every page, in object order
+var pdfFontResources = []pdfFont{pdfFontTitle, pdfFontBody, pdfFontTable, pdfFontTableB}
+
+// PDFDocument lays out text on A4 pages with the standard PDF fonts. It supports the small set of
+// blocks reports need (titles, headings, wrapped paragraphs and fixed-width tables) and keeps pages
+// in memory until WriteTo, so the total page count can be printed in the footer.
+type PDFDocument struct {
+	Footer string // Printed at the bottom of every page, followed by the page number
+
+	pages []*bytes.Buffer
+	y     float64 // Baseline of the next line on the current page
+}
+
+// NewPDFDocument creates an empty document
+func NewPDFDocument() *PDFDocument {
+	return &PDFDocument{}
+}
+
+// Title adds a document title
+func (d *PDFDocument) Title(text string) {
+	d.space(6)
+	d.wrapped(pdfFontTitle, text)
+	d.space(4)
+}
+
+// Heading adds a section heading, kept on the same page as at least two following lines
+func (d *PDFDocument) Heading(text string) {
+	d.space(8)
+	d.ensure(pdfFontHeading.leading + 2*pdfFontBody.leading)
+	d.wrapped(pdfFontHeading, text)
+	d.space(2)
+}
+
+// Paragraph adds text wrapped to the page width
+func (d *PDFDocument) Paragraph(text string) {
+	d.wrapped(pdfFontBody, text)
+}
+
+// Space adds vertical space
+func (d *PDFDocument) Space() {
+	d.space(pdfFontBody.leading / 2)
+}
+
+// Rule adds a horizontal line
+func (d *PDFDocument) Rule() {
+	d.space(4)
+	d.ensure(6)
+	fmt.Fprintf(d.page(), "0.5 w %.2f %.2f m %.2f %.2f l S\n", pdfMargin, d.y+4, pdfPageWidth-pdfMargin, d.y+4)
+	d.space(6)
+}
+
+// Table adds rows of cells in fixed-width columns. Columns are as wide as their widest cell; when
+// the table does not fit, the widest columns are narrowed and their cells shortened with "...".
+// The first row is printed in bold when header is set.
+func (d *PDFDocument) Table(rows [][]string, header bool) {
+	if len(rows) == 0 {
+		return
+	}
+
+	widths := []int{}
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if n := len([]rune(cell)); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	const gap = 2
+	available := int(pdfTextWidth / (pdfFontTable.size * 0.6))
+	for {
+		total := 0
+		widest := 0
+		for i, w := range widths {
+			total += w + gap
+			if w > widths[widest] {
+				widest = i
+			}
+		}
+		if total-gap <= available || widths[widest] <= 4 {
+			break
+		}
+		widths[widest]--
+	}
+
+	for i, row := range rows {
+		var line strings.Builder
+		for j, cell := range row {
+			line.WriteString(padRight(truncateRunes(cell, widths[j]), widths[j]+gap))
+		}
+		font := pdfFontTable
+		if header && i == 0 {
+			font = pdfFontTableB
+		}
+		d.line(font, strings.TrimRight(line.String(), " "))
+	}
+}
+
+// WriteTo writes the document as a PDF file
+func (d *PDFDocument) WriteTo(w io.Writer) (int64, error) {
+	if len(d.pages) == 0 {
+		d.page()
+	}
+
+	buffered := bufio.NewWriter(w)
+	out := &countingWriter{w: buffered}
+	offsets := []int64{}
+	object := func(body string) {
+		offsets = append(offsets, out.n)
+		fmt.Fprintf(out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	// Objects: 1 catalog, 2 page tree, then the fonts, then a page and its content per page
+	firstFont := 3
+	firstPage := firstFont + len(pdfFontResources)
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+	fonts := make([]string, len(pdfFontResources))
+	for i, font := range pdfFontResources {
+		fonts[i] = fmt.Sprintf("/%s %d 0 R", font.resource, firstFont+i)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	for _, font := range pdfFontResources {
+		object(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", font.baseFont))
+	}
+	for i, page := range d.pages {
+		if d.Footer != "" || len(d.pages) > 1 {
+			footer := strings.TrimSpace(fmt.Sprintf("%s  %d / %d", d.Footer, i+1, len(d.pages)))
+			writeText(page, pdfFontFooter, pdfMargin, pdfMargin/2, footer)
+		}
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << %s >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, strings.Join(fonts, " "), firstPage+2*i+1))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.Bytes()))
+	}
+
+	xref := out.n
+	fmt.Fprintf(out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	if err := buffered.Flush(); err != nil {
+		return out.n, err
+	}
+	return out.n, out.err
+}
+
+// NewPDFDocumentFromLayout lays out a document described one block per line, as produced by report
+// templates. Lines are trimmed and blank lines ignored; a line starting with
+//
+//	"= "   is a title
+//	"== "  is a heading
+//	"| "   is a table row with cells separated by " | " ("|! " for a header row)
+//	"---"  is a horizontal rule
+//	"~"    is vertical space
+//
+// and any other line is a paragraph. Consecutive table rows form one table.
+func NewPDFDocumentFromLayout(layout string) *PDFDocument {
+	d := NewPDFDocument()
+	var table [][]string
+	header := false
+	flush := func() {
+		d.Table(table, header)
+		table, header = nil, false
+	}
+
+	for _, line := range strings.Split(layout, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "|") {
+			if strings.HasPrefix(line, "|!") {
+				if len(table) > 0 {
+					flush()
+				}
+				header = true
+				line = line[1:]
+			}
+			cells := strings.Split(strings.TrimSpace(line[1:]), " | ")
+			for i := range cells {
+				cells[i] = strings.TrimSpace(cells[i])
+			}
+			table = append(table, cells)
+			continue
+		}
+		if len(table) > 0 {
+			flush()
+		}
+
+		switch {
+		case strings.HasPrefix(line, "== "):
+			d.Heading(line[3:])
+		case strings.HasPrefix(line, "= "):
+			d.Title(line[2:])
+		case line == "---":
+			d.Rule()
+		case line == "~":
+			d.Space()
+		default:
+			d.Paragraph(line)
+		}
+	}
+	if len(table) > 0 {
+		flush()
+	}
+	return d
+}
+
+// page returns the current page, starting the first one if needed
+func (d *PDFDocument) page() *bytes.Buffer {
+	if len(d.pages) == 0 {
+		d.newPage()
+	}
+	return d.pages[len(d.pages)-1]
+}
+
+func (d *PDFDocument) newPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.y = pdfPageHeight - pdfMargin
+}
+
+// ensure starts a new page unless height points fit above the bottom margin
+func (d *PDFDocument) ensure(height float64) {
+	if len(d.pages) == 0 || d.y-height < pdfMargin {
+		d.newPage()
+	}
+}
+
+func (d *PDFDocument) space(height float64) {
+	if len(d.pages) > 0 && d.y < pdfPageHeight-pdfMargin {
+		d.y -= height
+	}
+}
+
+// line writes one line of text and moves to the next
+func (d *PDFDocument) line(font pdfFont, text string) {
+	d.ensure(font.leading)
+	d.y -= font.size
+	writeText(d.page(), font, pdfMargin, d.y, text)
+	d.y -= font.leading - font.size
+}
+
+// wrapped writes text broken into lines at spaces so each fits the page width
+func (d *PDFDocument) wrapped(font pdfFont, text string) {
+	words := strings.Fields(text)
+	current := ""
+	for _, word := range words {
+		candidate := word
+		if current != "" {
+			candidate = current + " " + word
+		}
+		if current != "" && textWidth(font, candidate) > pdfTextWidth {
+			d.line(font, current)
+			candidate = word
+		}
+		current = candidate
+	}
+	if current != "" {
+		d.line(font, current)
+	}
+}
+
+// writeText draws text with its baseline at x, y
+func writeText(page *bytes.Buffer, font pdfFont, x, y float64, text string) {
+	fmt.Fprintf(page, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font.resource, font.size, x, y, pdfString(text))
+}
+
+// pdfString encodes text for a PDF string literal in WinAnsiEncoding. Characters outside the
+// encoding are replaced with "?".
+func pdfString(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		c, ok := winAnsi(r)
+		if !ok {
+			c = '?'
+		}
+		switch c {
+		case '(', ')', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			if c < 32 {
+				c = ' '
+			}
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// winAnsiSpecials are the characters WinAnsiEncoding places in 0x80-0x9F
+var winAnsiSpecials = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, 'ˆ': 0x88, '‰': 0x89,
+	'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95,
+	'–': 0x96, '—': 0x97, '˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B, 'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+func winAnsi(r rune) (byte, bool) {
+	if r < 0x80 || (r >= 0xA0 && r <= 0xFF) {
+		return byte(r), true
+	}
+	c, ok := winAnsiSpecials[r]
+	return c, ok
+}
+
+// helveticaWidths are the Helvetica glyph widths (1/1000 em) of the printable ASCII characters
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space to /
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556, // 0 to ?
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778, // @ to O
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556, // P to _
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556, // ` to o
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584, // p to ~
+}
+
+// textWidth estimates the width of text in points. Bold Helvetica is taken as 5% wider and
+// characters outside ASCII as wide as a digit.
+func textWidth(font pdfFont, text string) float64 {
+	if font.mono {
+		return float64(len([]rune(text))) * 600 * font.size / 1000
+	}
+	units := 0
+	for _, r := range text {
+		if r >= 32 && r < 127 {
+			units += helveticaWidths[r-32]
+		} else {
+			units += 556
+		}
+	}
+	width := float64(units) * font.size / 1000
+	if strings.HasSuffix(font.baseFont, "-Bold") {
+		width *= 1.05
+	}
+	return width
+}
+
+func truncateRunes(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	if max <= 3 {
+		return string(runes[:max])
+	}
+	return string(runes[:max-3]) + "..."
+}
+
+func padRight(text string, width int) string {
+	if n := len([]rune(text)); n < width {
+		return text + strings.Repeat(" ", width-n)
+	}
+	return text
+}
+
+// countingWriter tracks the bytes written, for the cross-reference table, and the first error
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
+
+func (c *countingWriter) WriteString(s string) (int, error) {
+	return c.Write([]byte(s))
+}
@@ -0,0 +1,189 @@
+package utils
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Export formats supported by NewTableWriter
+const (
+	TableFormatCSV  = "csv"
+	TableFormatXLSX = "xlsx"
+)
+
+// TableWriter streams rows of a table to a file format. Cells are strings, float64 or nil (empty).
+type TableWriter interface {
+	WriteRow(cells []interface{}) error
+	// Close flushes the remaining output; the table is incomplete until it is called
+	Close() error
+}
+
+// NewTableWriter creates a TableWriter for the given format
+func NewTableWriter(format string, w io.Writer) (TableWriter, error) {
+	switch format {
+	case TableFormatCSV:
+		return NewCSVTableWriter(w), nil
+	case TableFormatXLSX:
+		return NewXLSXTableWriter(w)
+	default:
+		return nil, fmt.Errorf("invalid format: must be %s or %s", TableFormatCSV, TableFormatXLSX)
+	}
+}
+
+// TableContentType returns the MIME type of a table format
+func TableContentType(format string) string {
+	if format == TableFormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// CSVTableWriter writes a table as CSV
+type CSVTableWriter struct {
+	writer *csv.Writer
+	record []string
+}
+
+// NewCSVTableWriter creates a new CSVTableWriter
+func NewCSVTableWriter(w io.Writer) *CSVTableWriter {
+	return &CSVTableWriter{writer: csv.NewWriter(w)}
+}
+
+// WriteRow writes one CSV record. Text that a spreadsheet would evaluate as a formula is prefixed
+// with a quote so it is shown as text.
+func (t *CSVTableWriter) WriteRow(cells []interface{}) error {
+	t.record = t.record[:0]
+	for _, cell := range cells {
+		switch v := cell.(type) {
+		case nil:
+			t.record = append(t.record, "")
+		case float64:
+			t.record = append(t.record, strconv.FormatFloat(v, 'f', -1, 64))
+		case string:
+			if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+				v = "'" + v
+			}
+			t.record = append(t.record, v)
+		default:
+			t.record = append(t.record, fmt.Sprint(v))
+		}
+	}
+	return t.writer.Write(t.record)
+}
+
+// Close flushes the buffered records
+func (t *CSVTableWriter) Close() error {
+	t.writer.Flush()
+	return t.writer.Error()
+}
+
+// XLSXTableWriter writes a table as a single-sheet XLSX workbook. The sheet is streamed into the
+// archive row by row, with text stored inline so no shared string table has to be kept in memory.
+type XLSXTableWriter struct {
+	archive *zip.Writer
+	sheet   *bufio.Writer
+	rows    int
+}
+
+// xlsxStaticParts are the workbook parts that do not depend on the data, in archive order
+var xlsxStaticParts = []struct{ name, content string }{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets>` +
+		`</workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+		`</Relationships>`},
+	{"xl/styles.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<fonts count="1"><font><sz val="11"/><name val="Calibri"/></font></fonts>` +
+		`<fills count="1"><fill><patternFill patternType="none"/></fill></fills>` +
+		`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+		`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+		`<cellXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/></cellXfs>` +
+		`</styleSheet>`},
+}
+
+// NewXLSXTableWriter writes the workbook parts and opens the sheet for rows
+func NewXLSXTableWriter(w io.Writer) (*XLSXTableWriter, error) {
+	archive := zip.NewWriter(w)
+	for _, part := range xlsxStaticParts {
+		f, err := archive.Create(part.name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", part.name, err)
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", part.name, err)
+		}
+	}
+
+	f, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to write sheet: %w", err)
+	}
+	sheet := bufio.NewWriter(f)
+	sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	return &XLSXTableWriter{archive: archive, sheet: sheet}, nil
+}
+
+// WriteRow appends a row to the sheet. Numbers are stored as numbers, everything else as text.
+func (t *XLSXTableWriter) WriteRow(cells []interface{}) error {
+	t.rows++
+	fmt.Fprintf(t.sheet, `<row r="%d">`, t.rows)
+	for i, cell := range cells {
+		ref := xlsxColumnName(i) + strconv.Itoa(t.rows)
+		switch v := cell.(type) {
+		case nil:
+		case float64:
+			fmt.Fprintf(t.sheet, `<c r="%s"><v>%s</v></c>`, ref, strconv.FormatFloat(v, 'g', -1, 64))
+		default:
+			fmt.Fprintf(t.sheet, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref)
+			if err := xml.EscapeText(t.sheet, []byte(fmt.Sprint(v))); err != nil {
+				return fmt.Errorf("failed to write cell %s: %w", ref, err)
+			}
+			t.sheet.WriteString(`</t></is></c>`)
+		}
+	}
+	_, err := t.sheet.WriteString(`</row>`)
+	return err
+}
+
+// Close ends the sheet and writes the archive directory
+func (t *XLSXTableWriter) Close() error {
+	t.sheet.WriteString(`</sheetData></worksheet>`)
+	if err := t.sheet.Flush(); err != nil {
+		return fmt.Errorf("failed to write sheet: %w", err)
+	}
+	return t.archive.Close()
+}
+
+// xlsxColumnName converts a zero-based column index to its spreadsheet name (A, B, ..., Z, AA, ...)
+func xlsxColumnName(index int) string {
+	name := ""
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = string(rune('A'+(index-1)%26)) + name
+	}
+	return name
+}
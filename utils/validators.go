@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"questionarie-service/models"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -168,14 +169,31 @@ func ValidateUniqueStrings(arr []string, fieldName string) error {
 	return nil
 }
 
-// ValidateQuestionType validates question type
+// ValidateQuestionType validates question type against the registered question types
 func ValidateQuestionType(qType string) error {
-	allowedTypes := []string{"multiple_choice", "likert_scale", "free_text", "yes_no"}
-	return ValidateEnum(qType, allowedTypes, "question_type")
+	return ValidateEnum(qType, models.QuestionTypeNames(), "question_type")
+}
+
+// ValidateQuestionnaireStatus validates questionnaire lifecycle status
+func ValidateQuestionnaireStatus(status string) error {
+	allowedStatuses := []string{"draft", "published", "archived"}
+	return ValidateEnum(status, allowedStatuses, "status")
 }
 
 // ValidateAssignmentStatus validates assignment status
 func ValidateAssignmentStatus(status string) error {
-	allowedStatuses := []string{"pending", "in_progress", "completed"}
+	allowedStatuses := []string{"pending", "in_progress", "completed", "expired"}
+	return ValidateEnum(status, allowedStatuses, "status")
+}
+
+// ValidateWebhookDeliveryStatus validates webhook delivery status
+func ValidateWebhookDeliveryStatus(status string) error {
+	allowedStatuses := []string{"pending", "delivered", "failed"}
+	return ValidateEnum(status, allowedStatuses, "status")
+}
+
+// ValidateNotificationStatus validates notification status
+func ValidateNotificationStatus(status string) error {
+	allowedStatuses := []string{"pending", "sent", "failed"}
 	return ValidateEnum(status, allowedStatuses, "status")
 }
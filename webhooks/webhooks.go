@@ -0,0 +1,95 @@
+// Package webhooks signs and posts outbound webhook payloads
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Headers set on every delivery
+const (
+	SignatureHeader = "X-Webhook-Signature" // t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>">
+	EventHeader     = "X-Webhook-Event"
+	EventIDHeader   = "X-Webhook-Event-Id"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// Sign returns the signature header value of body sent at timestamp. Receivers recompute the
+// HMAC-SHA256 of "<t>.<body>" with the shared secret and compare it with v1; the timestamp lets them
+// reject old replays.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(t))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return fmt.Sprintf("t=%s,v1=%s", t, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// GenerateSecret returns a random secret for a new subscription
+func GenerateSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// Request is one signed delivery of an event
+type Request struct {
+	URL        string
+	Secret     string
+	EventType  string
+	EventID    string
+	DeliveryID string
+	Body       []byte
+}
+
+// Sender posts signed webhook requests
+type Sender struct {
+	client *http.Client
+}
+
+// NewSender creates a Sender whose requests time out after timeout
+func NewSender(timeout time.Duration) *Sender {
+	return &Sender{client: &http.Client{
+		Timeout: timeout,
+		// A redirect would post the payload somewhere the subscription did not name
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}}
+}
+
+// Send posts a request and returns the response status code. Any status other than 2xx is an error.
+func (s *Sender) Send(ctx context.Context, request Request) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, request.URL, bytes.NewReader(request.Body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "questionarie-service-webhooks")
+	req.Header.Set(EventHeader, request.EventType)
+	req.Header.Set(EventIDHeader, request.EventID)
+	req.Header.Set(DeliveryHeader, request.DeliveryID)
+	req.Header.Set(SignatureHeader, Sign(request.Secret, time.Now(), request.Body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}